@@ -0,0 +1,194 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"deep-thinking-agent/cmd/common"
+)
+
+func runCollections(args []string) error {
+	fs := flag.NewFlagSet("collections", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to configuration file")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: deep-thinking-agent collections [options] <subcommand> [args]
+
+Manage vector store collections.
+
+Subcommands:
+  list                     List all collections
+  create [-dimension N] <name>
+                           Create a collection (dimension defaults to the embedder's)
+  delete <name>            Delete a collection and all of its documents
+  info [name]              Show details of a collection (default from config)
+
+Options:
+  -config string
+        Path to configuration file (default "config.json")
+
+Examples:
+  # List collections
+  deep-thinking-agent collections list
+
+  # Create a collection for research papers
+  deep-thinking-agent collections create research
+
+  # Show document and chunk counts
+  deep-thinking-agent collections info research
+`)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return fmt.Errorf("subcommand is required")
+	}
+
+	// Load configuration
+	config, err := common.LoadConfig(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Initialize system
+	system, err := common.InitializeSystem(config)
+	if err != nil {
+		return fmt.Errorf("failed to initialize system: %w", err)
+	}
+	defer system.Close()
+
+	ctx := context.Background()
+	subcommand := fs.Arg(0)
+	subArgs := fs.Args()[1:]
+
+	switch subcommand {
+	case "list":
+		return listCollections(ctx, system)
+	case "create":
+		return createCollection(ctx, system, subArgs)
+	case "delete":
+		return deleteCollection(ctx, system, subArgs)
+	case "info":
+		return collectionInfo(ctx, system, subArgs)
+	default:
+		return fmt.Errorf("unknown subcommand: %s", subcommand)
+	}
+}
+
+func listCollections(ctx context.Context, system *common.System) error {
+	collections, err := system.VectorStore.ListCollections(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(collections) == 0 {
+		fmt.Println("No collections found.")
+		return nil
+	}
+
+	for _, collection := range collections {
+		marker := ""
+		if collection.Name == system.Collection("") {
+			marker = " (default)"
+		}
+		fmt.Printf("%s%s\n", collection.Name, marker)
+	}
+
+	return nil
+}
+
+func createCollection(ctx context.Context, system *common.System, args []string) error {
+	fs := flag.NewFlagSet("collections create", flag.ExitOnError)
+	dimension := fs.Int("dimension", 0, "Vector dimension (default from the embedder)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		return fmt.Errorf("collection name is required")
+	}
+
+	name := fs.Arg(0)
+	if *dimension <= 0 {
+		*dimension = system.Embedder.Dimensions()
+	}
+
+	if err := system.VectorStore.CreateCollection(ctx, name, *dimension, nil); err != nil {
+		return err
+	}
+
+	fmt.Printf("Created collection %s (dimension %d)\n", name, *dimension)
+	return nil
+}
+
+func deleteCollection(ctx context.Context, system *common.System, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("collection name is required")
+	}
+	name := args[0]
+
+	// Note the collection's documents so their stored schemas can be removed
+	documents, err := system.ListDocuments(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if err := system.VectorStore.DeleteCollection(ctx, name); err != nil {
+		return err
+	}
+
+	for _, doc := range documents {
+		if err := system.SchemaStore.Delete(ctx, doc.DocID); err != nil {
+			return err
+		}
+	}
+
+	// Forget the collection's documents so they are re-ingested next time
+	manifestPath := resolveManifestPath("", system.Config)
+	manifest, err := common.LoadIngestManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+	manifest.RemoveCollection(name)
+	if err := manifest.Save(manifestPath); err != nil {
+		return err
+	}
+
+	fmt.Printf("Deleted collection %s\n", name)
+	return nil
+}
+
+func collectionInfo(ctx context.Context, system *common.System, args []string) error {
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	}
+	name = system.Collection(name)
+
+	info, err := system.VectorStore.GetCollection(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	documents, err := system.ListDocuments(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Collection: %s\n", info.Name)
+	fmt.Printf("  Vector dimension: %d\n", info.VectorDimension)
+	fmt.Printf("  Documents: %d\n", len(documents))
+	fmt.Printf("  Chunks: %d\n", info.DocumentCount)
+
+	return nil
+}
@@ -157,7 +157,7 @@ func validateConfig(args []string) error {
 	if config.VectorStore.Type == "" {
 		errors = append(errors, "vector_store.type is required")
 	}
-	if config.VectorStore.Address == "" {
+	if config.VectorStore.Address == "" && config.VectorStore.Type != "memory" {
 		errors = append(errors, "vector_store.address is required")
 	}
 
@@ -0,0 +1,246 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+
+	"deep-thinking-agent/cmd/common"
+)
+
+func runDocs(args []string) error {
+	fs := flag.NewFlagSet("docs", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to configuration file")
+	collection := fs.String("collection", "", "Collection name (default from config)")
+	manifest := fs.String("manifest", "", "Path to the ingest manifest (default from config)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: deep-thinking-agent docs [options] <subcommand> [args]
+
+Manage ingested documents. Documents are identified by their doc_id, the
+path they were ingested from.
+
+Subcommands:
+  list                        List documents with their chunk counts
+  show [-chunks] <doc-id>     Show a document's metadata (and chunk text)
+  delete <doc-id>             Delete all chunks of a document
+  reingest [-no-schema] <doc-id>
+                              Parse and ingest a document again from its path
+
+Options:
+  -config string
+        Path to configuration file (default "config.json")
+  -collection string
+        Collection name (default from config)
+  -manifest string
+        Path to the ingest manifest (default from config, or ".ingest-manifest.json")
+
+Examples:
+  # List documents in the default collection
+  deep-thinking-agent docs list
+
+  # Show the chunks of a document
+  deep-thinking-agent docs show -chunks ./documents/report.pdf
+
+  # Remove a document from the research collection
+  deep-thinking-agent docs -collection research delete ./papers/old.pdf
+`)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return fmt.Errorf("subcommand is required")
+	}
+
+	// Load configuration
+	config, err := common.LoadConfig(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if *collection != "" {
+		config.VectorStore.DefaultCollection = *collection
+	}
+
+	// Initialize system
+	system, err := common.InitializeSystem(config)
+	if err != nil {
+		return fmt.Errorf("failed to initialize system: %w", err)
+	}
+	defer system.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	manifestPath := resolveManifestPath(*manifest, config)
+	subcommand := fs.Arg(0)
+	subArgs := fs.Args()[1:]
+
+	switch subcommand {
+	case "list":
+		return listDocuments(ctx, system)
+	case "show":
+		return showDocument(ctx, system, subArgs)
+	case "delete":
+		return deleteDocument(ctx, system, manifestPath, subArgs)
+	case "reingest":
+		return reingestDocument(ctx, system, manifestPath, subArgs)
+	default:
+		return fmt.Errorf("unknown subcommand: %s", subcommand)
+	}
+}
+
+func listDocuments(ctx context.Context, system *common.System) error {
+	documents, err := system.ListDocuments(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	if len(documents) == 0 {
+		fmt.Printf("No documents in collection %s.\n", system.Collection(""))
+		return nil
+	}
+
+	for _, doc := range documents {
+		fmt.Printf("%s\t%s\t%d chunks", doc.DocID, doc.Format, doc.Chunks)
+		if doc.Title != "" {
+			fmt.Printf("\t%s", doc.Title)
+		}
+		fmt.Println()
+	}
+	fmt.Printf("\n%d documents in collection %s\n", len(documents), system.Collection(""))
+
+	return nil
+}
+
+func showDocument(ctx context.Context, system *common.System, args []string) error {
+	fs := flag.NewFlagSet("docs show", flag.ExitOnError)
+	showChunks := fs.Bool("chunks", false, "Print the text of each chunk")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		return fmt.Errorf("doc-id is required")
+	}
+	docID := fs.Arg(0)
+
+	chunks, err := system.DocumentChunks(ctx, "", docID)
+	if err != nil {
+		return err
+	}
+	if len(chunks) == 0 {
+		return fmt.Errorf("document not found: %s", docID)
+	}
+
+	fmt.Printf("Document: %s\n", docID)
+	fmt.Printf("  Collection: %s\n", system.Collection(""))
+	fmt.Printf("  Chunks: %d\n", len(chunks))
+
+	// Document-level metadata is copied onto every chunk
+	metadata := chunks[0].Metadata
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		switch key {
+		case "doc_id", "chunk_index", "start_pos", "end_pos", "section_id", "section_title", "section_type", "hierarchy_path", "semantic_tags":
+			continue // Identity or per-chunk fields
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Printf("  %s: %v\n", key, metadata[key])
+	}
+
+	if *showChunks {
+		for i, chunk := range chunks {
+			fmt.Printf("\n--- Chunk %d", i)
+			if sectionType, ok := chunk.Metadata["section_type"].(string); ok && sectionType != "" {
+				fmt.Printf(" (%s)", sectionType)
+			}
+			fmt.Println(" ---")
+			fmt.Println(chunk.Content)
+		}
+	}
+
+	return nil
+}
+
+func deleteDocument(ctx context.Context, system *common.System, manifestPath string, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("doc-id is required")
+	}
+	docID := args[0]
+
+	deleted, err := system.DeleteDocument(ctx, "", docID)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := common.LoadIngestManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+	manifest.Remove(system.Collection(""), docID)
+	if err := manifest.Save(manifestPath); err != nil {
+		return err
+	}
+
+	fmt.Printf("Deleted %s (%d chunks)\n", docID, deleted)
+	return nil
+}
+
+func reingestDocument(ctx context.Context, system *common.System, manifestPath string, args []string) error {
+	fs := flag.NewFlagSet("docs reingest", flag.ExitOnError)
+	noSchema := fs.Bool("no-schema", false, "Skip schema derivation, use simple chunking")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		return fmt.Errorf("doc-id is required")
+	}
+	docID := fs.Arg(0)
+
+	manifest, err := common.LoadIngestManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	opts := common.IngestOptions{
+		DeriveSchema: !*noSchema,
+		Manifest:     manifest,
+	}
+
+	var result common.IngestResult
+	err = system.IngestFiles(ctx, []string{docID}, opts, func(r common.IngestResult) {
+		result = r
+	})
+	if err != nil {
+		return err
+	}
+
+	// Record the new content hash before reporting any error
+	if err := manifest.Save(manifestPath); err != nil {
+		return err
+	}
+
+	switch {
+	case result.Err != nil:
+		return result.Err
+	case result.Unsupported:
+		return fmt.Errorf("%w: %s", common.ErrUnsupportedFormat, docID)
+	}
+
+	fmt.Printf("Re-ingested %s (%d chunks)\n", docID, result.Chunks)
+	return nil
+}
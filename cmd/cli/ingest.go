@@ -10,8 +10,9 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
-	"strings"
+	"time"
 
 	"deep-thinking-agent/cmd/common"
 )
@@ -20,15 +21,19 @@ func runIngest(args []string) error {
 	fs := flag.NewFlagSet("ingest", flag.ExitOnError)
 	configPath := fs.String("config", "config.json", "Path to configuration file")
 	recursive := fs.Bool("recursive", false, "Recursively process directories")
-	collection := fs.String("collection", "documents", "Target collection name")
+	collection := fs.String("collection", "", "Target collection name (default from config)")
 	deriveSchema := fs.Bool("derive-schema", true, "Derive document schema using LLM (default true)")
 	noSchema := fs.Bool("no-schema", false, "Skip schema derivation, use simple chunking")
 	verbose := fs.Bool("verbose", false, "Show detailed processing information")
+	incremental := fs.Bool("incremental", false, "Skip files whose content is unchanged since the last ingest")
+	manifest := fs.String("manifest", "", "Path to the ingest manifest (default from config)")
+	workers := fs.Int("workers", 0, "Number of files to ingest concurrently (default from config)")
 
 	fs.Usage = func() {
 		fmt.Fprintf(os.Stderr, `Usage: deep-thinking-agent ingest [options] <file-or-directory>...
 
 Ingest documents into the vector store with schema analysis.
+Supported formats: plain text (.txt), Markdown (.md), PDF (.pdf) and HTML (.html, .htm).
 
 Options:
   -config string
@@ -36,13 +41,22 @@ Options:
   -recursive
         Recursively process directories
   -collection string
-        Target collection name (default "documents")
+        Target collection name (default from config)
   -derive-schema
         Derive document schema using LLM (default true)
   -no-schema
         Skip schema derivation, use simple paragraph-based chunking
   -verbose
         Show detailed processing information
+  -incremental
+        Skip files whose content hash matches the ingest manifest
+  -manifest string
+        Path to the ingest manifest (default from config, or ".ingest-manifest.json")
+  -workers int
+        Number of files to ingest concurrently (default from config, or 4)
+
+Re-ingesting a file replaces its chunks, so repeated runs do not duplicate documents.
+Files are ingested concurrently; a file that fails is reported and the rest continue.
 
 Examples:
   # Ingest with schema analysis (default)
@@ -56,6 +70,9 @@ Examples:
 
   # Ingest with custom collection
   deep-thinking-agent ingest -collection research_papers ./papers
+
+  # Only ingest new or changed files
+  deep-thinking-agent ingest -incremental -recursive ./documents
 `)
 	}
 
@@ -72,6 +89,9 @@ Examples:
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
+	if *collection != "" {
+		config.VectorStore.DefaultCollection = *collection
+	}
 
 	// Initialize system
 	system, err := common.InitializeSystem(config)
@@ -80,117 +100,155 @@ Examples:
 	}
 	defer system.Close()
 
-	ctx := context.Background()
+	// Ctrl-C stops ingestion and cancels in-flight requests
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
-	// Process each path
-	var totalFiles, totalChunks int
-	// If -no-schema is set, override -derive-schema
-	useSchema := *deriveSchema && !*noSchema
-	for _, path := range fs.Args() {
-		files, chunks, err := processPath(ctx, system, path, *recursive, *collection, useSchema, *verbose)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to process %s: %v\n", path, err)
-			continue
-		}
-		totalFiles += files
-		totalChunks += chunks
+	manifestPath := resolveManifestPath(*manifest, config)
+	ingestManifest, err := common.LoadIngestManifest(manifestPath)
+	if err != nil {
+		return err
 	}
 
-	fmt.Printf("\nIngestion complete:\n")
-	fmt.Printf("  Files processed: %d\n", totalFiles)
-	fmt.Printf("  Chunks created: %d\n", totalChunks)
-	fmt.Printf("  Collection: %s\n", *collection)
+	files := collectFiles(fs.Args(), *recursive)
+	if len(files) == 0 {
+		return fmt.Errorf("no files found to ingest")
+	}
 
-	return nil
-}
+	opts := common.IngestOptions{
+		DeriveSchema: *deriveSchema && !*noSchema, // -no-schema overrides -derive-schema
+		Workers:      *workers,
+		Manifest:     ingestManifest,
+		Incremental:  *incremental,
+	}
 
-func processPath(ctx context.Context, system *common.System, path string, recursive bool, collection string, deriveSchema bool, verbose bool) (int, int, error) {
-	info, err := os.Stat(path)
-	if err != nil {
-		return 0, 0, err
+	// Ingest files concurrently; failures are reported per file
+	progress := &ingestProgress{total: len(files), verbose: *verbose}
+	start := time.Now()
+	runErr := system.IngestFiles(ctx, files, opts, progress.report)
+
+	// Record what was ingested, even if the run was interrupted
+	if err := ingestManifest.Save(manifestPath); err != nil {
+		return err
+	}
+	if runErr != nil {
+		return fmt.Errorf("ingestion cancelled after %d of %d files: %w", progress.done, len(files), runErr)
 	}
 
-	if info.IsDir() {
-		return processDirectory(ctx, system, path, recursive, collection, deriveSchema, verbose)
+	fmt.Printf("\nIngestion complete:\n")
+	fmt.Printf("  Files processed: %d\n", progress.ingested)
+	if *incremental {
+		fmt.Printf("  Files unchanged: %d\n", progress.unchanged)
 	}
+	if progress.failed > 0 {
+		fmt.Printf("  Files failed: %d\n", progress.failed)
+	}
+	fmt.Printf("  Chunks created: %d\n", progress.chunks)
+	fmt.Printf("  Collection: %s\n", system.Collection(""))
+	fmt.Printf("  Elapsed: %s\n", time.Since(start).Round(time.Millisecond))
 
-	return processFile(ctx, system, path, collection, deriveSchema, verbose)
+	return nil
 }
 
-func processDirectory(ctx context.Context, system *common.System, dirPath string, recursive bool, collection string, deriveSchema bool, verbose bool) (int, int, error) {
-	var totalFiles, totalChunks int
+// progressInterval is how often, in files, a progress line is printed when
+// not in verbose mode.
+const progressInterval = 100
 
-	entries, err := os.ReadDir(dirPath)
-	if err != nil {
-		return 0, 0, err
-	}
+// ingestProgress prints per-file results and keeps the run totals.
+type ingestProgress struct {
+	total   int
+	verbose bool
 
-	for _, entry := range entries {
-		fullPath := filepath.Join(dirPath, entry.Name())
+	done      int
+	ingested  int
+	unchanged int
+	failed    int
+	chunks    int
+}
 
-		if entry.IsDir() {
-			if recursive {
-				files, chunks, err := processDirectory(ctx, system, fullPath, recursive, collection, deriveSchema, verbose)
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "Warning: failed to process directory %s: %v\n", fullPath, err)
-					continue
-				}
-				totalFiles += files
-				totalChunks += chunks
-			}
-			continue
-		}
+func (p *ingestProgress) report(result common.IngestResult) {
+	p.done++
 
-		files, chunks, err := processFile(ctx, system, fullPath, collection, deriveSchema, verbose)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to process file %s: %v\n", fullPath, err)
-			continue
+	switch {
+	case result.Err != nil:
+		p.failed++
+		fmt.Fprintf(os.Stderr, "Warning: failed to process %s: %v\n", result.Path, result.Err)
+	case result.Unsupported:
+		if p.verbose {
+			fmt.Printf("[%d/%d] Skipping unsupported file: %s\n", p.done, p.total, result.Path)
+		}
+	case result.Unchanged:
+		p.unchanged++
+		if p.verbose {
+			fmt.Printf("[%d/%d] Unchanged: %s\n", p.done, p.total, result.Path)
+		}
+	default:
+		p.ingested++
+		p.chunks += result.Chunks
+		if p.verbose {
+			fmt.Printf("[%d/%d] Processed: %s (%s, %d chunks, %s)\n",
+				p.done, p.total, result.Path, result.Format, result.Chunks, result.Duration.Round(time.Millisecond))
+			if result.Title != "" {
+				fmt.Printf("  Title: %s\n", result.Title)
+			}
 		}
-		totalFiles += files
-		totalChunks += chunks
 	}
 
-	return totalFiles, totalChunks, nil
+	if !p.verbose && (p.done%progressInterval == 0 || p.done == p.total) {
+		fmt.Fprintf(os.Stderr, "Progress: %d/%d files\n", p.done, p.total)
+	}
 }
 
-func processFile(ctx context.Context, system *common.System, filePath string, collection string, deriveSchema bool, verbose bool) (int, int, error) {
-	// Check if file extension is supported
-	ext := strings.ToLower(filepath.Ext(filePath))
-	supportedExts := []string{".txt", ".md", ".markdown"}
-	supported := false
-	for _, supportedExt := range supportedExts {
-		if ext == supportedExt {
-			supported = true
-			break
+// collectFiles expands the given paths into the list of files to ingest.
+// Directories are listed, and walked into if recursive is set. Paths that
+// cannot be read are reported and skipped.
+func collectFiles(paths []string, recursive bool) []string {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to process %s: failed to stat path: %v\n", path, err)
+			continue
 		}
-	}
 
-	if !supported {
-		if verbose {
-			fmt.Printf("Skipping unsupported file: %s\n", filePath)
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
 		}
-		return 0, 0, nil
-	}
 
-	if verbose {
-		fmt.Printf("Processing: %s\n", filePath)
+		files = append(files, collectDirectory(path, recursive)...)
 	}
+	return files
+}
 
-	// Read file
-	content, err := os.ReadFile(filePath)
+func collectDirectory(dirPath string, recursive bool) []string {
+	entries, err := os.ReadDir(dirPath)
 	if err != nil {
-		return 0, 0, fmt.Errorf("failed to read file: %w", err)
+		fmt.Fprintf(os.Stderr, "Warning: failed to process directory %s: %v\n", dirPath, err)
+		return nil
 	}
 
-	// Ingest document
-	chunks, err := system.IngestDocument(ctx, filePath, string(content), deriveSchema)
-	if err != nil {
-		return 0, 0, fmt.Errorf("failed to ingest: %w", err)
-	}
+	var files []string
+	for _, entry := range entries {
+		fullPath := filepath.Join(dirPath, entry.Name())
+
+		if entry.IsDir() {
+			if recursive {
+				files = append(files, collectDirectory(fullPath, recursive)...)
+			}
+			continue
+		}
 
-	if verbose {
-		fmt.Printf("  Created %d chunks\n", chunks)
+		files = append(files, fullPath)
 	}
+	return files
+}
 
-	return 1, chunks, nil
+// resolveManifestPath returns the ingest manifest path from the flag value,
+// falling back to the configuration and then the default path.
+func resolveManifestPath(flagValue string, config *common.Config) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	return config.Ingest.ManifestFile()
 }
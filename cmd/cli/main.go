@@ -29,6 +29,16 @@ func main() {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
+	case "collections":
+		if err := runCollections(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	case "docs":
+		if err := runDocs(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 	case "config":
 		if err := runConfig(os.Args[2:]); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -54,6 +64,8 @@ Usage:
 Commands:
   query       Execute a deep thinking query
   ingest      Ingest documents into the system
+  collections Manage vector store collections
+  docs        List, inspect, delete and re-ingest documents
   config      Manage configuration
   version     Print version information
   help        Show this help message
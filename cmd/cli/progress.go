@@ -0,0 +1,128 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"deep-thinking-agent/pkg/workflow"
+)
+
+// nodeActivities describes what each workflow node is doing while it runs.
+var nodeActivities = map[string]string{
+	"planner":     "planning",
+	"rewriter":    "rewriting query",
+	"supervisor":  "selecting strategy",
+	"retriever":   "retrieving",
+	"reranker":    "reranking",
+	"distiller":   "distilling",
+	"reflector":   "reflecting",
+	"policy":      "deciding next step",
+	"synthesizer": "synthesizing answer",
+}
+
+// workflowNodes run outside the per-step pipeline, so their events are not
+// attributed to a plan step.
+var workflowNodes = map[string]bool{
+	"planner":     true,
+	"policy":      true,
+	"synthesizer": true,
+}
+
+// progressReporter prints live workflow progress from executor events.
+// Verbose mode adds timings, step results and policy decisions.
+type progressReporter struct {
+	out     io.Writer
+	verbose bool
+}
+
+// newProgressReporter creates a reporter that writes to out.
+func newProgressReporter(out io.Writer, verbose bool) *progressReporter {
+	return &progressReporter{out: out, verbose: verbose}
+}
+
+// OnEvent implements workflow.Observer.
+func (p *progressReporter) OnEvent(event workflow.Event) {
+	switch event.Type {
+	case workflow.EventNodeStarted:
+		fmt.Fprintf(p.out, "%s%s…\n", stepPrefix(event), activity(event))
+
+	case workflow.EventNodeFinished:
+		if event.Err != nil {
+			fmt.Fprintf(p.out, "%s%s failed after %s: %v\n", stepPrefix(event), event.Node, formatDuration(event.Duration), event.Err)
+			return
+		}
+		if p.verbose && event.Node == "planner" && event.Delta != nil && event.Delta.PlanCreated {
+			fmt.Fprintf(p.out, "Planned %d steps in %s\n", event.TotalSteps, formatDuration(event.Duration))
+		}
+
+	case workflow.EventStepCompleted:
+		if !p.verbose {
+			return
+		}
+		docs := 0
+		if event.PastStep != nil {
+			docs = len(event.PastStep.RetrievedDocs)
+		}
+		fmt.Fprintf(p.out, "%scompleted in %s (%d documents)\n", stepPrefix(event), formatDuration(event.Duration), docs)
+
+	case workflow.EventPolicyDecision:
+		if !p.verbose || event.Decision == nil {
+			return
+		}
+		action := "finish"
+		if event.Decision.ShouldContinue && event.Decision.Replan {
+			action = "replan"
+		} else if event.Decision.ShouldContinue {
+			action = "continue"
+		}
+		fmt.Fprintf(p.out, "Policy: %s (confidence %.2f) - %s\n", action, event.Decision.Confidence, event.Decision.Reasoning)
+	}
+}
+
+// stepPrefix returns "Step n/m: " for events tied to a plan step.
+func stepPrefix(event workflow.Event) string {
+	if !isStepEvent(event) {
+		return ""
+	}
+	return fmt.Sprintf("Step %d/%d: ", event.StepPosition+1, event.TotalSteps)
+}
+
+// activity describes a started node, including the retrieval strategy
+// once the supervisor has selected one.
+func activity(event workflow.Event) string {
+	description, ok := nodeActivities[event.Node]
+	if !ok {
+		description = "running " + event.Node
+	}
+	if event.Node == "retriever" && event.Strategy != "" {
+		description = fmt.Sprintf("%s (%s)", description, event.Strategy)
+	}
+	if !isStepEvent(event) {
+		// Capitalize standalone activities such as planning
+		description = strings.ToUpper(description[:1]) + description[1:]
+	}
+	return description
+}
+
+// isStepEvent reports whether an event belongs to a specific plan step.
+func isStepEvent(event workflow.Event) bool {
+	if event.Type == workflow.EventStepCompleted {
+		return event.StepPosition >= 0
+	}
+	return event.StepPosition >= 0 && event.TotalSteps > 0 && !workflowNodes[event.Node]
+}
+
+// formatDuration rounds a duration for display.
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
@@ -11,6 +11,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 	"strings"
 
 	"deep-thinking-agent/cmd/common"
@@ -21,11 +22,15 @@ func runQuery(args []string) error {
 	fs := flag.NewFlagSet("query", flag.ExitOnError)
 	configPath := fs.String("config", "config.json", "Path to configuration file")
 	interactive := fs.Bool("interactive", false, "Run in interactive mode")
-	verbose := fs.Bool("verbose", false, "Show detailed execution information")
+	verbose := fs.Bool("verbose", false, "Show detailed execution information and timings")
 	maxIterations := fs.Int("max-iterations", 10, "Maximum number of reasoning iterations")
+	stream := fs.Bool("stream", true, "Stream the final answer as it is generated")
+	resume := fs.String("resume", "", "Resume a checkpointed run by its run ID")
+	collection := fs.String("collection", "", "Collection to search (default from config)")
 
 	fs.Usage = func() {
 		fmt.Fprintf(os.Stderr, `Usage: deep-thinking-agent query [options] <question>
+       deep-thinking-agent query [options] -resume <run-id>
 
 Execute a deep thinking query using multi-hop reasoning.
 
@@ -35,9 +40,16 @@ Options:
   -interactive
         Run in interactive mode for multiple queries
   -verbose
-        Show detailed execution information
+        Show detailed execution information and timings
   -max-iterations int
         Maximum number of reasoning iterations (default 10)
+  -stream
+        Stream the final answer as it is generated (default true)
+  -collection string
+        Collection to search (default from config)
+  -resume string
+        Resume a checkpointed run from its last completed node
+        (requires workflow.checkpoint_dir in the config)
 
 Examples:
   # Single query
@@ -48,6 +60,12 @@ Examples:
 
   # With custom config
   deep-thinking-agent query -config prod.json "Analyze the financial trends"
+
+  # Search a specific collection
+  deep-thinking-agent query -collection research "Which methods were compared?"
+
+  # Continue a run that failed or was interrupted
+  deep-thinking-agent query -resume 3f1c9a2e-5b7d-4e8f-9a0b-1c2d3e4f5a6b
 `)
 	}
 
@@ -60,6 +78,9 @@ Examples:
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
+	if *collection != "" {
+		config.VectorStore.DefaultCollection = *collection
+	}
 
 	// Initialize system
 	system, err := common.InitializeSystem(config)
@@ -68,8 +89,12 @@ Examples:
 	}
 	defer system.Close()
 
+	if *resume != "" {
+		return resumeQuery(system, *resume, *verbose, *stream)
+	}
+
 	if *interactive {
-		return runInteractiveQuery(system, *verbose, *maxIterations)
+		return runInteractiveQuery(system, *verbose, *maxIterations, *stream)
 	}
 
 	// Single query mode
@@ -78,10 +103,10 @@ Examples:
 	}
 
 	question := strings.Join(fs.Args(), " ")
-	return executeQuery(system, question, *verbose, *maxIterations)
+	return executeQuery(system, question, *verbose, *maxIterations, *stream)
 }
 
-func runInteractiveQuery(system *common.System, verbose bool, maxIterations int) error {
+func runInteractiveQuery(system *common.System, verbose bool, maxIterations int, stream bool) error {
 	fmt.Println("Deep Thinking Agent - Interactive Mode")
 	fmt.Println("Type 'exit' or 'quit' to exit")
 	fmt.Println()
@@ -104,7 +129,7 @@ func runInteractiveQuery(system *common.System, verbose bool, maxIterations int)
 			break
 		}
 
-		if err := executeQuery(system, question, verbose, maxIterations); err != nil {
+		if err := executeQuery(system, question, verbose, maxIterations, stream); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		}
 		fmt.Println()
@@ -117,37 +142,107 @@ func runInteractiveQuery(system *common.System, verbose bool, maxIterations int)
 	return nil
 }
 
-func executeQuery(system *common.System, question string, verbose bool, maxIterations int) error {
-	ctx := context.Background()
-
+func executeQuery(system *common.System, question string, verbose bool, maxIterations int, stream bool) error {
 	fmt.Printf("Question: %s\n\n", question)
 
+	// Create initial state
+	state := workflow.NewState(question)
+	state.MaxIterations = maxIterations
+
+	return runWorkflow(system, verbose, stream, func(ctx context.Context) (*workflow.State, error) {
+		return system.Executor.Execute(ctx, state)
+	})
+}
+
+// resumeQuery continues a checkpointed run and displays its results.
+func resumeQuery(system *common.System, runID string, verbose bool, stream bool) error {
+	fmt.Printf("Resuming run: %s\n\n", runID)
+
+	return runWorkflow(system, verbose, stream, func(ctx context.Context) (*workflow.State, error) {
+		return system.Executor.Resume(ctx, runID)
+	})
+}
+
+// runWorkflow executes a workflow run with progress reporting and answer
+// streaming, then displays the results.
+func runWorkflow(system *common.System, verbose bool, stream bool, execute func(ctx context.Context) (*workflow.State, error)) error {
+	// Ctrl-C cancels the query, including in-flight LLM and retrieval calls
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
 	if verbose {
 		fmt.Println("Executing deep thinking workflow...")
 		fmt.Println()
 	}
 
-	// Create initial state
-	state := workflow.NewState(question)
-	state.MaxIterations = maxIterations
+	// Print the final answer as it streams in, under the section header
+	// that would otherwise be printed after execution
+	streamed := false
+	if stream && system.Synthesizer != nil {
+		header := "Answer:"
+		if verbose {
+			header = "=== Final Answer ==="
+		}
+		headerPrinted := false
+		system.Synthesizer.SetStreamHandler(func(delta string) {
+			if !headerPrinted {
+				if streamed {
+					// A corrective loop after verification rewrites the answer
+					fmt.Print("\n\n")
+					header = "Revised answer:"
+					if verbose {
+						header = "=== Revised Answer ==="
+					}
+				}
+				fmt.Println(header)
+				headerPrinted = true
+				streamed = true
+			}
+			fmt.Print(delta)
+		})
+		defer system.Synthesizer.SetStreamHandler(nil)
+
+		ctx = workflow.WithObserver(ctx, workflow.ObserverFunc(func(event workflow.Event) {
+			if event.Type == workflow.EventNodeStarted && event.Node == "synthesizer" {
+				headerPrinted = false
+			}
+		}))
+	}
+
+	// Report progress on stderr so the answer on stdout stays clean
+	system.Executor.SetObserver(newProgressReporter(os.Stderr, verbose))
+	defer system.Executor.SetObserver(nil)
 
 	// Execute workflow
-	result, err := system.Executor.Execute(ctx, state)
+	result, err := execute(ctx)
+	if streamed {
+		fmt.Println()
+		fmt.Println()
+	}
 	if err != nil {
+		if result != nil && result.RunID != "" {
+			fmt.Fprintf(os.Stderr, "Run %s was checkpointed; continue it with: deep-thinking-agent query -resume %s\n", result.RunID, result.RunID)
+		}
 		return fmt.Errorf("execution failed: %w", err)
 	}
 
 	// Display results
 	if verbose {
-		displayVerboseResults(result)
+		displayVerboseResults(result, streamed)
 	} else {
-		displayCompactResults(result)
+		displayCompactResults(result, streamed)
+	}
+	displayReferences(result.Citations, verbose)
+	if verbose {
+		displayVerification(result.Verification)
 	}
 
 	return nil
 }
 
-func displayVerboseResults(state *workflow.State) {
+// displayVerboseResults prints the plan, execution history, and final answer.
+// The answer section is skipped if it was already streamed.
+func displayVerboseResults(state *workflow.State, answerStreamed bool) {
 	fmt.Println("=== Execution Plan ===")
 	if state.Plan != nil {
 		fmt.Printf("Reasoning: %s\n", state.Plan.Reasoning)
@@ -168,6 +263,9 @@ func displayVerboseResults(state *workflow.State) {
 	fmt.Println("=== Execution History ===")
 	for i, pastStep := range state.PastSteps {
 		fmt.Printf("Step %d: %s\n", i+1, pastStep.Step.SubQuestion)
+		if pastStep.Strategy != "" {
+			fmt.Printf("Strategy: %s\n", pastStep.Strategy)
+		}
 		fmt.Printf("Summary: %s\n", pastStep.Summary)
 		if len(pastStep.KeyFindings) > 0 {
 			fmt.Println("Key Findings:")
@@ -179,6 +277,10 @@ func displayVerboseResults(state *workflow.State) {
 		fmt.Println()
 	}
 
+	if answerStreamed {
+		return
+	}
+
 	fmt.Println("=== Final Answer ===")
 	if state.FinalAnswer != "" {
 		fmt.Println(state.FinalAnswer)
@@ -187,12 +289,81 @@ func displayVerboseResults(state *workflow.State) {
 	}
 }
 
-func displayCompactResults(state *workflow.State) {
+// displayReferences lists the sources cited by the final answer's [n] markers.
+func displayReferences(citations []workflow.Citation, verbose bool) {
+	if len(citations) == 0 {
+		return
+	}
+
+	fmt.Println()
+	if verbose {
+		fmt.Println("=== References ===")
+	} else {
+		fmt.Println("References:")
+	}
+	for _, citation := range citations {
+		source := citation.DocID
+		if citation.SourceURL != "" {
+			source = citation.SourceURL
+		}
+		if source == "" {
+			source = citation.ChunkID
+		}
+		if citation.SectionTitle != "" {
+			source += ", " + citation.SectionTitle
+		}
+
+		details := fmt.Sprintf("score %.2f", citation.Score)
+		if citation.EndPos > 0 {
+			details = fmt.Sprintf("chars %d-%d, %s", citation.StartPos, citation.EndPos, details)
+		}
+		fmt.Printf("[%d] %s (%s)\n", citation.Marker, source, details)
+	}
+}
+
+// displayVerification prints the verdict on each claim of the final answer.
+func displayVerification(report *workflow.VerificationReport) {
+	if report == nil {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println("=== Verification ===")
+	if report.Error != "" {
+		fmt.Printf("Verification failed: %s\n", report.Error)
+		return
+	}
+
+	supported := len(report.Claims) - len(report.Failed())
+	fmt.Printf("%d of %d claims supported", supported, len(report.Claims))
+	if report.Corrected {
+		fmt.Print(" (after corrective retrieval)")
+	}
+	fmt.Println()
+
+	for _, claim := range report.Claims {
+		fmt.Printf("  [%s] %s\n", claim.Status, claim.Claim)
+		if claim.Status != workflow.ClaimSupported && claim.Explanation != "" {
+			fmt.Printf("      %s\n", claim.Explanation)
+		}
+	}
+}
+
+// displayCompactResults prints the step count and final answer.
+// The answer is skipped if it was already streamed.
+func displayCompactResults(state *workflow.State, answerStreamed bool) {
 	if state.Plan != nil && len(state.Plan.Steps) > 0 {
 		fmt.Printf("Executed %d reasoning steps\n", len(state.PastSteps))
+		if answerStreamed {
+			return
+		}
 		fmt.Println()
 	}
 
+	if answerStreamed {
+		return
+	}
+
 	fmt.Println("Answer:")
 	if state.FinalAnswer != "" {
 		fmt.Println(state.FinalAnswer)
@@ -20,6 +20,9 @@ type Config struct {
 	Embedding   EmbeddingConfig   `json:"embedding"`
 	VectorStore VectorStoreConfig `json:"vector_store"`
 	Workflow    WorkflowConfig    `json:"workflow"`
+	WebSearch   *WebSearchConfig  `json:"web_search,omitempty"`
+	Ingest      IngestConfig      `json:"ingest,omitempty"`
+	Server      ServerConfig      `json:"server,omitempty"`
 }
 
 // LLMConfig contains configuration for LLM providers.
@@ -30,32 +33,155 @@ type LLMConfig struct {
 
 // LLMProviderConfig contains configuration for a specific LLM provider.
 type LLMProviderConfig struct {
-	Provider           string  `json:"provider"`
+	Provider           string  `json:"provider"` // "openai", "anthropic", or "ollama"
 	Model              string  `json:"model"`
 	APIKey             string  `json:"api_key,omitempty"`
+	BaseURL            string  `json:"base_url,omitempty"`
 	DefaultTemperature float32 `json:"default_temperature"`
 }
 
 // EmbeddingConfig contains configuration for embedding generation.
 type EmbeddingConfig struct {
-	Provider string `json:"provider"`
-	Model    string `json:"model"`
-	APIKey   string `json:"api_key,omitempty"`
+	Provider  string `json:"provider"` // "openai" or "ollama"
+	Model     string `json:"model"`
+	APIKey    string `json:"api_key,omitempty"`
+	BaseURL   string `json:"base_url,omitempty"`
+	BatchSize int    `json:"batch_size,omitempty"` // Texts per embedding request; defaults to DefaultEmbeddingBatchSize
+}
+
+// DefaultEmbeddingBatchSize is the number of texts per embedding request
+// when the configuration sets no batch size.
+const DefaultEmbeddingBatchSize = 100
+
+// batchSize returns the configured batch size or the default.
+func (c EmbeddingConfig) batchSize() int {
+	if c.BatchSize > 0 {
+		return c.BatchSize
+	}
+	return DefaultEmbeddingBatchSize
 }
 
 // VectorStoreConfig contains configuration for the vector database.
 type VectorStoreConfig struct {
-	Type              string `json:"type"`
-	Address           string `json:"address"`
-	DefaultCollection string `json:"default_collection"`
+	Type              string                      `json:"type"`
+	Address           string                      `json:"address"`
+	DefaultCollection string                      `json:"default_collection"`
+	Collections       map[string]CollectionConfig `json:"collections,omitempty"` // Per-collection settings, keyed by name
+}
+
+// CollectionConfig contains retrieval settings for a single collection.
+type CollectionConfig struct {
+	// QueryExpansion selects the query variants searched alongside each
+	// rewritten query: "none" (default), "multi" (paraphrases and keywords),
+	// "hyde" (a hypothetical answer passage) or "full" (all of them)
+	QueryExpansion string `json:"query_expansion,omitempty"`
+	Paraphrases    int    `json:"paraphrases,omitempty"` // For "multi" and "full"; defaults to 2
+}
+
+// Collection returns the settings for a collection, or zero settings if it
+// has none.
+func (c VectorStoreConfig) Collection(name string) CollectionConfig {
+	return c.Collections[name]
+}
+
+// WebSearchConfig contains configuration for the optional web search tool.
+type WebSearchConfig struct {
+	Enabled    bool   `json:"enabled"`
+	Provider   string `json:"provider"`           // "serper", "brave", or "http"
+	Endpoint   string `json:"endpoint,omitempty"` // Overrides the provider's default endpoint
+	APIKey     string `json:"api_key,omitempty"`
+	MaxResults int    `json:"max_results"`
 }
 
 // WorkflowConfig contains configuration for workflow execution.
 type WorkflowConfig struct {
-	MaxIterations   int    `json:"max_iterations"`
-	TopKRetrieval   int    `json:"top_k_retrieval"`
-	TopNReranking   int    `json:"top_n_reranking"`
-	DefaultStrategy string `json:"default_strategy"`
+	MaxIterations     int     `json:"max_iterations"`
+	TopKRetrieval     int     `json:"top_k_retrieval"`
+	TopNReranking     int     `json:"top_n_reranking"`
+	MinRelevanceScore float32 `json:"min_relevance_score,omitempty"` // Applies to LLM relevance scores; requires rerank_mode "llm"
+	RerankMode        string  `json:"rerank_mode,omitempty"`         // "score" (default) or "llm"
+	MaxParallelSteps  int     `json:"max_parallel_steps,omitempty"`  // Plan steps run at once; 0 or 1 runs them sequentially
+	MaxReplans        int     `json:"max_replans,omitempty"`         // Plan revisions per run; 0 uses the default of 2, negative disables
+	DefaultStrategy   string  `json:"default_strategy"`
+	CheckpointDir     string  `json:"checkpoint_dir,omitempty"` // Enables resumable runs when set
+
+	// VerifyAnswers checks final answers against the retrieved evidence;
+	// CorrectiveRetrieval then re-researches failed claims once
+	VerifyAnswers       bool `json:"verify_answers,omitempty"`
+	CorrectiveRetrieval bool `json:"corrective_retrieval,omitempty"`
+}
+
+// IngestConfig contains configuration for document ingestion.
+type IngestConfig struct {
+	ManifestPath string `json:"manifest_path,omitempty"` // Defaults to DefaultManifestPath
+	Workers      int    `json:"workers,omitempty"`       // Files ingested concurrently; defaults to DefaultIngestWorkers
+	SchemaDir    string `json:"schema_dir,omitempty"`    // Derived document schemas; defaults to DefaultSchemaDir
+}
+
+// ServerConfig contains configuration for the HTTP API server.
+// Zero values are replaced by defaults in WithDefaults.
+type ServerConfig struct {
+	Address              string `json:"address,omitempty"`
+	MaxConcurrentQueries int    `json:"max_concurrent_queries,omitempty"`
+	MaxConcurrentIngests int    `json:"max_concurrent_ingests,omitempty"`
+	QueryTimeoutSeconds  int    `json:"query_timeout_seconds,omitempty"`
+	IngestTimeoutSeconds int    `json:"ingest_timeout_seconds,omitempty"`
+	MaxUploadMB          int    `json:"max_upload_mb,omitempty"`
+}
+
+// WithDefaults returns the configuration with unset fields defaulted.
+func (c ServerConfig) WithDefaults() ServerConfig {
+	if c.Address == "" {
+		c.Address = ":8080"
+	}
+	if c.MaxConcurrentQueries <= 0 {
+		c.MaxConcurrentQueries = 4
+	}
+	if c.MaxConcurrentIngests <= 0 {
+		c.MaxConcurrentIngests = 2
+	}
+	if c.QueryTimeoutSeconds <= 0 {
+		c.QueryTimeoutSeconds = 300
+	}
+	if c.IngestTimeoutSeconds <= 0 {
+		c.IngestTimeoutSeconds = 600
+	}
+	if c.MaxUploadMB <= 0 {
+		c.MaxUploadMB = 32
+	}
+	return c
+}
+
+// ManifestFile returns the configured ingest manifest path or the default.
+func (c IngestConfig) ManifestFile() string {
+	if c.ManifestPath != "" {
+		return c.ManifestPath
+	}
+	return DefaultManifestPath
+}
+
+// DefaultIngestWorkers is the number of files ingested concurrently when the
+// configuration sets no worker count.
+const DefaultIngestWorkers = 4
+
+// workers returns the configured worker count or the default.
+func (c IngestConfig) workers() int {
+	if c.Workers > 0 {
+		return c.Workers
+	}
+	return DefaultIngestWorkers
+}
+
+// DefaultSchemaDir is where derived document schemas are stored when the
+// configuration names no schema directory.
+const DefaultSchemaDir = ".schemas"
+
+// schemaDir returns the configured schema directory or the default.
+func (c IngestConfig) schemaDir() string {
+	if c.SchemaDir != "" {
+		return c.SchemaDir
+	}
+	return DefaultSchemaDir
 }
 
 // LoadConfig loads configuration from a JSON file.
@@ -74,18 +200,38 @@ func LoadConfig(path string) (*Config, error) {
 
 	// Load API keys from environment if not in config
 	if config.LLM.ReasoningLLM.APIKey == "" {
-		config.LLM.ReasoningLLM.APIKey = os.Getenv("OPENAI_API_KEY")
+		if env := llmAPIKeyEnv(config.LLM.ReasoningLLM.Provider); env != "" {
+			config.LLM.ReasoningLLM.APIKey = os.Getenv(env)
+		}
 	}
 	if config.LLM.FastLLM.APIKey == "" {
-		config.LLM.FastLLM.APIKey = os.Getenv("OPENAI_API_KEY")
+		if env := llmAPIKeyEnv(config.LLM.FastLLM.Provider); env != "" {
+			config.LLM.FastLLM.APIKey = os.Getenv(env)
+		}
 	}
-	if config.Embedding.APIKey == "" {
+	if config.Embedding.APIKey == "" && config.Embedding.Provider == "openai" {
 		config.Embedding.APIKey = os.Getenv("OPENAI_API_KEY")
 	}
+	if config.WebSearch != nil && config.WebSearch.APIKey == "" {
+		config.WebSearch.APIKey = os.Getenv("WEB_SEARCH_API_KEY")
+	}
 
 	return &config, nil
 }
 
+// llmAPIKeyEnv returns the environment variable holding the API key for an
+// LLM provider, or "" for local providers that need no key.
+func llmAPIKeyEnv(provider string) string {
+	switch provider {
+	case "anthropic":
+		return "ANTHROPIC_API_KEY"
+	case "ollama":
+		return ""
+	default:
+		return "OPENAI_API_KEY"
+	}
+}
+
 // DefaultConfig returns a default configuration suitable for initial setup.
 func DefaultConfig() *Config {
 	return &Config{
@@ -102,8 +248,9 @@ func DefaultConfig() *Config {
 			},
 		},
 		Embedding: EmbeddingConfig{
-			Provider: "openai",
-			Model:    "text-embedding-3-small",
+			Provider:  "openai",
+			Model:     "text-embedding-3-small",
+			BatchSize: DefaultEmbeddingBatchSize,
 		},
 		VectorStore: VectorStoreConfig{
 			Type:              "qdrant",
@@ -111,11 +258,20 @@ func DefaultConfig() *Config {
 			DefaultCollection: "documents",
 		},
 		Workflow: WorkflowConfig{
-			MaxIterations:   10,
-			TopKRetrieval:   10,
-			TopNReranking:   3,
-			DefaultStrategy: "hybrid",
+			MaxIterations:    10,
+			TopKRetrieval:    10,
+			TopNReranking:    3,
+			RerankMode:       "score",
+			MaxParallelSteps: 3,
+			DefaultStrategy:  "hybrid",
+			CheckpointDir:    ".checkpoints",
+		},
+		Ingest: IngestConfig{
+			ManifestPath: DefaultManifestPath,
+			Workers:      DefaultIngestWorkers,
+			SchemaDir:    DefaultSchemaDir,
 		},
+		Server: ServerConfig{}.WithDefaults(),
 	}
 }
 
@@ -0,0 +1,144 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"deep-thinking-agent/pkg/vectorstore"
+)
+
+// listPageSize is the number of chunks fetched per List call when scanning
+// a collection.
+const listPageSize = 500
+
+// DocumentInfo summarizes an ingested document from its chunks.
+type DocumentInfo struct {
+	DocID       string `json:"doc_id"`
+	Title       string `json:"title,omitempty"`
+	Format      string `json:"format,omitempty"`
+	ContentHash string `json:"content_hash,omitempty"`
+	Chunks      int    `json:"chunks"`
+}
+
+// Collection returns the collection to use for name, which defaults to the
+// configured collection when empty.
+func (s *System) Collection(name string) string {
+	if name != "" {
+		return name
+	}
+	return s.Config.VectorStore.DefaultCollection
+}
+
+// ListDocuments returns the documents in a collection, sorted by doc_id.
+// Documents are found by scanning every chunk, grouped by doc_id.
+func (s *System) ListDocuments(ctx context.Context, collection string) ([]DocumentInfo, error) {
+	collection = s.Collection(collection)
+
+	byID := make(map[string]*DocumentInfo)
+	err := s.scanChunks(ctx, collection, nil, func(chunk vectorstore.Document) {
+		docID, _ := chunk.Metadata["doc_id"].(string)
+		info, ok := byID[docID]
+		if !ok {
+			info = &DocumentInfo{DocID: docID}
+			info.Title, _ = chunk.Metadata["title"].(string)
+			info.Format, _ = chunk.Metadata["format"].(string)
+			info.ContentHash, _ = chunk.Metadata["content_hash"].(string)
+			byID[docID] = info
+		}
+		info.Chunks++
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	documents := make([]DocumentInfo, 0, len(byID))
+	for _, info := range byID {
+		documents = append(documents, *info)
+	}
+	sort.Slice(documents, func(i, j int) bool {
+		return documents[i].DocID < documents[j].DocID
+	})
+
+	return documents, nil
+}
+
+// DocumentChunks returns the chunks of a document, ordered by chunk_index.
+func (s *System) DocumentChunks(ctx context.Context, collection, docID string) ([]vectorstore.Document, error) {
+	collection = s.Collection(collection)
+
+	var chunks []vectorstore.Document
+	err := s.scanChunks(ctx, collection, vectorstore.Eq("doc_id", docID), func(chunk vectorstore.Document) {
+		chunks = append(chunks, chunk)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(chunks, func(i, j int) bool {
+		return chunkIndex(chunks[i]) < chunkIndex(chunks[j])
+	})
+
+	return chunks, nil
+}
+
+// DeleteDocument removes every chunk of a document from a collection and
+// returns the number of chunks deleted.
+func (s *System) DeleteDocument(ctx context.Context, collection, docID string) (int, error) {
+	collection = s.Collection(collection)
+
+	chunks, err := s.DocumentChunks(ctx, collection, docID)
+	if err != nil {
+		return 0, err
+	}
+	if len(chunks) == 0 {
+		return 0, fmt.Errorf("document not found: %s", docID)
+	}
+
+	if err := s.deleteDocumentChunks(ctx, collection, docID); err != nil {
+		return 0, err
+	}
+	if err := s.saveSchema(ctx, docID, nil, nil); err != nil {
+		return 0, err
+	}
+
+	return len(chunks), nil
+}
+
+// scanChunks calls fn for every chunk in a collection matching filter.
+func (s *System) scanChunks(ctx context.Context, collection string, filter vectorstore.Filter, fn func(vectorstore.Document)) error {
+	for offset := 0; ; offset += listPageSize {
+		page, err := s.VectorStore.List(ctx, collection, filter, listPageSize, offset)
+		if err != nil {
+			return err
+		}
+
+		for _, chunk := range page {
+			fn(chunk)
+		}
+
+		if len(page) < listPageSize {
+			return nil
+		}
+	}
+}
+
+// chunkIndex returns a chunk's position within its document. Stores may
+// decode numeric metadata as int, int64 or float64.
+func chunkIndex(chunk vectorstore.Document) int {
+	switch index := chunk.Metadata["chunk_index"].(type) {
+	case int:
+		return index
+	case int64:
+		return int(index)
+	case float64:
+		return int(index)
+	default:
+		return 0
+	}
+}
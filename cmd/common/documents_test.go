@@ -0,0 +1,82 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package common
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"deep-thinking-agent/pkg/document/parser"
+)
+
+func TestSystem_Documents(t *testing.T) {
+	ctx := context.Background()
+	sys := newIngestTestSystem(t)
+
+	docs := []*parser.Document{
+		{
+			Content:    strings.Repeat("Alpha paragraph.\n", 60) + strings.Repeat("Beta paragraph.\n", 60),
+			Format:     "markdown",
+			SourcePath: "b.md",
+			Title:      "B",
+		},
+		{Content: "Only paragraph.", Format: "text", SourcePath: "a.txt"},
+	}
+	var bChunks int
+	for _, doc := range docs {
+		chunks, err := sys.IngestDocument(ctx, doc, false)
+		if err != nil {
+			t.Fatalf("IngestDocument() error = %v", err)
+		}
+		if doc.SourcePath == "b.md" {
+			bChunks = chunks
+		}
+	}
+
+	listed, err := sys.ListDocuments(ctx, "")
+	if err != nil {
+		t.Fatalf("ListDocuments() error = %v", err)
+	}
+	if len(listed) != 2 || listed[0].DocID != "a.txt" || listed[1].DocID != "b.md" {
+		t.Fatalf("ListDocuments() = %+v, want a.txt then b.md", listed)
+	}
+	if listed[1].Chunks != bChunks || listed[1].Title != "B" || listed[1].ContentHash == "" {
+		t.Errorf("b.md info = %+v", listed[1])
+	}
+
+	chunks, err := sys.DocumentChunks(ctx, "", "b.md")
+	if err != nil {
+		t.Fatalf("DocumentChunks() error = %v", err)
+	}
+	if len(chunks) != bChunks {
+		t.Fatalf("DocumentChunks() returned %d chunks, want %d", len(chunks), bChunks)
+	}
+	for i, chunk := range chunks {
+		if chunkIndex(chunk) != i {
+			t.Errorf("chunk %d has chunk_index %d", i, chunkIndex(chunk))
+		}
+	}
+
+	deleted, err := sys.DeleteDocument(ctx, "", "b.md")
+	if err != nil {
+		t.Fatalf("DeleteDocument() error = %v", err)
+	}
+	if deleted != bChunks {
+		t.Errorf("DeleteDocument() = %d, want %d", deleted, bChunks)
+	}
+	if _, err := sys.DeleteDocument(ctx, "", "b.md"); err == nil {
+		t.Error("DeleteDocument() expected error for missing document")
+	}
+
+	listed, err = sys.ListDocuments(ctx, "documents")
+	if err != nil {
+		t.Fatalf("ListDocuments() error = %v", err)
+	}
+	if len(listed) != 1 || listed[0].DocID != "a.txt" {
+		t.Errorf("after delete ListDocuments() = %+v", listed)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package common
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// IngestOptions controls how IngestFiles processes a set of files.
+type IngestOptions struct {
+	// DeriveSchema enables schema resolution and schema-aware chunking
+	DeriveSchema bool
+
+	// Workers is the number of files processed concurrently.
+	// Defaults to the configured ingest workers.
+	Workers int
+
+	// Manifest, if set, records every ingested document
+	Manifest *IngestManifest
+
+	// Incremental skips documents whose content hash matches the manifest
+	Incremental bool
+}
+
+// IngestResult reports the outcome of ingesting one file.
+type IngestResult struct {
+	Path   string
+	Format string
+	Title  string
+	Chunks int
+
+	// Unsupported is set if no parser is registered for the file
+	Unsupported bool
+
+	// Unchanged is set if the file was skipped in incremental mode
+	Unchanged bool
+
+	// Err is set if the file could not be parsed or ingested
+	Err error
+
+	Duration time.Duration
+}
+
+// IngestFiles ingests files with a pool of workers. Each worker parses a
+// file, resolves its schema, chunks it, embeds the chunks in batches and
+// inserts them. A failing file does not stop the others: its error is
+// reported in its result. report, if non-nil, is called once per file from
+// the calling goroutine, in completion order. Returns the context's error if
+// the run was cancelled before every file was processed.
+func (s *System) IngestFiles(ctx context.Context, paths []string, opts IngestOptions, report func(IngestResult)) error {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = s.Config.Ingest.workers()
+	}
+	if workers > len(paths) {
+		workers = len(paths)
+	}
+
+	jobs := make(chan string)
+	results := make(chan IngestResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				results <- s.ingestFile(ctx, path, opts)
+			}
+		}()
+	}
+
+	// Feed paths until done or cancelled, then close results once the
+	// workers have drained
+	go func() {
+		defer close(jobs)
+		for _, path := range paths {
+			select {
+			case jobs <- path:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for result := range results {
+		if report != nil {
+			report(result)
+		}
+	}
+
+	return ctx.Err()
+}
+
+// ingestFile runs the ingestion pipeline for a single file.
+func (s *System) ingestFile(ctx context.Context, path string, opts IngestOptions) IngestResult {
+	start := time.Now()
+	result := IngestResult{Path: path}
+	defer func() {
+		result.Duration = time.Since(start)
+	}()
+
+	doc, err := s.ParseFile(path)
+	if errors.Is(err, ErrUnsupportedFormat) {
+		result.Unsupported = true
+		return result
+	}
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Format = doc.Format
+	result.Title = doc.Title
+
+	// In incremental mode, skip documents whose content is unchanged
+	collection := s.Config.VectorStore.DefaultCollection
+	hash := ContentHash(doc)
+	if opts.Incremental && opts.Manifest != nil {
+		if entry, ok := opts.Manifest.Lookup(collection, doc.SourcePath); ok && entry.ContentHash == hash {
+			result.Unchanged = true
+			return result
+		}
+	}
+
+	chunks, err := s.IngestDocument(ctx, doc, opts.DeriveSchema)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Chunks = chunks
+
+	if opts.Manifest != nil {
+		opts.Manifest.RecordDocument(collection, doc, chunks)
+	}
+
+	return result
+}
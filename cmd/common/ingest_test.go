@@ -0,0 +1,159 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"deep-thinking-agent/pkg/document/parser"
+	"deep-thinking-agent/pkg/embedding"
+	"deep-thinking-agent/pkg/vectorstore"
+)
+
+// recordingEmbedder records the size of each embedding request.
+type recordingEmbedder struct {
+	fakeEmbedder
+
+	mu    sync.Mutex
+	sizes []int
+}
+
+func (r *recordingEmbedder) Embed(ctx context.Context, req *embedding.EmbedRequest) (*embedding.EmbedResponse, error) {
+	r.mu.Lock()
+	r.sizes = append(r.sizes, len(req.Texts))
+	r.mu.Unlock()
+	return r.fakeEmbedder.Embed(ctx, req)
+}
+
+func TestSystem_IngestFiles(t *testing.T) {
+	ctx := context.Background()
+	sys := newIngestTestSystem(t)
+	dir := t.TempDir()
+
+	var paths []string
+	for i := 0; i < 6; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("doc%d.md", i))
+		if err := os.WriteFile(path, []byte(fmt.Sprintf("# Doc %d\n\nBody %d.", i, i)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	unsupported := filepath.Join(dir, "image.png")
+	if err := os.WriteFile(unsupported, []byte{0x89}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing.txt")
+	paths = append(paths, unsupported, missing)
+
+	manifest := NewIngestManifest()
+	opts := IngestOptions{Workers: 3, Manifest: manifest, Incremental: true}
+
+	results := make(map[string]IngestResult)
+	if err := sys.IngestFiles(ctx, paths, opts, func(r IngestResult) { results[r.Path] = r }); err != nil {
+		t.Fatalf("IngestFiles() error = %v", err)
+	}
+
+	if len(results) != len(paths) {
+		t.Fatalf("got %d results, want %d", len(results), len(paths))
+	}
+	if !results[unsupported].Unsupported {
+		t.Error("unsupported file should be reported as unsupported")
+	}
+	if results[missing].Err == nil {
+		t.Error("missing file should be reported as an error without stopping the run")
+	}
+	for _, path := range paths[:6] {
+		if r := results[path]; r.Err != nil || r.Chunks == 0 {
+			t.Errorf("%s: result = %+v", path, r)
+		}
+		if _, ok := manifest.Lookup("documents", path); !ok {
+			t.Errorf("%s not recorded in manifest", path)
+		}
+	}
+
+	stored, err := sys.VectorStore.List(ctx, "", nil, 100, 0)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(stored) != 6 {
+		t.Errorf("stored %d chunks, want 6", len(stored))
+	}
+
+	// A second incremental run only re-ingests the changed file
+	if err := os.WriteFile(paths[0], []byte("# Doc 0\n\nRevised."), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	unchanged := 0
+	err = sys.IngestFiles(ctx, paths[:6], opts, func(r IngestResult) {
+		if r.Unchanged {
+			unchanged++
+		} else if r.Path != paths[0] {
+			t.Errorf("%s was re-ingested", r.Path)
+		}
+	})
+	if err != nil {
+		t.Fatalf("IngestFiles() error = %v", err)
+	}
+	if unchanged != 5 {
+		t.Errorf("unchanged = %d, want 5", unchanged)
+	}
+}
+
+func TestSystem_IngestFiles_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	sys := newIngestTestSystem(t)
+	path := filepath.Join(t.TempDir(), "doc.txt")
+	if err := os.WriteFile(path, []byte("text"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sys.IngestFiles(ctx, []string{path}, IngestOptions{}, nil); err == nil {
+		t.Error("IngestFiles() expected error for cancelled context")
+	}
+}
+
+func TestSystem_IngestDocument_EmbeddingBatches(t *testing.T) {
+	ctx := context.Background()
+	sys := newIngestTestSystem(t)
+	sys.Config.Embedding.BatchSize = 2
+	embedder := &recordingEmbedder{}
+	sys.Embedder = embedder
+
+	paragraphs := make([]string, 5)
+	for i := range paragraphs {
+		paragraphs[i] = strings.Repeat(fmt.Sprintf("paragraph %d ", i), 40)
+	}
+	doc := &parser.Document{Content: strings.Join(paragraphs, "\n"), Format: "text", SourcePath: "batched.txt"}
+
+	chunks, err := sys.IngestDocument(ctx, doc, false)
+	if err != nil {
+		t.Fatalf("IngestDocument() error = %v", err)
+	}
+	if chunks != 5 {
+		t.Fatalf("IngestDocument() created %d chunks, want 5", chunks)
+	}
+
+	want := []int{2, 2, 1}
+	if fmt.Sprint(embedder.sizes) != fmt.Sprint(want) {
+		t.Errorf("embedding request sizes = %v, want %v", embedder.sizes, want)
+	}
+
+	stored, err := sys.VectorStore.List(ctx, "", vectorstore.Eq("chunk_index", 4), 10, 0)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(stored) != 1 || !strings.HasPrefix(stored[0].Content, "paragraph 4") {
+		t.Errorf("last chunk = %+v", stored)
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"deep-thinking-agent/pkg/document/parser"
+)
+
+// DefaultManifestPath is used when the configuration names no ingest manifest.
+const DefaultManifestPath = ".ingest-manifest.json"
+
+const manifestVersion = 1
+
+// IngestManifest records which documents have been ingested into which
+// collections, so unchanged files can be skipped on later runs.
+// It is safe for concurrent use.
+type IngestManifest struct {
+	Version   int                       `json:"version"`
+	Documents map[string]*ManifestEntry `json:"documents"` // collection/doc_id -> entry
+
+	mu sync.Mutex
+}
+
+// ManifestEntry describes one ingested document.
+type ManifestEntry struct {
+	DocID       string    `json:"doc_id"`
+	Collection  string    `json:"collection"`
+	ContentHash string    `json:"content_hash"`
+	Format      string    `json:"format,omitempty"`
+	Title       string    `json:"title,omitempty"`
+	Chunks      int       `json:"chunks"`
+	IngestedAt  time.Time `json:"ingested_at"`
+}
+
+// NewIngestManifest creates an empty manifest.
+func NewIngestManifest() *IngestManifest {
+	return &IngestManifest{
+		Version:   manifestVersion,
+		Documents: make(map[string]*ManifestEntry),
+	}
+}
+
+// LoadIngestManifest reads a manifest written by Save.
+// A missing file yields an empty manifest.
+func LoadIngestManifest(path string) (*IngestManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return NewIngestManifest(), nil
+		}
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var manifest IngestManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if manifest.Version != manifestVersion {
+		return nil, fmt.Errorf("unsupported manifest version: %d", manifest.Version)
+	}
+	if manifest.Documents == nil {
+		manifest.Documents = make(map[string]*ManifestEntry)
+	}
+
+	return &manifest, nil
+}
+
+// Save writes the manifest atomically via a temporary file and rename.
+func (m *IngestManifest) Save(path string) error {
+	m.mu.Lock()
+	data, err := json.MarshalIndent(m, "", "  ")
+	m.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create manifest directory: %w", err)
+		}
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	return nil
+}
+
+// Lookup returns the entry for a document in a collection, if recorded.
+func (m *IngestManifest) Lookup(collection, docID string) (*ManifestEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.Documents[manifestKey(collection, docID)]
+	return entry, ok
+}
+
+// Record adds or replaces the entry for a document.
+func (m *IngestManifest) Record(entry *ManifestEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Documents[manifestKey(entry.Collection, entry.DocID)] = entry
+}
+
+// RecordDocument records doc as ingested into collection as chunks chunks.
+func (m *IngestManifest) RecordDocument(collection string, doc *parser.Document, chunks int) {
+	m.Record(&ManifestEntry{
+		DocID:       doc.SourcePath,
+		Collection:  collection,
+		ContentHash: ContentHash(doc),
+		Format:      doc.Format,
+		Title:       doc.Title,
+		Chunks:      chunks,
+		IngestedAt:  time.Now(),
+	})
+}
+
+// Remove deletes the entry for a document, if recorded.
+func (m *IngestManifest) Remove(collection, docID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.Documents, manifestKey(collection, docID))
+}
+
+// RemoveCollection deletes the entries for every document in a collection.
+func (m *IngestManifest) RemoveCollection(collection string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, entry := range m.Documents {
+		if entry.Collection == collection {
+			delete(m.Documents, key)
+		}
+	}
+}
+
+func manifestKey(collection, docID string) string {
+	return collection + "/" + docID
+}
+
+// ContentHash returns the hex SHA-256 of a document's original bytes,
+// falling back to its extracted text if the raw content was not kept.
+func ContentHash(doc *parser.Document) string {
+	data := doc.RawContent
+	if data == nil {
+		data = []byte(doc.Content)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -7,18 +7,27 @@ package common
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"deep-thinking-agent/pkg/agent"
 	"deep-thinking-agent/pkg/document/chunker"
+	"deep-thinking-agent/pkg/document/parser"
 	"deep-thinking-agent/pkg/embedding"
 	"deep-thinking-agent/pkg/llm"
+	"deep-thinking-agent/pkg/llm/anthropic"
+	"deep-thinking-agent/pkg/llm/ollama"
 	"deep-thinking-agent/pkg/llm/openai"
 	"deep-thinking-agent/pkg/nodes"
 	"deep-thinking-agent/pkg/schema"
 	"deep-thinking-agent/pkg/vectorstore"
+	"deep-thinking-agent/pkg/vectorstore/memory"
 	"deep-thinking-agent/pkg/vectorstore/qdrant"
+	"deep-thinking-agent/pkg/websearch"
 	"deep-thinking-agent/pkg/workflow"
 
 	"github.com/google/uuid"
@@ -32,13 +41,18 @@ type System struct {
 	Embedder       embedding.Embedder
 	VectorStore    vectorstore.Store
 	SchemaResolver *schema.Resolver
+	SchemaStore    schema.SchemaStore
+	Parsers        *parser.ParserRegistry
+	WebSearcher    websearch.Searcher
+	Synthesizer    *agent.Synthesizer
 	Executor       *workflow.Executor
 }
 
 // InitializeSystem creates and initializes all system components based on configuration.
 func InitializeSystem(config *Config) (*System, error) {
 	sys := &System{
-		Config: config,
+		Config:  config,
+		Parsers: parser.NewParserRegistry(),
 	}
 
 	// Initialize LLM providers
@@ -61,6 +75,16 @@ func InitializeSystem(config *Config) (*System, error) {
 		return nil, fmt.Errorf("failed to initialize schema resolver: %w", err)
 	}
 
+	// Initialize schema store
+	if err := sys.initSchemaStore(); err != nil {
+		return nil, fmt.Errorf("failed to initialize schema store: %w", err)
+	}
+
+	// Initialize web search (optional)
+	if err := sys.initWebSearch(); err != nil {
+		return nil, fmt.Errorf("failed to initialize web search: %w", err)
+	}
+
 	// Initialize workflow executor
 	if err := sys.initWorkflow(); err != nil {
 		return nil, fmt.Errorf("failed to initialize workflow: %w", err)
@@ -71,64 +95,92 @@ func InitializeSystem(config *Config) (*System, error) {
 
 func (s *System) initLLMs() error {
 	// Initialize reasoning LLM
-	switch s.Config.LLM.ReasoningLLM.Provider {
-	case "openai":
-		provider, err := openai.NewProvider(
-			s.Config.LLM.ReasoningLLM.APIKey,
-			s.Config.LLM.ReasoningLLM.Model,
-			&llm.Config{
-				DefaultTemperature: s.Config.LLM.ReasoningLLM.DefaultTemperature,
-				DefaultMaxTokens:   2000,
-			},
-		)
-		if err != nil {
-			return fmt.Errorf("failed to create reasoning LLM: %w", err)
-		}
-		s.ReasoningLLM = provider
-	default:
-		return fmt.Errorf("unsupported reasoning LLM provider: %s", s.Config.LLM.ReasoningLLM.Provider)
+	provider, err := newLLMProvider(s.Config.LLM.ReasoningLLM, 2000)
+	if err != nil {
+		return fmt.Errorf("failed to create reasoning LLM: %w", err)
 	}
+	s.ReasoningLLM = provider
 
 	// Initialize fast LLM
-	switch s.Config.LLM.FastLLM.Provider {
+	provider, err = newLLMProvider(s.Config.LLM.FastLLM, 1000)
+	if err != nil {
+		return fmt.Errorf("failed to create fast LLM: %w", err)
+	}
+	s.FastLLM = provider
+
+	return nil
+}
+
+// newLLMProvider creates the LLM provider selected by the configuration.
+func newLLMProvider(config LLMProviderConfig, defaultMaxTokens int) (llm.Provider, error) {
+	llmConfig := &llm.Config{
+		Provider:           config.Provider,
+		BaseURL:            config.BaseURL,
+		DefaultTemperature: config.DefaultTemperature,
+		DefaultMaxTokens:   defaultMaxTokens,
+	}
+
+	switch config.Provider {
 	case "openai":
-		provider, err := openai.NewProvider(
-			s.Config.LLM.FastLLM.APIKey,
-			s.Config.LLM.FastLLM.Model,
-			&llm.Config{
-				DefaultTemperature: s.Config.LLM.FastLLM.DefaultTemperature,
-				DefaultMaxTokens:   1000,
-			},
-		)
+		provider, err := openai.NewProvider(config.APIKey, config.Model, llmConfig)
 		if err != nil {
-			return fmt.Errorf("failed to create fast LLM: %w", err)
+			return nil, err
 		}
-		s.FastLLM = provider
+		return provider, nil
+	case "anthropic":
+		provider, err := anthropic.NewProvider(config.APIKey, config.Model, llmConfig)
+		if err != nil {
+			return nil, err
+		}
+		return provider, nil
+	case "ollama":
+		llmConfig.TimeoutSeconds = 300 // Local models can be slow to load
+		provider, err := ollama.NewProvider(config.Model, llmConfig)
+		if err != nil {
+			return nil, err
+		}
+		return provider, nil
 	default:
-		return fmt.Errorf("unsupported fast LLM provider: %s", s.Config.LLM.FastLLM.Provider)
+		return nil, fmt.Errorf("unsupported LLM provider: %s", config.Provider)
 	}
-
-	return nil
 }
 
 func (s *System) initEmbedder() error {
+	var embedder embedding.Embedder
 	switch s.Config.Embedding.Provider {
 	case "openai":
-		embedder, err := embedding.NewOpenAIEmbedder(
+		openaiEmbedder, err := embedding.NewOpenAIEmbedder(
 			s.Config.Embedding.APIKey,
 			s.Config.Embedding.Model,
 			&embedding.Config{
-				BatchSize: 100,
+				BaseURL:   s.Config.Embedding.BaseURL,
+				BatchSize: s.Config.Embedding.batchSize(),
 			},
 		)
 		if err != nil {
 			return fmt.Errorf("failed to create embedder: %w", err)
 		}
-		s.Embedder = embedder
+		embedder = openaiEmbedder
+	case "ollama":
+		ollamaEmbedder, err := embedding.NewOllamaEmbedder(
+			s.Config.Embedding.Model,
+			&embedding.Config{
+				BaseURL:        s.Config.Embedding.BaseURL,
+				TimeoutSeconds: 120,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create embedder: %w", err)
+		}
+		embedder = ollamaEmbedder
 	default:
 		return fmt.Errorf("unsupported embedding provider: %s", s.Config.Embedding.Provider)
 	}
 
+	// Retry rate limits and transient failures, which are common when
+	// ingesting with several workers
+	s.Embedder = embedding.NewRetryEmbedder(embedder, nil)
+
 	return nil
 }
 
@@ -145,6 +197,17 @@ func (s *System) initVectorStore() error {
 			return fmt.Errorf("failed to create vector store: %w", err)
 		}
 		s.VectorStore = store
+	case "memory":
+		// Address is an optional snapshot file for the in-memory store
+		store, err := memory.NewStore(&vectorstore.Config{
+			Type:              "memory",
+			Address:           s.Config.VectorStore.Address,
+			DefaultCollection: s.Config.VectorStore.DefaultCollection,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create vector store: %w", err)
+		}
+		s.VectorStore = store
 	default:
 		return fmt.Errorf("unsupported vector store type: %s", s.Config.VectorStore.Type)
 	}
@@ -164,9 +227,41 @@ func (s *System) initSchemaResolver() error {
 	return nil
 }
 
-func (s *System) initWorkflow() error {
-	ctx := context.Background()
+func (s *System) initSchemaStore() error {
+	store, err := schema.NewFileSchemaStore(s.Config.Ingest.schemaDir())
+	if err != nil {
+		return err
+	}
+	s.SchemaStore = store
+
+	return nil
+}
+
+func (s *System) initWebSearch() error {
+	if s.Config.WebSearch == nil || !s.Config.WebSearch.Enabled {
+		return nil
+	}
+
+	searchConfig, err := websearch.PresetConfig(s.Config.WebSearch.Provider)
+	if err != nil {
+		return err
+	}
+	if s.Config.WebSearch.Endpoint != "" {
+		searchConfig.Endpoint = s.Config.WebSearch.Endpoint
+	}
+	searchConfig.APIKey = s.Config.WebSearch.APIKey
+	searchConfig.MaxResults = s.Config.WebSearch.MaxResults
+
+	searcher, err := websearch.NewHTTPSearcher(searchConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create web searcher: %w", err)
+	}
+	s.WebSearcher = searcher
 
+	return nil
+}
+
+func (s *System) initWorkflow() error {
 	// Create agents
 	// Reasoning models (gpt-5, o1, o3) need higher token limits for internal reasoning
 	// Standard models (gpt-4o, gpt-4) use reasonable completion limits
@@ -182,9 +277,20 @@ func (s *System) initWorkflow() error {
 		MaxTokens:   plannerMaxTokens,
 	})
 
+	// Query variants are configured for the collection being searched
+	expansion, err := queryExpansion(s.Config.VectorStore.Collection(s.Config.VectorStore.DefaultCollection))
+	if err != nil {
+		return err
+	}
+	rewriterMaxTokens := 500
+	if expansion != nil {
+		rewriterMaxTokens = 1000 // Room for paraphrases and a hypothetical passage
+	}
+
 	rewriter := agent.NewRewriter(s.FastLLM, &agent.RewriterConfig{
 		Temperature: 0.5,
-		MaxTokens:   500,
+		MaxTokens:   rewriterMaxTokens,
+		Expansion:   expansion,
 	})
 
 	supervisor := agent.NewSupervisor(s.FastLLM, &agent.SupervisorConfig{
@@ -196,26 +302,53 @@ func (s *System) initWorkflow() error {
 		s.VectorStore,
 		s.Embedder,
 		&agent.RetrieverConfig{
-			DefaultTopK: s.Config.Workflow.TopKRetrieval,
+			DefaultTopK:     s.Config.Workflow.TopKRetrieval,
+			DefaultStrategy: workflow.RetrievalStrategy(s.Config.Workflow.DefaultStrategy),
+			WebSearcher:     s.WebSearcher,
 		},
 	)
 
-	reranker := agent.NewReranker(&agent.RerankerConfig{
-		TopN: s.Config.Workflow.TopNReranking,
-	})
-
 	// Fast LLM agents - increase tokens if using reasoning models
 	distillerMaxTokens := 1000
 	reflectorMaxTokens := 500
 	policyMaxTokens := 300
+	scorerMaxTokens := 300
+	verifierMaxTokens := 1500
 	if strings.HasPrefix(s.Config.LLM.FastLLM.Model, "gpt-5") ||
 		strings.HasPrefix(s.Config.LLM.FastLLM.Model, "o1") ||
 		strings.HasPrefix(s.Config.LLM.FastLLM.Model, "o3") {
 		distillerMaxTokens = 5000
 		reflectorMaxTokens = 2500
 		policyMaxTokens = 1500
+		scorerMaxTokens = 1500
+		verifierMaxTokens = 6000
 	}
 
+	var scorer agent.Scorer
+	switch s.Config.Workflow.RerankMode {
+	case "", "score":
+		// Use retrieval scores as-is; their scale depends on the strategy,
+		// so a relevance threshold would cut arbitrarily
+		if s.Config.Workflow.MinRelevanceScore > 0 {
+			return fmt.Errorf("min_relevance_score requires rerank_mode \"llm\"")
+		}
+	case "llm":
+		scorer = agent.NewLLMScorer(s.FastLLM, &agent.LLMScorerConfig{
+			Temperature:  0.0,
+			MaxTokens:    scorerMaxTokens,
+			BatchSize:    5,
+			MaxDocLength: 800,
+		})
+	default:
+		return fmt.Errorf("unsupported rerank mode: %s", s.Config.Workflow.RerankMode)
+	}
+
+	reranker := agent.NewReranker(&agent.RerankerConfig{
+		TopN:              s.Config.Workflow.TopNReranking,
+		MinRelevanceScore: s.Config.Workflow.MinRelevanceScore,
+		Scorer:            scorer,
+	})
+
 	distiller := agent.NewDistiller(s.FastLLM, &agent.DistillerConfig{
 		Temperature: 0.3,
 		MaxTokens:   distillerMaxTokens,
@@ -226,21 +359,62 @@ func (s *System) initWorkflow() error {
 		MaxTokens:   reflectorMaxTokens,
 	})
 
+	// The policy may send the run back to the planner a few times
+	maxReplans := s.Config.Workflow.MaxReplans
+	if maxReplans == 0 {
+		maxReplans = 2
+	} else if maxReplans < 0 {
+		maxReplans = 0
+	}
+
 	policy := agent.NewPolicy(s.FastLLM, &agent.PolicyConfig{
 		Temperature: 0.3,
 		MaxTokens:   policyMaxTokens,
+		MaxReplans:  maxReplans,
 	})
 
-	// Create workflow nodes
+	// Final answer synthesis uses the reasoning LLM, same token budget as planning
+	synthesizer := agent.NewSynthesizer(s.ReasoningLLM, &agent.SynthesizerConfig{
+		Temperature: 0.3,
+		MaxTokens:   plannerMaxTokens,
+		MaxDocs:     10,
+	})
+	s.Synthesizer = synthesizer
+
+	// Create workflow nodes; the planner and supervisor see stored schemas,
+	// the planner those of the documents a search for the question finds
+	plannerNode := nodes.NewPlannerNode(planner)
+	supervisorNode := nodes.NewSupervisorNode(supervisor)
+	if s.SchemaStore != nil {
+		plannerNode.SetSchemaStore(s.SchemaStore)
+		plannerNode.SetRetriever(retrieverAgent)
+		supervisorNode.SetSchemaStore(s.SchemaStore)
+	}
+
 	nodeMap := map[string]workflow.Node{
-		"planner":    nodes.NewPlannerNode(ctx, planner),
-		"rewriter":   nodes.NewRewriterNode(ctx, rewriter),
-		"supervisor": nodes.NewSupervisorNode(ctx, supervisor),
-		"retriever":  nodes.NewRetrieverNode(ctx, retrieverAgent),
-		"reranker":   nodes.NewRerankerNode(ctx, reranker),
-		"distiller":  nodes.NewDistillerNode(ctx, distiller),
-		"reflector":  nodes.NewReflectorNode(ctx, reflector),
-		"policy":     nodes.NewPolicyNode(ctx, policy),
+		"planner":     plannerNode,
+		"rewriter":    nodes.NewRewriterNode(rewriter),
+		"supervisor":  supervisorNode,
+		"retriever":   nodes.NewRetrieverNode(retrieverAgent),
+		"reranker":    nodes.NewRerankerNode(reranker),
+		"distiller":   nodes.NewDistillerNode(distiller),
+		"reflector":   nodes.NewReflectorNode(reflector),
+		"policy":      nodes.NewPolicyNode(policy),
+		"synthesizer": nodes.NewSynthesizerNode(synthesizer),
+	}
+
+	// The verifier checks the final answer and can send failed claims back
+	// through the step pipeline once
+	if s.Config.Workflow.VerifyAnswers {
+		verifier := agent.NewVerifier(s.FastLLM, &agent.VerifierConfig{
+			Temperature: 0.0,
+			MaxTokens:   verifierMaxTokens,
+		})
+		verifierNode := nodes.NewVerifierNode(verifier)
+		if s.Config.Workflow.CorrectiveRetrieval {
+			verifierNode.SetCorrectionNode("rewriter")
+		}
+		nodeMap["verifier"] = verifierNode
 	}
 
 	// Build workflow graph
@@ -249,52 +423,134 @@ func (s *System) initWorkflow() error {
 		return fmt.Errorf("failed to build workflow graph: %w", err)
 	}
 
+	// Checkpoint runs to disk so failed queries can be resumed
+	var checkpoints workflow.CheckpointStore
+	if s.Config.Workflow.CheckpointDir != "" {
+		store, err := workflow.NewFileCheckpointStore(s.Config.Workflow.CheckpointDir)
+		if err != nil {
+			return fmt.Errorf("failed to create checkpoint store: %w", err)
+		}
+		checkpoints = store
+	}
+
 	// Create executor
 	s.Executor = workflow.NewExecutor(graph, &workflow.ExecutorConfig{
-		Timeout: 300000000000, // 5 minutes in nanoseconds
+		Timeout:          300000000000, // 5 minutes in nanoseconds
+		MaxParallelSteps: s.Config.Workflow.MaxParallelSteps,
+		Checkpoints:      checkpoints,
 	})
 
 	return nil
 }
 
-// IngestDocument processes and ingests a document into the vector store.
+// queryExpansion returns the rewriter's query variants for a collection's
+// settings, or nil if queries are not expanded.
+func queryExpansion(settings CollectionConfig) (*agent.ExpansionConfig, error) {
+	paraphrases := settings.Paraphrases
+	if paraphrases <= 0 {
+		paraphrases = 2
+	}
+
+	switch settings.QueryExpansion {
+	case "", "none":
+		return nil, nil
+	case "multi":
+		return &agent.ExpansionConfig{Paraphrases: paraphrases, Keywords: true}, nil
+	case "hyde":
+		return &agent.ExpansionConfig{HyDE: true}, nil
+	case "full":
+		return &agent.ExpansionConfig{Paraphrases: paraphrases, Keywords: true, HyDE: true}, nil
+	default:
+		return nil, fmt.Errorf("unsupported query expansion mode: %s", settings.QueryExpansion)
+	}
+}
+
+// ErrUnsupportedFormat is returned by ParseFile for files with no registered parser.
+var ErrUnsupportedFormat = errors.New("unsupported file format")
+
+// ParseFile reads a file and parses it with the parser registered for its
+// extension.
+func (s *System) ParseFile(path string) (*parser.Document, error) {
+	if _, ok := s.Parsers.GetParser(filepath.Ext(path)); !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	return s.ParseReader(file, path)
+}
+
+// ParseReader parses content with the parser registered for the extension
+// of name, which becomes the document's source path.
+func (s *System) ParseReader(r io.Reader, name string) (*parser.Document, error) {
+	p, ok := s.Parsers.GetParser(filepath.Ext(name))
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, name)
+	}
+
+	doc, err := p.Parse(r, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", p.Name(), err)
+	}
+
+	return doc, nil
+}
+
+// IngestDocument processes and ingests a parsed document into the vector store.
+// The document's source path is used as its doc_id, and its title, format,
+// content hash and scalar metadata are copied onto every chunk.
+// Ingestion is idempotent: chunk IDs are derived from the doc_id and chunk
+// position, and chunks left from a previous ingest of the document are removed.
 // If deriveSchema is true, uses schema-aware chunking; otherwise uses simple paragraph chunking.
-func (s *System) IngestDocument(ctx context.Context, docID string, content string, deriveSchema bool) (int, error) {
+func (s *System) IngestDocument(ctx context.Context, doc *parser.Document, deriveSchema bool) (int, error) {
+	if doc == nil {
+		return 0, errors.New("document is nil")
+	}
+
+	docID := doc.SourcePath
+	content := doc.Content
+	collection := s.Config.VectorStore.DefaultCollection
+	baseMetadata := documentMetadata(doc)
+
 	var chunks []string
 	var chunkMetadata []map[string]interface{}
+	var docSchema *schema.DocumentSchema // Set when chunks were built from a resolved schema
 
 	if deriveSchema && s.SchemaResolver != nil {
 		// Use schema-aware chunking
-		resolutionResult, err := s.SchemaResolver.Resolve(ctx, docID, content, "text/plain", nil)
+		resolutionResult, err := s.SchemaResolver.Resolve(ctx, docID, content, doc.Format, nil)
 		if err != nil {
 			// Fall back to simple chunking if schema resolution fails
-			chunks = splitIntoChunks(content, 512)
-			chunkMetadata = make([]map[string]interface{}, len(chunks))
-			for i := range chunks {
-				chunkMetadata[i] = map[string]interface{}{"doc_id": docID}
-			}
+			chunks, chunkMetadata = simpleChunks(content, baseMetadata)
 		} else {
 			// Use schema-aware chunker
 			chunkerConfig := chunker.DefaultConfig()
 			chunkResults, err := chunker.ChunkDocument(content, resolutionResult.Schema, chunkerConfig)
 			if err != nil {
 				// Fall back to simple chunking
-				chunks = splitIntoChunks(content, 512)
-				chunkMetadata = make([]map[string]interface{}, len(chunks))
-				for i := range chunks {
-					chunkMetadata[i] = map[string]interface{}{"doc_id": docID}
-				}
+				chunks, chunkMetadata = simpleChunks(content, baseMetadata)
 			} else {
 				// Extract chunks and their metadata
+				docSchema = resolutionResult.Schema
 				chunks = make([]string, len(chunkResults))
 				chunkMetadata = make([]map[string]interface{}, len(chunkResults))
 				for i, chunkResult := range chunkResults {
 					chunks[i] = chunkResult.Text
-					metadata := map[string]interface{}{"doc_id": docID}
+					metadata := copyMetadata(baseMetadata)
+					metadata["start_pos"] = chunkResult.StartPos
+					metadata["end_pos"] = chunkResult.EndPos
 					if chunkResult.Metadata != nil {
 						metadata["section_id"] = chunkResult.Metadata.SectionID
+						metadata["section_title"] = chunkResult.Metadata.SectionTitle
 						metadata["section_type"] = chunkResult.Metadata.SectionType
-						metadata["hierarchy"] = chunkResult.Metadata.HierarchyPath
+						metadata["hierarchy_path"] = chunkResult.Metadata.HierarchyPath
+						if len(chunkResult.Metadata.SemanticTags) > 0 {
+							metadata["semantic_tags"] = chunkResult.Metadata.SemanticTags
+						}
 					}
 					chunkMetadata[i] = metadata
 				}
@@ -302,43 +558,195 @@ func (s *System) IngestDocument(ctx context.Context, docID string, content strin
 		}
 	} else {
 		// Simple chunking: split by paragraphs
-		chunks = splitIntoChunks(content, 512)
-		chunkMetadata = make([]map[string]interface{}, len(chunks))
-		for i := range chunks {
-			chunkMetadata[i] = map[string]interface{}{"doc_id": docID}
+		chunks, chunkMetadata = simpleChunks(content, baseMetadata)
+	}
+
+	if len(chunks) == 0 {
+		if err := s.deleteDocumentChunks(ctx, collection, docID); err != nil {
+			return 0, err
 		}
+		return 0, s.saveSchema(ctx, docID, nil, nil)
 	}
 
 	// Generate embeddings
-	embedResp, err := s.Embedder.Embed(ctx, &embedding.EmbedRequest{
-		Texts: chunks,
-	})
+	embeddings, err := s.embedChunks(ctx, chunks)
 	if err != nil {
-		return 0, fmt.Errorf("failed to generate embeddings: %w", err)
+		return 0, err
+	}
+
+	// Replace any chunks from a previous ingest of this document
+	if err := s.deleteDocumentChunks(ctx, collection, docID); err != nil {
+		return 0, err
 	}
 
 	// Insert into vector store
 	docs := make([]vectorstore.Document, len(chunks))
+	chunkIDs := make([]string, len(chunks))
 	for i, chunk := range chunks {
+		chunkMetadata[i]["chunk_index"] = i
+		chunkIDs[i] = ChunkID(docID, i)
 		docs[i] = vectorstore.Document{
-			ID:        uuid.New().String(), // Generate valid UUID for Qdrant
+			ID:        chunkIDs[i],
 			Content:   chunk,
-			Embedding: embedResp.Vectors[i].Embedding,
+			Embedding: embeddings[i],
 			Metadata:  chunkMetadata[i],
 		}
 	}
 
 	_, err = s.VectorStore.Insert(ctx, &vectorstore.InsertRequest{
-		CollectionName: s.Config.VectorStore.DefaultCollection,
+		CollectionName: collection,
 		Documents:      docs,
 	})
 	if err != nil {
 		return 0, fmt.Errorf("failed to insert chunks: %w", err)
 	}
 
+	if err := s.saveSchema(ctx, docID, docSchema, chunkIDs); err != nil {
+		return 0, err
+	}
+
 	return len(chunks), nil
 }
 
+// saveSchema records the schema a document was chunked with in the schema
+// store. A nil schema removes any schema kept from a previous ingest, since
+// its chunk IDs no longer describe the stored chunks.
+func (s *System) saveSchema(ctx context.Context, docID string, docSchema *schema.DocumentSchema, chunkIDs []string) error {
+	if s.SchemaStore == nil {
+		return nil
+	}
+
+	if docSchema == nil {
+		if err := s.SchemaStore.Delete(ctx, docID); err != nil {
+			return fmt.Errorf("failed to delete schema: %w", err)
+		}
+		return nil
+	}
+
+	err := s.SchemaStore.Save(ctx, &schema.IndexEntry{
+		DocID:    docID,
+		Schema:   docSchema,
+		ChunkIDs: chunkIDs,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save schema: %w", err)
+	}
+	return nil
+}
+
+// embedChunks embeds chunk texts in requests of at most the configured
+// embedding batch size, returning one vector per chunk in order.
+func (s *System) embedChunks(ctx context.Context, chunks []string) ([][]float32, error) {
+	batchSize := s.Config.Embedding.batchSize()
+
+	embeddings := make([][]float32, 0, len(chunks))
+	for start := 0; start < len(chunks); start += batchSize {
+		end := start + batchSize
+		if end > len(chunks) {
+			end = len(chunks)
+		}
+
+		resp, err := s.Embedder.Embed(ctx, &embedding.EmbedRequest{
+			Texts: chunks[start:end],
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate embeddings: %w", err)
+		}
+		if len(resp.Vectors) != end-start {
+			return nil, fmt.Errorf("embedder returned %d vectors for %d chunks", len(resp.Vectors), end-start)
+		}
+
+		for _, vector := range resp.Vectors {
+			embeddings = append(embeddings, vector.Embedding)
+		}
+	}
+
+	return embeddings, nil
+}
+
+// chunkIDNamespace scopes the name-based UUIDs generated for chunk IDs.
+var chunkIDNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("deep-thinking-agent/chunk"))
+
+// ChunkID returns the deterministic ID of a document chunk. IDs are
+// name-based UUIDs so they are valid Qdrant point IDs.
+func ChunkID(docID string, index int) string {
+	return uuid.NewSHA1(chunkIDNamespace, []byte(fmt.Sprintf("%s#%d", docID, index))).String()
+}
+
+// deleteDocumentChunks removes every chunk of a document from a collection.
+// A collection that does not exist yet has nothing to delete.
+func (s *System) deleteDocumentChunks(ctx context.Context, collection, docID string) error {
+	_, err := s.VectorStore.GetCollection(ctx, collection)
+	if errors.Is(err, vectorstore.ErrCollectionNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete stale chunks: %w", err)
+	}
+
+	_, err = s.VectorStore.Delete(ctx, &vectorstore.DeleteRequest{
+		CollectionName: collection,
+		Filter:         vectorstore.Eq("doc_id", docID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete stale chunks: %w", err)
+	}
+	return nil
+}
+
+// documentMetadata builds the metadata shared by all chunks of a document.
+// Only scalar parser metadata is kept so it can be stored and filtered on
+// by every vector store.
+func documentMetadata(doc *parser.Document) map[string]interface{} {
+	metadata := make(map[string]interface{}, len(doc.Metadata)+3)
+	for key, value := range doc.Metadata {
+		switch value.(type) {
+		case string, int, int64, float64, bool:
+			metadata[key] = value
+		}
+	}
+
+	if doc.Title != "" {
+		metadata["title"] = doc.Title
+	}
+	if doc.Format != "" {
+		metadata["format"] = doc.Format
+	}
+	metadata["doc_id"] = doc.SourcePath
+	metadata["content_hash"] = ContentHash(doc)
+
+	return metadata
+}
+
+// simpleChunks splits content into paragraph chunks, each carrying a copy of
+// the document metadata and its position in the content.
+func simpleChunks(content string, baseMetadata map[string]interface{}) ([]string, []map[string]interface{}) {
+	chunks := splitIntoChunks(content, 512)
+	chunkMetadata := make([]map[string]interface{}, len(chunks))
+	pos := 0
+	for i, chunk := range chunks {
+		chunkMetadata[i] = copyMetadata(baseMetadata)
+
+		// Chunks are trimmed runs of whole lines, so each occurs in order
+		if offset := strings.Index(content[pos:], chunk); offset >= 0 {
+			pos += offset
+			chunkMetadata[i]["start_pos"] = pos
+			chunkMetadata[i]["end_pos"] = pos + len(chunk)
+			pos += len(chunk)
+		}
+	}
+	return chunks, chunkMetadata
+}
+
+// copyMetadata returns a shallow copy of a metadata map.
+func copyMetadata(metadata map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(metadata))
+	for key, value := range metadata {
+		copied[key] = value
+	}
+	return copied
+}
+
 // splitIntoChunks splits text into chunks of approximately maxSize characters
 func splitIntoChunks(text string, maxSize int) []string {
 	var chunks []string
@@ -0,0 +1,435 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package common
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"deep-thinking-agent/pkg/agent"
+	"deep-thinking-agent/pkg/document/parser"
+	"deep-thinking-agent/pkg/embedding"
+	"deep-thinking-agent/pkg/llm"
+	"deep-thinking-agent/pkg/schema"
+	"deep-thinking-agent/pkg/vectorstore"
+	"deep-thinking-agent/pkg/vectorstore/memory"
+)
+
+func TestNewLLMProvider(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   LLMProviderConfig
+		wantName string
+		wantErr  bool
+	}{
+		{
+			name:     "openai",
+			config:   LLMProviderConfig{Provider: "openai", Model: "gpt-4o", APIKey: "key"},
+			wantName: "openai",
+		},
+		{
+			name:     "anthropic",
+			config:   LLMProviderConfig{Provider: "anthropic", Model: "claude-sonnet-4-5", APIKey: "key"},
+			wantName: "anthropic",
+		},
+		{
+			name:     "ollama needs no API key",
+			config:   LLMProviderConfig{Provider: "ollama", Model: "llama3.1", BaseURL: "http://localhost:11434"},
+			wantName: "ollama",
+		},
+		{
+			name:    "missing API key",
+			config:  LLMProviderConfig{Provider: "anthropic", Model: "claude-sonnet-4-5"},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported provider",
+			config:  LLMProviderConfig{Provider: "unknown", Model: "m", APIKey: "key"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := newLLMProvider(tt.config, 1000)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("newLLMProvider() expected error")
+				}
+				if provider != nil {
+					t.Error("provider should be nil on error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("newLLMProvider() error = %v", err)
+			}
+			if provider.Name() != tt.wantName {
+				t.Errorf("Name() = %v, want %v", provider.Name(), tt.wantName)
+			}
+		})
+	}
+}
+
+// fakeEmbedder returns a fixed vector for every text.
+type fakeEmbedder struct{}
+
+func (f *fakeEmbedder) Embed(ctx context.Context, req *embedding.EmbedRequest) (*embedding.EmbedResponse, error) {
+	vectors := make([]embedding.Vector, len(req.Texts))
+	for i, text := range req.Texts {
+		vectors[i] = embedding.Vector{Embedding: []float32{1, 0, 0}, Text: text}
+	}
+	return &embedding.EmbedResponse{Vectors: vectors}, nil
+}
+
+func (f *fakeEmbedder) Dimensions() int   { return 3 }
+func (f *fakeEmbedder) ModelName() string { return "fake" }
+
+// newIngestTestSystem returns a system backed by an in-memory store and a
+// fake embedder, without schema derivation.
+func newIngestTestSystem(t *testing.T) *System {
+	t.Helper()
+
+	store, err := memory.NewStore(&vectorstore.Config{DefaultCollection: "documents"})
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+
+	return &System{
+		Config:      &Config{VectorStore: VectorStoreConfig{DefaultCollection: "documents"}},
+		Embedder:    &fakeEmbedder{},
+		VectorStore: store,
+		Parsers:     parser.NewParserRegistry(),
+	}
+}
+
+func TestQueryExpansion(t *testing.T) {
+	vectorStore := VectorStoreConfig{
+		DefaultCollection: "documents",
+		Collections: map[string]CollectionConfig{
+			"research": {QueryExpansion: "full", Paraphrases: 3},
+			"manuals":  {QueryExpansion: "hyde"},
+			"notes":    {QueryExpansion: "multi"},
+		},
+	}
+
+	expansion, err := queryExpansion(vectorStore.Collection("documents"))
+	if err != nil || expansion != nil {
+		t.Errorf("unconfigured collection = %+v, %v, want no expansion", expansion, err)
+	}
+
+	expansion, err = queryExpansion(vectorStore.Collection("research"))
+	if err != nil || *expansion != (agent.ExpansionConfig{Paraphrases: 3, Keywords: true, HyDE: true}) {
+		t.Errorf("full = %+v, %v", expansion, err)
+	}
+
+	expansion, err = queryExpansion(vectorStore.Collection("manuals"))
+	if err != nil || *expansion != (agent.ExpansionConfig{HyDE: true}) {
+		t.Errorf("hyde = %+v, %v", expansion, err)
+	}
+
+	expansion, err = queryExpansion(vectorStore.Collection("notes"))
+	if err != nil || *expansion != (agent.ExpansionConfig{Paraphrases: 2, Keywords: true}) {
+		t.Errorf("multi = %+v, %v", expansion, err)
+	}
+
+	if _, err := queryExpansion(CollectionConfig{QueryExpansion: "rag-fusion"}); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+}
+
+func TestSystem_ParseFile(t *testing.T) {
+	sys := newIngestTestSystem(t)
+	dir := t.TempDir()
+
+	htmlPath := filepath.Join(dir, "report.html")
+	if err := os.WriteFile(htmlPath, []byte("<html><head><title>Annual Report</title></head><body><p>Revenue grew.</p></body></html>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := sys.ParseFile(htmlPath)
+	if err != nil {
+		t.Fatalf("ParseFile() error = %v", err)
+	}
+	if doc.Format != "html" || doc.Title != "Annual Report" {
+		t.Errorf("ParseFile() format = %q, title = %q", doc.Format, doc.Title)
+	}
+
+	imagePath := filepath.Join(dir, "chart.png")
+	if err := os.WriteFile(imagePath, []byte{0x89, 'P', 'N', 'G'}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sys.ParseFile(imagePath); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("ParseFile() error = %v, want ErrUnsupportedFormat", err)
+	}
+}
+
+func TestSystem_IngestDocument(t *testing.T) {
+	ctx := context.Background()
+	sys := newIngestTestSystem(t)
+
+	doc := &parser.Document{
+		Content:    "Annual Report\n\nRevenue grew by 10%.",
+		Format:     "pdf",
+		SourcePath: "reports/annual.pdf",
+		Title:      "Annual Report",
+		Metadata: map[string]interface{}{
+			"page_count":     3,
+			"heading_counts": map[int]int{1: 2},
+		},
+	}
+
+	chunks, err := sys.IngestDocument(ctx, doc, false)
+	if err != nil {
+		t.Fatalf("IngestDocument() error = %v", err)
+	}
+	if chunks == 0 {
+		t.Fatal("IngestDocument() created no chunks")
+	}
+
+	stored, err := sys.VectorStore.List(ctx, "", nil, 10, 0)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(stored) != chunks {
+		t.Fatalf("stored %d chunks, want %d", len(stored), chunks)
+	}
+
+	metadata := stored[0].Metadata
+	want := map[string]interface{}{
+		"doc_id":     "reports/annual.pdf",
+		"title":      "Annual Report",
+		"format":     "pdf",
+		"page_count": 3,
+	}
+	for key, value := range want {
+		if metadata[key] != value {
+			t.Errorf("metadata[%s] = %v, want %v", key, metadata[key], value)
+		}
+	}
+	if _, ok := metadata["heading_counts"]; ok {
+		t.Error("non-scalar parser metadata should not be copied to chunks")
+	}
+
+	// Chunks record their span in the document content
+	for _, chunk := range stored {
+		start, _ := chunk.Metadata["start_pos"].(int)
+		end, ok := chunk.Metadata["end_pos"].(int)
+		if !ok || doc.Content[start:end] != chunk.Content {
+			t.Errorf("chunk %s span = %v-%v, does not match its content", chunk.ID, chunk.Metadata["start_pos"], chunk.Metadata["end_pos"])
+		}
+	}
+}
+
+func TestSystem_IngestDocument_Reingest(t *testing.T) {
+	ctx := context.Background()
+	sys := newIngestTestSystem(t)
+
+	long := strings.Repeat("First paragraph of the report.\n", 20) +
+		strings.Repeat("Second paragraph of the report.\n", 20)
+	doc := &parser.Document{Content: long, Format: "markdown", SourcePath: "report.md"}
+
+	first, err := sys.IngestDocument(ctx, doc, false)
+	if err != nil {
+		t.Fatalf("IngestDocument() error = %v", err)
+	}
+	if first < 2 {
+		t.Fatalf("IngestDocument() created %d chunks, want at least 2", first)
+	}
+
+	// Re-ingesting the same document must not duplicate chunks
+	if _, err := sys.IngestDocument(ctx, doc, false); err != nil {
+		t.Fatalf("IngestDocument() error = %v", err)
+	}
+	stored, err := sys.VectorStore.List(ctx, "", nil, 100, 0)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(stored) != first {
+		t.Fatalf("after re-ingest stored %d chunks, want %d", len(stored), first)
+	}
+
+	// A shorter revision must remove the chunks it no longer has
+	doc.Content = "Short revision."
+	second, err := sys.IngestDocument(ctx, doc, false)
+	if err != nil {
+		t.Fatalf("IngestDocument() error = %v", err)
+	}
+	stored, err = sys.VectorStore.List(ctx, "", nil, 100, 0)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(stored) != second {
+		t.Fatalf("after revision stored %d chunks, want %d", len(stored), second)
+	}
+	if stored[0].ID != ChunkID("report.md", 0) {
+		t.Errorf("chunk ID = %q, want %q", stored[0].ID, ChunkID("report.md", 0))
+	}
+
+	// Other documents are left alone
+	other := &parser.Document{Content: "Another document.", Format: "text", SourcePath: "other.txt"}
+	if _, err := sys.IngestDocument(ctx, other, false); err != nil {
+		t.Fatalf("IngestDocument() error = %v", err)
+	}
+	if _, err := sys.IngestDocument(ctx, doc, false); err != nil {
+		t.Fatalf("IngestDocument() error = %v", err)
+	}
+	stored, err = sys.VectorStore.List(ctx, "", vectorstore.Eq("doc_id", "other.txt"), 100, 0)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(stored) != 1 {
+		t.Errorf("other document has %d chunks, want 1", len(stored))
+	}
+}
+
+// unreachableStore is a vector store whose collections cannot be looked up.
+type unreachableStore struct {
+	*memory.Store
+}
+
+func (s unreachableStore) GetCollection(ctx context.Context, name string) (*vectorstore.CollectionInfo, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestSystem_IngestDocument_CollectionErrors(t *testing.T) {
+	ctx := context.Background()
+	doc := &parser.Document{Content: "Revenue grew.", Format: "text", SourcePath: "report.txt"}
+
+	// Ingesting into a collection that does not exist yet creates it
+	sys := newIngestTestSystem(t)
+	if _, err := sys.IngestDocument(ctx, doc, false); err != nil {
+		t.Fatalf("IngestDocument() into a new collection error = %v", err)
+	}
+
+	// Other lookup failures must not be mistaken for an empty collection
+	sys.VectorStore = unreachableStore{sys.VectorStore.(*memory.Store)}
+	if _, err := sys.IngestDocument(ctx, doc, false); err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("IngestDocument() error = %v, want the collection lookup error", err)
+	}
+}
+
+func TestChunkID(t *testing.T) {
+	if ChunkID("a.md", 0) != ChunkID("a.md", 0) {
+		t.Error("ChunkID() should be deterministic")
+	}
+	if ChunkID("a.md", 0) == ChunkID("a.md", 1) || ChunkID("a.md", 0) == ChunkID("b.md", 0) {
+		t.Error("ChunkID() should differ across documents and positions")
+	}
+}
+
+func TestIngestManifest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "manifest.json")
+
+	manifest, err := LoadIngestManifest(path)
+	if err != nil {
+		t.Fatalf("LoadIngestManifest() missing file error = %v", err)
+	}
+	if len(manifest.Documents) != 0 {
+		t.Fatalf("new manifest has %d documents", len(manifest.Documents))
+	}
+
+	doc := &parser.Document{Content: "text", RawContent: []byte("raw bytes"), SourcePath: "a.md"}
+	manifest.Record(&ManifestEntry{
+		DocID:       doc.SourcePath,
+		Collection:  "documents",
+		ContentHash: ContentHash(doc),
+		Chunks:      2,
+		IngestedAt:  time.Now(),
+	})
+	if err := manifest.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := LoadIngestManifest(path)
+	if err != nil {
+		t.Fatalf("LoadIngestManifest() error = %v", err)
+	}
+	entry, ok := loaded.Lookup("documents", "a.md")
+	if !ok {
+		t.Fatal("Lookup() found no entry after reload")
+	}
+	if entry.ContentHash != ContentHash(doc) || entry.Chunks != 2 {
+		t.Errorf("entry = %+v", entry)
+	}
+	if _, ok := loaded.Lookup("other", "a.md"); ok {
+		t.Error("Lookup() should be scoped to the collection")
+	}
+
+	loaded.RemoveCollection("documents")
+	if _, ok := loaded.Lookup("documents", "a.md"); ok {
+		t.Error("RemoveCollection() should remove the collection's entries")
+	}
+
+	changed := &parser.Document{Content: "text", RawContent: []byte("new bytes"), SourcePath: "a.md"}
+	if ContentHash(changed) == entry.ContentHash {
+		t.Error("ContentHash() should change with the raw content")
+	}
+}
+
+// schemaLLM answers schema analysis requests with a single-section schema.
+type schemaLLM struct{}
+
+func (s *schemaLLM) Complete(ctx context.Context, req *llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	return &llm.CompletionResponse{Content: `{
+		"title": "Annual Report",
+		"sections": [{"id": "sec1", "title": "Risk Factors", "level": 1, "start_pos": 0, "end_pos": 1000, "type": "risk_factors"}],
+		"chunking_strategy": "section_based",
+		"confidence": 0.9
+	}`}, nil
+}
+
+func (s *schemaLLM) Name() string            { return "fake" }
+func (s *schemaLLM) ModelName() string       { return "fake" }
+func (s *schemaLLM) SupportsStreaming() bool { return false }
+
+func TestSystem_IngestDocument_SchemaStore(t *testing.T) {
+	ctx := context.Background()
+	sys := newIngestTestSystem(t)
+	sys.SchemaResolver = schema.NewResolver(&schemaLLM{}, nil)
+	sys.SchemaStore = schema.NewMemorySchemaStore()
+
+	doc := &parser.Document{Content: "Risk Factors\n\nMarkets may decline.", Format: "pdf", SourcePath: "annual.pdf"}
+	chunks, err := sys.IngestDocument(ctx, doc, true)
+	if err != nil {
+		t.Fatalf("IngestDocument() error = %v", err)
+	}
+
+	entry, err := sys.SchemaStore.Load(ctx, "annual.pdf")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if entry.Schema.Sections[0].Type != "risk_factors" {
+		t.Errorf("stored schema = %+v", entry.Schema)
+	}
+	if len(entry.ChunkIDs) != chunks || entry.ChunkIDs[0] != ChunkID("annual.pdf", 0) {
+		t.Errorf("ChunkIDs = %v, want %d IDs of the stored chunks", entry.ChunkIDs, chunks)
+	}
+
+	// Re-ingesting without a schema drops the stale one
+	if _, err := sys.IngestDocument(ctx, doc, false); err != nil {
+		t.Fatalf("IngestDocument() error = %v", err)
+	}
+	if _, err := sys.SchemaStore.Load(ctx, "annual.pdf"); !errors.Is(err, schema.ErrSchemaNotFound) {
+		t.Errorf("Load() error = %v, want ErrSchemaNotFound", err)
+	}
+
+	// Deleting the document removes its schema
+	if _, err := sys.IngestDocument(ctx, doc, true); err != nil {
+		t.Fatalf("IngestDocument() error = %v", err)
+	}
+	if _, err := sys.DeleteDocument(ctx, "", "annual.pdf"); err != nil {
+		t.Fatalf("DeleteDocument() error = %v", err)
+	}
+	if _, err := sys.SchemaStore.Load(ctx, "annual.pdf"); !errors.Is(err, schema.ErrSchemaNotFound) {
+		t.Errorf("Load() after delete error = %v, want ErrSchemaNotFound", err)
+	}
+}
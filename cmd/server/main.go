@@ -0,0 +1,106 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"deep-thinking-agent/cmd/common"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("server", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to configuration file")
+	addr := fs.String("addr", "", "Listen address (default from config, or \":8080\")")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: deep-thinking-server [options]
+
+Serve the deep thinking agent over HTTP.
+
+Endpoints:
+  POST   /query              Run a query; streams events as SSE when "stream" is
+                             true or the client accepts text/event-stream
+  POST   /ingest             Ingest uploaded files (multipart field "file")
+  GET    /documents          List ingested documents (?collection=name)
+  GET    /schemas/{docID}    Show the resolved schema of a document
+  GET    /healthz            Health check
+
+Options:
+  -config string
+        Path to configuration file (default "config.json")
+  -addr string
+        Listen address (default from config, or ":8080")
+`)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// Load configuration
+	config, err := common.LoadConfig(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	serverConfig := config.Server.WithDefaults()
+	if *addr != "" {
+		serverConfig.Address = *addr
+	}
+
+	// Initialize system
+	system, err := common.InitializeSystem(config)
+	if err != nil {
+		return fmt.Errorf("failed to initialize system: %w", err)
+	}
+	defer system.Close()
+
+	httpServer := &http.Server{
+		Addr:              serverConfig.Address,
+		Handler:           newServer(system, serverConfig).routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	// Stop accepting requests on SIGINT/SIGTERM and let in-flight ones finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("Listening on %s", serverConfig.Address)
+		errCh <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	log.Println("Shutting down...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return httpServer.Shutdown(shutdownCtx)
+}
@@ -0,0 +1,496 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"deep-thinking-agent/cmd/common"
+	"deep-thinking-agent/pkg/agent"
+	"deep-thinking-agent/pkg/document/parser"
+	"deep-thinking-agent/pkg/schema"
+	"deep-thinking-agent/pkg/workflow"
+)
+
+// server handles the HTTP API. Queries and ingests each run under their own
+// concurrency limit and timeout.
+type server struct {
+	system *common.System
+	config common.ServerConfig
+
+	querySlots  chan struct{}
+	ingestSlots chan struct{}
+
+	// manifestMu serializes ingest manifest updates across ingest requests
+	manifestMu sync.Mutex
+}
+
+func newServer(system *common.System, config common.ServerConfig) *server {
+	config = config.WithDefaults()
+	return &server{
+		system:      system,
+		config:      config,
+		querySlots:  make(chan struct{}, config.MaxConcurrentQueries),
+		ingestSlots: make(chan struct{}, config.MaxConcurrentIngests),
+	}
+}
+
+func (s *server) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /query", s.handleQuery)
+	mux.HandleFunc("POST /ingest", s.handleIngest)
+	mux.HandleFunc("GET /documents", s.handleDocuments)
+	mux.HandleFunc("GET /schemas/{docID...}", s.handleSchema)
+	mux.HandleFunc("GET /healthz", s.handleHealth)
+	return mux
+}
+
+// errServerBusy is returned when no slot frees up before the request times out.
+var errServerBusy = errors.New("server busy, try again later")
+
+// acquire waits for a free slot, returning a function that releases it.
+func acquire(ctx context.Context, slots chan struct{}) (func(), error) {
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, errServerBusy
+	}
+}
+
+// queryRequest is the body of POST /query.
+type queryRequest struct {
+	Question      string `json:"question"`
+	MaxIterations int    `json:"max_iterations,omitempty"`
+	Stream        bool   `json:"stream,omitempty"`
+}
+
+// queryResponse is the result of a query, returned as JSON or as the final
+// "result" event of a stream.
+type queryResponse struct {
+	RunID      string         `json:"run_id,omitempty"`
+	Answer     string         `json:"answer"`
+	Plan       []string       `json:"plan,omitempty"`
+	Steps      []stepResponse `json:"steps"`
+	Citations  []citation     `json:"citations,omitempty"`
+	DurationMs int64          `json:"duration_ms"`
+}
+
+// citation is the JSON form of a workflow.Citation.
+type citation struct {
+	Marker       int     `json:"marker"`
+	ChunkID      string  `json:"chunk_id,omitempty"`
+	DocID        string  `json:"doc_id,omitempty"`
+	SectionID    string  `json:"section_id,omitempty"`
+	SectionTitle string  `json:"section_title,omitempty"`
+	StartPos     int     `json:"start_pos"`
+	EndPos       int     `json:"end_pos"`
+	Score        float32 `json:"score"`
+	SourceURL    string  `json:"source_url,omitempty"`
+}
+
+type stepResponse struct {
+	SubQuestion string   `json:"sub_question"`
+	Strategy    string   `json:"strategy,omitempty"`
+	Summary     string   `json:"summary"`
+	KeyFindings []string `json:"key_findings,omitempty"`
+	Documents   int      `json:"documents"`
+	DurationMs  int64    `json:"duration_ms"`
+}
+
+func newQueryResponse(state *workflow.State, elapsed time.Duration) *queryResponse {
+	resp := &queryResponse{
+		RunID:      state.RunID,
+		Answer:     state.FinalAnswer,
+		Steps:      make([]stepResponse, 0, len(state.PastSteps)),
+		DurationMs: elapsed.Milliseconds(),
+	}
+	if state.Plan != nil {
+		for _, step := range state.Plan.Steps {
+			resp.Plan = append(resp.Plan, step.SubQuestion)
+		}
+	}
+	for _, cited := range state.Citations {
+		resp.Citations = append(resp.Citations, citation(cited))
+	}
+	for _, past := range state.PastSteps {
+		resp.Steps = append(resp.Steps, stepResponse{
+			SubQuestion: past.Step.SubQuestion,
+			Strategy:    string(past.Strategy),
+			Summary:     past.Summary,
+			KeyFindings: past.KeyFindings,
+			Documents:   len(past.RetrievedDocs),
+			DurationMs:  past.ExecutionTimeMs,
+		})
+	}
+	return resp
+}
+
+func (s *server) handleQuery(w http.ResponseWriter, r *http.Request) {
+	var req queryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+	req.Question = strings.TrimSpace(req.Question)
+	if req.Question == "" {
+		writeError(w, http.StatusBadRequest, errors.New("question is required"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(s.config.QueryTimeoutSeconds)*time.Second)
+	defer cancel()
+
+	release, err := acquire(ctx, s.querySlots)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	defer release()
+
+	// Requests may lower the configured iteration limit, not raise it
+	state := workflow.NewState(req.Question)
+	if limit := s.system.Config.Workflow.MaxIterations; limit > 0 {
+		state.MaxIterations = limit
+	}
+	if req.MaxIterations > 0 && req.MaxIterations < state.MaxIterations {
+		state.MaxIterations = req.MaxIterations
+	}
+
+	if req.Stream || strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		s.streamQuery(ctx, w, state)
+		return
+	}
+
+	start := time.Now()
+	result, err := s.system.Executor.Execute(ctx, state)
+	if err != nil {
+		writeError(w, statusForError(err), fmt.Errorf("execution failed: %w", err))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newQueryResponse(result, time.Since(start)))
+}
+
+// streamQuery runs a query, sending executor events and answer text as
+// server-sent events, followed by a "result" or "error" event.
+func (s *server) streamQuery(ctx context.Context, w http.ResponseWriter, state *workflow.State) {
+	stream, err := newEventStream(w)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx = workflow.WithObserver(ctx, workflow.ObserverFunc(func(event workflow.Event) {
+		stream.send(string(event.Type), newEventPayload(event))
+	}))
+	ctx = agent.WithStreamHandler(ctx, func(delta string) {
+		stream.send("answer_delta", map[string]string{"delta": delta})
+	})
+
+	start := time.Now()
+	result, err := s.system.Executor.Execute(ctx, state)
+	if err != nil {
+		stream.send("error", errorResponse{Error: fmt.Sprintf("execution failed: %v", err)})
+		return
+	}
+
+	stream.send("result", newQueryResponse(result, time.Since(start)))
+}
+
+// eventPayload is the JSON form of a workflow.Event.
+type eventPayload struct {
+	Node         string           `json:"node"`
+	StepPosition int              `json:"step_position"`
+	TotalSteps   int              `json:"total_steps"`
+	SubQuestion  string           `json:"sub_question,omitempty"`
+	Strategy     string           `json:"strategy,omitempty"`
+	DurationMs   int64            `json:"duration_ms,omitempty"`
+	Error        string           `json:"error,omitempty"`
+	Summary      string           `json:"summary,omitempty"`
+	Decision     *decisionPayload `json:"decision,omitempty"`
+}
+
+type decisionPayload struct {
+	ShouldContinue bool    `json:"should_continue"`
+	Replan         bool    `json:"replan,omitempty"`
+	Reasoning      string  `json:"reasoning,omitempty"`
+	Confidence     float32 `json:"confidence"`
+}
+
+func newEventPayload(event workflow.Event) eventPayload {
+	payload := eventPayload{
+		Node:         event.Node,
+		StepPosition: event.StepPosition,
+		TotalSteps:   event.TotalSteps,
+		SubQuestion:  event.SubQuestion,
+		Strategy:     string(event.Strategy),
+		DurationMs:   event.Duration.Milliseconds(),
+	}
+	if event.Decision != nil {
+		payload.Decision = &decisionPayload{
+			ShouldContinue: event.Decision.ShouldContinue,
+			Replan:         event.Decision.Replan,
+			Reasoning:      event.Decision.Reasoning,
+			Confidence:     event.Decision.Confidence,
+		}
+	}
+	if event.Err != nil {
+		payload.Error = event.Err.Error()
+	}
+	if event.PastStep != nil {
+		payload.Summary = event.PastStep.Summary
+	}
+	return payload
+}
+
+// eventStream writes server-sent events. Sends are serialized since events
+// and answer text may arrive from different goroutines.
+type eventStream struct {
+	mu      sync.Mutex
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+func newEventStream(w http.ResponseWriter) (*eventStream, error) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, errors.New("streaming is not supported by this connection")
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	return &eventStream{w: w, flusher: flusher}, nil
+}
+
+func (e *eventStream) send(event string, data interface{}) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("failed to encode %s event: %v", event, err)
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	fmt.Fprintf(e.w, "event: %s\ndata: %s\n\n", event, encoded)
+	e.flusher.Flush()
+}
+
+// ingestResponse reports the outcome for each uploaded file.
+type ingestResponse struct {
+	Collection string           `json:"collection"`
+	Documents  []ingestedResult `json:"documents"`
+}
+
+type ingestedResult struct {
+	DocID  string `json:"doc_id"`
+	Format string `json:"format,omitempty"`
+	Title  string `json:"title,omitempty"`
+	Chunks int    `json:"chunks"`
+	Error  string `json:"error,omitempty"`
+}
+
+// handleIngest ingests files uploaded in the multipart "file" field. Each
+// file's name is its doc_id, unless a single file is sent with a "doc_id"
+// field. Schema derivation can be disabled with derive_schema=false.
+func (s *server) handleIngest(w http.ResponseWriter, r *http.Request) {
+	maxBytes := int64(s.config.MaxUploadMB) << 20
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+	if err := r.ParseMultipartForm(maxBytes); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid upload: %w", err))
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	files := r.MultipartForm.File["file"]
+	if len(files) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("at least one file is required"))
+		return
+	}
+	docID := r.FormValue("doc_id")
+	if docID != "" && len(files) > 1 {
+		writeError(w, http.StatusBadRequest, errors.New("doc_id can only be set when uploading a single file"))
+		return
+	}
+
+	deriveSchema := true
+	if value := r.FormValue("derive_schema"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid derive_schema: %w", err))
+			return
+		}
+		deriveSchema = parsed
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(s.config.IngestTimeoutSeconds)*time.Second)
+	defer cancel()
+
+	release, err := acquire(ctx, s.ingestSlots)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	defer release()
+
+	resp := ingestResponse{
+		Collection: s.system.Collection(""),
+		Documents:  make([]ingestedResult, 0, len(files)),
+	}
+	var ingested []*parser.Document
+	var chunkCounts []int
+	for _, header := range files {
+		result := ingestedResult{DocID: header.Filename}
+		if docID != "" {
+			result.DocID = docID
+		}
+
+		doc, chunks, err := s.ingestUpload(ctx, header, result.DocID, deriveSchema)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Format = doc.Format
+			result.Title = doc.Title
+			result.Chunks = chunks
+			ingested = append(ingested, doc)
+			chunkCounts = append(chunkCounts, chunks)
+		}
+		resp.Documents = append(resp.Documents, result)
+	}
+
+	if len(ingested) > 0 {
+		if err := s.recordIngested(resp.Collection, ingested, chunkCounts); err != nil {
+			log.Printf("failed to update ingest manifest: %v", err)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// recordIngested adds ingested documents to the ingest manifest, as the
+// CLI's ingest command does, so incremental ingests and document management
+// see them. The manifest is reloaded for each update since the CLI may have
+// changed it.
+func (s *server) recordIngested(collection string, docs []*parser.Document, chunks []int) error {
+	s.manifestMu.Lock()
+	defer s.manifestMu.Unlock()
+
+	path := s.system.Config.Ingest.ManifestFile()
+	manifest, err := common.LoadIngestManifest(path)
+	if err != nil {
+		return err
+	}
+	for i, doc := range docs {
+		manifest.RecordDocument(collection, doc, chunks[i])
+	}
+	return manifest.Save(path)
+}
+
+// ingestUpload parses an uploaded file by its name and ingests it under docID.
+func (s *server) ingestUpload(ctx context.Context, header *multipart.FileHeader, docID string, deriveSchema bool) (*parser.Document, int, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read upload: %w", err)
+	}
+	defer file.Close()
+
+	doc, err := s.system.ParseReader(file, header.Filename)
+	if err != nil {
+		return nil, 0, err
+	}
+	doc.SourcePath = docID
+
+	chunks, err := s.system.IngestDocument(ctx, doc, deriveSchema)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return doc, chunks, nil
+}
+
+func (s *server) handleDocuments(w http.ResponseWriter, r *http.Request) {
+	collection := s.system.Collection(r.URL.Query().Get("collection"))
+
+	documents, err := s.system.ListDocuments(r.Context(), collection)
+	if err != nil {
+		writeError(w, statusForError(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"collection": collection,
+		"documents":  documents,
+	})
+}
+
+func (s *server) handleSchema(w http.ResponseWriter, r *http.Request) {
+	docID := r.PathValue("docID")
+
+	if s.system.SchemaStore == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no schema for document: %s", docID))
+		return
+	}
+	entry, err := s.system.SchemaStore.Load(r.Context(), docID)
+	if errors.Is(err, schema.ErrSchemaNotFound) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no schema for document: %s", docID))
+		return
+	}
+	if err != nil {
+		writeError(w, statusForError(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, entry)
+}
+
+func (s *server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"status":       "ok",
+		"vector_store": s.system.VectorStore.Name(),
+	})
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// statusForError maps execution errors to HTTP status codes.
+func statusForError(err error) int {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, context.Canceled):
+		return 499 // Client closed request
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("failed to write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
@@ -0,0 +1,323 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"deep-thinking-agent/cmd/common"
+	"deep-thinking-agent/pkg/agent"
+	"deep-thinking-agent/pkg/document/parser"
+	"deep-thinking-agent/pkg/embedding"
+	"deep-thinking-agent/pkg/llm"
+	"deep-thinking-agent/pkg/nodes"
+	"deep-thinking-agent/pkg/schema"
+	"deep-thinking-agent/pkg/vectorstore"
+	"deep-thinking-agent/pkg/vectorstore/memory"
+	"deep-thinking-agent/pkg/workflow"
+)
+
+// researchNode completes a one-step plan without calling any services.
+type researchNode struct {
+	block         chan struct{} // if set, Execute waits for it to close
+	maxIterations int           // the iteration limit of the last run
+}
+
+func (n *researchNode) Name() string { return "research" }
+
+func (n *researchNode) Execute(ctx context.Context, state *workflow.State) (*workflow.NodeResult, error) {
+	if n.block != nil {
+		select {
+		case <-n.block:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	n.maxIterations = state.MaxIterations
+	step := workflow.PlanStep{Index: 0, SubQuestion: "What grew?"}
+	state.Plan = &workflow.Plan{Steps: []workflow.PlanStep{step}}
+	state.AddPastStep(workflow.PastStep{Step: step, Summary: "Revenue grew.", Strategy: workflow.StrategyVector})
+	state.IncrementStep()
+	state.ShouldContinue = false
+	return &workflow.NodeResult{UpdatedState: state}, nil
+}
+
+// answerLLM returns a fixed answer.
+type answerLLM struct{}
+
+func (a *answerLLM) Complete(ctx context.Context, req *llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	return &llm.CompletionResponse{Content: "Revenue grew by 10%."}, nil
+}
+func (a *answerLLM) Name() string            { return "fake" }
+func (a *answerLLM) ModelName() string       { return "fake" }
+func (a *answerLLM) SupportsStreaming() bool { return false }
+
+// fakeEmbedder returns a fixed vector for every text.
+type fakeEmbedder struct{}
+
+func (f *fakeEmbedder) Embed(ctx context.Context, req *embedding.EmbedRequest) (*embedding.EmbedResponse, error) {
+	vectors := make([]embedding.Vector, len(req.Texts))
+	for i, text := range req.Texts {
+		vectors[i] = embedding.Vector{Embedding: []float32{1, 0, 0}, Text: text}
+	}
+	return &embedding.EmbedResponse{Vectors: vectors}, nil
+}
+func (f *fakeEmbedder) Dimensions() int   { return 3 }
+func (f *fakeEmbedder) ModelName() string { return "fake" }
+
+func newTestServer(t *testing.T, research *researchNode, config common.ServerConfig) *server {
+	t.Helper()
+
+	store, err := memory.NewStore(&vectorstore.Config{DefaultCollection: "documents"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	synthesizer := agent.NewSynthesizer(&answerLLM{}, nil)
+	graph := workflow.NewGraph()
+	for _, node := range []workflow.Node{research, nodes.NewSynthesizerNode(synthesizer)} {
+		if err := graph.AddNode(node); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := graph.SetStart("research"); err != nil {
+		t.Fatal(err)
+	}
+	if err := graph.SetFinish("synthesizer"); err != nil {
+		t.Fatal(err)
+	}
+
+	system := &common.System{
+		Config: &common.Config{
+			VectorStore: common.VectorStoreConfig{DefaultCollection: "documents"},
+			Workflow:    common.WorkflowConfig{MaxIterations: 8},
+			Ingest:      common.IngestConfig{ManifestPath: filepath.Join(t.TempDir(), "manifest.json")},
+		},
+		Embedder:       &fakeEmbedder{},
+		VectorStore:    store,
+		SchemaResolver: schema.NewResolver(&answerLLM{}, nil),
+		SchemaStore:    schema.NewMemorySchemaStore(),
+		Parsers:        parser.NewParserRegistry(),
+		Synthesizer:    synthesizer,
+		Executor:       workflow.NewExecutor(graph, nil),
+	}
+	return newServer(system, config)
+}
+
+func TestServer_Query(t *testing.T) {
+	srv := newTestServer(t, &researchNode{}, common.ServerConfig{})
+	handler := srv.routes()
+
+	t.Run("sync", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(`{"question": "How did revenue change?"}`)))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+		}
+		var resp queryResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Answer != "Revenue grew by 10%." || len(resp.Steps) != 1 || resp.Steps[0].Strategy != "vector" {
+			t.Errorf("response = %+v", resp)
+		}
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(`{"question": "How did revenue change?", "stream": true}`))
+		handler.ServeHTTP(rec, req)
+
+		if ct := rec.Header().Get("Content-Type"); ct != "text/event-stream" {
+			t.Fatalf("Content-Type = %q", ct)
+		}
+		body := rec.Body.String()
+		for _, want := range []string{
+			"event: node_started\ndata: {\"node\":\"research\"",
+			"event: step_completed\n",
+			"event: answer_delta\ndata: {\"delta\":\"Revenue grew by 10%.\"}",
+			"event: result\ndata: {",
+		} {
+			if !strings.Contains(body, want) {
+				t.Errorf("stream missing %q:\n%s", want, body)
+			}
+		}
+		if strings.Index(body, "event: answer_delta") > strings.Index(body, "event: result") {
+			t.Error("result should be the last event")
+		}
+	})
+
+	t.Run("missing question", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(`{}`)))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("status = %d, want 400", rec.Code)
+		}
+	})
+}
+
+func TestServer_QueryConcurrencyLimit(t *testing.T) {
+	research := &researchNode{block: make(chan struct{})}
+	srv := newTestServer(t, research, common.ServerConfig{MaxConcurrentQueries: 1})
+	handler := srv.routes()
+
+	// Hold the only query slot
+	done := make(chan int)
+	go func() {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(`{"question": "first"}`)))
+		done <- rec.Code
+	}()
+	for len(srv.querySlots) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// A queued query gives up when its request ends before a slot frees up
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(`{"question": "second"}`)).WithContext(ctx))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503 while the slot is taken", rec.Code)
+	}
+
+	close(research.block)
+	if code := <-done; code != http.StatusOK {
+		t.Errorf("first query status = %d, want 200", code)
+	}
+}
+
+func TestServer_QueryMaxIterations(t *testing.T) {
+	research := &researchNode{}
+	handler := newTestServer(t, research, common.ServerConfig{}).routes()
+
+	tests := []struct {
+		body string
+		want int
+	}{
+		{`{"question": "How did revenue change?"}`, 8},
+		{`{"question": "How did revenue change?", "max_iterations": 3}`, 3},
+		{`{"question": "How did revenue change?", "max_iterations": 100}`, 8},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(tt.body)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+		}
+		if research.maxIterations != tt.want {
+			t.Errorf("%s: MaxIterations = %d, want %d", tt.body, research.maxIterations, tt.want)
+		}
+	}
+}
+
+func TestServer_IngestAndDocuments(t *testing.T) {
+	srv := newTestServer(t, &researchNode{}, common.ServerConfig{})
+	handler := srv.routes()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "report.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write([]byte("# Report\n\nRevenue grew by 10%."))
+	if part, err = form.CreateFormFile("file", "image.png"); err != nil {
+		t.Fatal(err)
+	}
+	part.Write([]byte{0x89})
+	form.WriteField("derive_schema", "false")
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/ingest", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	var ingested ingestResponse
+	if err := json.NewDecoder(rec.Body).Decode(&ingested); err != nil {
+		t.Fatal(err)
+	}
+	if len(ingested.Documents) != 2 {
+		t.Fatalf("documents = %+v", ingested.Documents)
+	}
+	if doc := ingested.Documents[0]; doc.DocID != "report.md" || doc.Chunks == 0 || doc.Error != "" {
+		t.Errorf("report.md result = %+v", doc)
+	}
+	if doc := ingested.Documents[1]; doc.Error == "" {
+		t.Errorf("image.png should report an unsupported format, got %+v", doc)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/documents", nil))
+	var listed struct {
+		Collection string                `json:"collection"`
+		Documents  []common.DocumentInfo `json:"documents"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&listed); err != nil {
+		t.Fatal(err)
+	}
+	if listed.Collection != "documents" || len(listed.Documents) != 1 || listed.Documents[0].DocID != "report.md" {
+		t.Errorf("documents = %+v", listed)
+	}
+
+	manifest, err := common.LoadIngestManifest(srv.system.Config.Ingest.ManifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, ok := manifest.Lookup("documents", "report.md")
+	if !ok || entry.Chunks != ingested.Documents[0].Chunks || entry.ContentHash == "" {
+		t.Errorf("manifest entry = %+v, want report.md with its chunks", entry)
+	}
+	if len(manifest.Documents) != 1 {
+		t.Errorf("manifest has %d documents, want only report.md", len(manifest.Documents))
+	}
+}
+
+func TestServer_SchemaAndHealth(t *testing.T) {
+	srv := newTestServer(t, &researchNode{}, common.ServerConfig{})
+	handler := srv.routes()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/schemas/reports/unknown.pdf", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("schema status = %d, want 404", rec.Code)
+	}
+
+	err := srv.system.SchemaStore.Save(context.Background(), &schema.IndexEntry{
+		DocID:    "reports/annual.pdf",
+		Schema:   &schema.DocumentSchema{DocID: "reports/annual.pdf", Title: "Annual Report"},
+		ChunkIDs: []string{"c0"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/schemas/reports/annual.pdf", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"chunk_ids":["c0"]`) {
+		t.Errorf("schema = %d %s", rec.Code, rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"status":"ok"`) {
+		t.Errorf("health = %d %s", rec.Code, rec.Body)
+	}
+}
@@ -8,12 +8,15 @@ package agent
 import (
 	"context"
 	"errors"
+	"reflect"
 	"strings"
 	"testing"
 
 	"deep-thinking-agent/pkg/embedding"
 	"deep-thinking-agent/pkg/llm"
+	"deep-thinking-agent/pkg/schema"
 	"deep-thinking-agent/pkg/vectorstore"
+	"deep-thinking-agent/pkg/websearch"
 	"deep-thinking-agent/pkg/workflow"
 )
 
@@ -37,6 +40,24 @@ func (m *mockLLMProvider) Name() string            { return "mock" }
 func (m *mockLLMProvider) ModelName() string       { return "mock-model" }
 func (m *mockLLMProvider) SupportsStreaming() bool { return false }
 
+// Mock streaming LLM Provider
+type mockStreamingLLMProvider struct {
+	mockLLMProvider
+	deltas []string
+}
+
+func (m *mockStreamingLLMProvider) SupportsStreaming() bool { return true }
+
+func (m *mockStreamingLLMProvider) CompleteStream(ctx context.Context, req *llm.CompletionRequest) (<-chan llm.StreamChunk, error) {
+	chunks := make(chan llm.StreamChunk, len(m.deltas)+1)
+	for _, delta := range m.deltas {
+		chunks <- llm.StreamChunk{Delta: delta}
+	}
+	chunks <- llm.StreamChunk{FinishReason: "stop"}
+	close(chunks)
+	return chunks, nil
+}
+
 // Mock Embedder
 type mockEmbedder struct {
 	embeddings [][]float32
@@ -148,6 +169,22 @@ func (m *mockVectorStore) GetCollection(ctx context.Context, name string) (*vect
 func (m *mockVectorStore) Close() error { return m.err }
 func (m *mockVectorStore) Name() string { return "mock-store" }
 
+// mockSearcher implements websearch.Searcher for testing
+type mockSearcher struct {
+	results    []websearch.Result
+	err        error
+	query      string
+	maxResults int
+}
+
+func (m *mockSearcher) Search(ctx context.Context, query string, maxResults int) ([]websearch.Result, error) {
+	m.query = query
+	m.maxResults = maxResults
+	return m.results, m.err
+}
+
+func (m *mockSearcher) Name() string { return "mock-search" }
+
 // Planner Tests
 func TestNewPlanner(t *testing.T) {
 	provider := &mockLLMProvider{}
@@ -367,6 +404,78 @@ func TestRewrite(t *testing.T) {
 	}
 }
 
+func TestRewriter_Expand(t *testing.T) {
+	response := `{
+  "query": "company revenue growth in fiscal 2024",
+  "paraphrases": ["How much did sales increase in 2024?", "company revenue growth in fiscal 2024", "  ", "What was the 2024 top-line growth?", "extra"],
+  "keywords": "revenue growth 2024",
+  "hypothetical_answer": "Revenue grew 12% in fiscal 2024, driven by services."
+}`
+
+	t.Run("variants", func(t *testing.T) {
+		rewriter := NewRewriter(&mockLLMProvider{response: response}, &RewriterConfig{
+			MaxTokens: 1000,
+			Expansion: &ExpansionConfig{Paraphrases: 2, Keywords: true, HyDE: true},
+		})
+
+		query, variants, err := rewriter.Expand(context.Background(), "revenue growth", nil)
+		if err != nil {
+			t.Fatalf("Expand() error = %v", err)
+		}
+		if query != "company revenue growth in fiscal 2024" {
+			t.Errorf("query = %q", query)
+		}
+
+		// Duplicates of the query and blank paraphrases are dropped before the cap
+		want := []workflow.QueryVariant{
+			{Kind: workflow.VariantParaphrase, Text: "How much did sales increase in 2024?"},
+			{Kind: workflow.VariantParaphrase, Text: "What was the 2024 top-line growth?"},
+			{Kind: workflow.VariantKeywords, Text: "revenue growth 2024"},
+			{Kind: workflow.VariantHyDE, Text: "Revenue grew 12% in fiscal 2024, driven by services."},
+		}
+		if !reflect.DeepEqual(variants, want) {
+			t.Errorf("variants = %+v, want %+v", variants, want)
+		}
+	})
+
+	t.Run("only configured variants", func(t *testing.T) {
+		rewriter := NewRewriter(&mockLLMProvider{response: response}, &RewriterConfig{
+			Expansion: &ExpansionConfig{HyDE: true},
+		})
+
+		_, variants, err := rewriter.Expand(context.Background(), "revenue growth", nil)
+		if err != nil {
+			t.Fatalf("Expand() error = %v", err)
+		}
+		if len(variants) != 1 || variants[0].Kind != workflow.VariantHyDE {
+			t.Errorf("variants = %+v, want only HyDE", variants)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		rewriter := NewRewriter(&mockLLMProvider{response: "enhanced query"}, nil)
+
+		query, variants, err := rewriter.Expand(context.Background(), "revenue growth", nil)
+		if err != nil || query != "enhanced query" || variants != nil {
+			t.Errorf("Expand() = %q, %v, %v", query, variants, err)
+		}
+	})
+
+	t.Run("unparseable response", func(t *testing.T) {
+		expansion := &RewriterConfig{Expansion: &ExpansionConfig{Paraphrases: 2}}
+
+		query, variants, err := NewRewriter(&mockLLMProvider{response: "{not json}"}, expansion).Expand(context.Background(), "revenue growth", nil)
+		if err != nil || query != "revenue growth" || variants != nil {
+			t.Errorf("Expand() = %q, %v, %v, want the original query", query, variants, err)
+		}
+
+		query, _, _ = NewRewriter(&mockLLMProvider{response: "plain rewrite"}, expansion).Expand(context.Background(), "revenue growth", nil)
+		if query != "plain rewrite" {
+			t.Errorf("query = %q, want the plain response", query)
+		}
+	})
+}
+
 // Supervisor Tests
 func TestNewSupervisor(t *testing.T) {
 	provider := &mockLLMProvider{}
@@ -477,6 +586,158 @@ func TestRetrieve(t *testing.T) {
 	}
 }
 
+func TestRetrieveStrategies(t *testing.T) {
+	store := &mockVectorStore{
+		searchResults: []vectorstore.Document{
+			{ID: "doc1", Content: "part number AX-4411 specification", Score: 0.9},
+		},
+	}
+	failingEmbedder := &mockEmbedder{err: errors.New("embed error")}
+
+	t.Run("keyword does not embed", func(t *testing.T) {
+		retriever := NewRetriever(store, failingEmbedder, nil)
+		docs, err := retriever.Retrieve(context.Background(), &workflow.RetrievalContext{
+			Query:    "AX-4411 specification",
+			Strategy: workflow.StrategyKeyword,
+			TopK:     5,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(docs) != 1 || docs[0].ID != "doc1" {
+			t.Errorf("expected keyword match on doc1, got %v", docs)
+		}
+	})
+
+	t.Run("vector requires embedding", func(t *testing.T) {
+		retriever := NewRetriever(store, failingEmbedder, nil)
+		_, err := retriever.Retrieve(context.Background(), &workflow.RetrievalContext{
+			Query:    "test",
+			Strategy: workflow.StrategyVector,
+		})
+		if err == nil {
+			t.Fatal("expected embedding error for vector strategy")
+		}
+	})
+
+	t.Run("schema filtered", func(t *testing.T) {
+		retriever := NewRetriever(store, &mockEmbedder{}, nil)
+		docs, err := retriever.Retrieve(context.Background(), &workflow.RetrievalContext{
+			Query:         "test",
+			Strategy:      workflow.StrategySchemaFiltered,
+			SchemaFilters: &workflow.SchemaFilters{SectionTypes: []string{"specs"}},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(docs) == 0 {
+			t.Error("no documents returned")
+		}
+	})
+
+	t.Run("empty strategy uses default", func(t *testing.T) {
+		retriever := NewRetriever(store, failingEmbedder, &RetrieverConfig{
+			DefaultTopK:     5,
+			DefaultStrategy: workflow.StrategyKeyword,
+		})
+		retrievalCtx := &workflow.RetrievalContext{Query: "AX-4411"}
+		if _, err := retriever.Retrieve(context.Background(), retrievalCtx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if retrievalCtx.Strategy != workflow.StrategyKeyword {
+			t.Errorf("Strategy = %v, want keyword", retrievalCtx.Strategy)
+		}
+	})
+
+	t.Run("web search", func(t *testing.T) {
+		searcher := &mockSearcher{results: []websearch.Result{
+			{Title: "Go", URL: "https://go.dev", Snippet: "The Go language"},
+		}}
+		retriever := NewRetriever(store, failingEmbedder, &RetrieverConfig{
+			DefaultTopK: 3,
+			WebSearcher: searcher,
+		})
+		if !retriever.SupportsWebSearch() {
+			t.Fatal("expected web search support")
+		}
+		docs, err := retriever.Retrieve(context.Background(), &workflow.RetrievalContext{
+			Query:    "golang",
+			Strategy: workflow.StrategyWebSearch,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if searcher.query != "golang" || searcher.maxResults != 3 {
+			t.Errorf("searcher called with %q/%d", searcher.query, searcher.maxResults)
+		}
+		if len(docs) != 1 || docs[0].Metadata[websearch.MetadataSourceURL] != "https://go.dev" {
+			t.Errorf("unexpected documents: %v", docs)
+		}
+	})
+
+	t.Run("web search not configured", func(t *testing.T) {
+		retriever := NewRetriever(store, &mockEmbedder{}, nil)
+		if retriever.SupportsWebSearch() {
+			t.Error("web search should be disabled by default")
+		}
+		_, err := retriever.Retrieve(context.Background(), &workflow.RetrievalContext{
+			Query:    "test",
+			Strategy: workflow.StrategyWebSearch,
+		})
+		if err == nil {
+			t.Fatal("expected error when web search is not configured")
+		}
+	})
+
+	t.Run("query variants are fused", func(t *testing.T) {
+		store := &mockVectorStore{searchResults: []vectorstore.Document{
+			{ID: "doc1", Content: "revenue grew in 2024"},
+			{ID: "doc2", Content: "sales increased strongly"},
+			{ID: "doc3", Content: "office relocation"},
+		}}
+		retriever := NewRetriever(store, failingEmbedder, nil)
+
+		retrievalCtx := &workflow.RetrievalContext{
+			Query:    "revenue",
+			Strategy: workflow.StrategyKeyword,
+			TopK:     5,
+		}
+		docs, err := retriever.Retrieve(context.Background(), retrievalCtx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(docs) != 1 {
+			t.Fatalf("expected only doc1 without variants, got %v", docs)
+		}
+
+		retrievalCtx.Variants = []workflow.QueryVariant{{Kind: workflow.VariantParaphrase, Text: "sales"}}
+		docs, err = retriever.Retrieve(context.Background(), retrievalCtx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(docs) != 2 || docs[0].ID != "doc1" || docs[1].ID != "doc2" {
+			t.Errorf("expected doc1 and doc2 fused, got %v", docs)
+		}
+
+		// HyDE passages are embedded, even for keyword steps
+		retrievalCtx.Variants = []workflow.QueryVariant{{Kind: workflow.VariantHyDE, Text: "Revenue grew 12%."}}
+		if _, err := retriever.Retrieve(context.Background(), retrievalCtx); err == nil {
+			t.Error("expected embedding error for HyDE variant")
+		}
+	})
+
+	t.Run("unknown strategy", func(t *testing.T) {
+		retriever := NewRetriever(store, &mockEmbedder{}, nil)
+		_, err := retriever.Retrieve(context.Background(), &workflow.RetrievalContext{
+			Query:    "test",
+			Strategy: workflow.RetrievalStrategy("graph"),
+		})
+		if err == nil {
+			t.Fatal("expected error for unknown strategy")
+		}
+	})
+}
+
 // Reranker Tests
 func TestNewReranker(t *testing.T) {
 	reranker := NewReranker(nil)
@@ -493,7 +754,10 @@ func TestRerank(t *testing.T) {
 	}
 
 	reranker := NewReranker(&RerankerConfig{TopN: 2})
-	reranked := reranker.Rerank(context.Background(), "query", docs)
+	reranked, err := reranker.Rerank(context.Background(), "query", docs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
 	if len(reranked) != 2 {
 		t.Errorf("expected 2 docs, got %d", len(reranked))
@@ -504,6 +768,204 @@ func TestRerank(t *testing.T) {
 	}
 }
 
+// mockScorer returns fixed scores for reranker tests
+type mockScorer struct {
+	scores []float32
+	err    error
+}
+
+func (m *mockScorer) Score(ctx context.Context, query string, docs []vectorstore.Document) ([]float32, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.scores, nil
+}
+
+func TestRerankWithScorer(t *testing.T) {
+	docs := []vectorstore.Document{
+		{ID: "doc1", Score: 0.9},
+		{ID: "doc2", Score: 0.8},
+		{ID: "doc3", Score: 0.7},
+	}
+
+	t.Run("scorer reorders documents", func(t *testing.T) {
+		reranker := NewReranker(&RerankerConfig{
+			TopN:   2,
+			Scorer: &mockScorer{scores: []float32{0.1, 0.5, 0.9}},
+		})
+		reranked, err := reranker.Rerank(context.Background(), "query", docs)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(reranked) != 2 {
+			t.Fatalf("expected 2 docs, got %d", len(reranked))
+		}
+		if reranked[0].ID != "doc3" || reranked[1].ID != "doc2" {
+			t.Errorf("expected [doc3, doc2], got [%s, %s]", reranked[0].ID, reranked[1].ID)
+		}
+		if reranked[0].Score != 0.9 {
+			t.Errorf("expected scorer score 0.9, got %v", reranked[0].Score)
+		}
+		// Input must not be mutated
+		if docs[0].Score != 0.9 {
+			t.Error("input documents were modified")
+		}
+	})
+
+	t.Run("min relevance score cuts results", func(t *testing.T) {
+		reranker := NewReranker(&RerankerConfig{
+			TopN:              3,
+			MinRelevanceScore: 0.6,
+			Scorer:            &mockScorer{scores: []float32{0.1, 0.5, 0.9}},
+		})
+		reranked, err := reranker.Rerank(context.Background(), "query", docs)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(reranked) != 1 || reranked[0].ID != "doc3" {
+			t.Errorf("expected only doc3, got %v", reranked)
+		}
+	})
+
+	t.Run("scorer error falls back to retrieval scores", func(t *testing.T) {
+		reranker := NewReranker(&RerankerConfig{
+			TopN:   1,
+			Scorer: &mockScorer{err: errors.New("scoring failed")},
+		})
+		reranked, err := reranker.Rerank(context.Background(), "query", docs)
+
+		if err == nil {
+			t.Error("expected the scorer error to be returned")
+		}
+		if len(reranked) != 1 || reranked[0].ID != "doc1" {
+			t.Errorf("expected doc1 by retrieval score, got %v", reranked)
+		}
+	})
+
+	t.Run("scorer error skips min relevance score", func(t *testing.T) {
+		// RRF-scale retrieval scores would all fall below the threshold
+		rrfDocs := []vectorstore.Document{
+			{ID: "doc1", Score: 0.033},
+			{ID: "doc2", Score: 0.016},
+		}
+		reranker := NewReranker(&RerankerConfig{
+			TopN:              3,
+			MinRelevanceScore: 0.5,
+			Scorer:            &mockScorer{err: errors.New("scoring failed")},
+		})
+		reranked, err := reranker.Rerank(context.Background(), "query", rrfDocs)
+
+		if err == nil {
+			t.Error("expected the scorer error to be returned")
+		}
+		if len(reranked) != 2 || reranked[0].ID != "doc1" {
+			t.Errorf("expected both docs in retrieval order, got %v", reranked)
+		}
+	})
+
+	t.Run("score count mismatch falls back", func(t *testing.T) {
+		reranker := NewReranker(&RerankerConfig{
+			TopN:              3,
+			MinRelevanceScore: 0.5,
+			Scorer:            &mockScorer{scores: []float32{0.9}},
+		})
+		reranked, err := reranker.Rerank(context.Background(), "query", docs)
+
+		if err == nil {
+			t.Error("expected an error for the missing scores")
+		}
+		if len(reranked) != 3 || reranked[0].ID != "doc1" {
+			t.Errorf("expected all docs in retrieval order, got %v", reranked)
+		}
+	})
+}
+
+func TestRerankWithoutScorerIgnoresMinRelevanceScore(t *testing.T) {
+	// Hybrid retrieval yields RRF scores of roughly 0.016-0.033
+	docs := []vectorstore.Document{
+		{ID: "doc1", Score: 0.016},
+		{ID: "doc2", Score: 0.033},
+		{ID: "doc3", Score: 0.025},
+	}
+
+	reranker := NewReranker(&RerankerConfig{TopN: 2, MinRelevanceScore: 0.5})
+	reranked, err := reranker.Rerank(context.Background(), "query", docs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(reranked) != 2 || reranked[0].ID != "doc2" || reranked[1].ID != "doc3" {
+		t.Errorf("expected [doc2, doc3], got %v", reranked)
+	}
+}
+
+func TestLLMScorer(t *testing.T) {
+	docs := []vectorstore.Document{
+		{ID: "doc1", Content: "content 1"},
+		{ID: "doc2", Content: "content 2"},
+		{ID: "doc3", Content: "content 3"},
+	}
+
+	t.Run("batches and normalizes scores", func(t *testing.T) {
+		provider := &sequenceLLMProvider{responses: []string{"[8, 12]", "Scores: [-1]"}}
+		scorer := NewLLMScorer(provider, &LLMScorerConfig{BatchSize: 2})
+
+		scores, err := scorer.Score(context.Background(), "query", docs)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if provider.calls != 2 {
+			t.Errorf("expected 2 LLM calls, got %d", provider.calls)
+		}
+
+		expected := []float32{0.8, 1.0, 0.0}
+		for i, want := range expected {
+			if scores[i] != want {
+				t.Errorf("scores[%d] = %v, want %v", i, scores[i], want)
+			}
+		}
+	})
+
+	t.Run("wrong score count", func(t *testing.T) {
+		scorer := NewLLMScorer(&mockLLMProvider{response: "[5]"}, nil)
+		if _, err := scorer.Score(context.Background(), "query", docs); err == nil {
+			t.Error("expected error for mismatched score count")
+		}
+	})
+
+	t.Run("unparseable response", func(t *testing.T) {
+		scorer := NewLLMScorer(&mockLLMProvider{response: "very relevant"}, nil)
+		if _, err := scorer.Score(context.Background(), "query", docs); err == nil {
+			t.Error("expected error for missing score array")
+		}
+	})
+
+	t.Run("LLM error", func(t *testing.T) {
+		scorer := NewLLMScorer(&mockLLMProvider{err: errors.New("llm error")}, nil)
+		if _, err := scorer.Score(context.Background(), "query", docs); err == nil {
+			t.Error("expected error from LLM failure")
+		}
+	})
+}
+
+// sequenceLLMProvider returns a different response on each call
+type sequenceLLMProvider struct {
+	responses []string
+	calls     int
+}
+
+func (m *sequenceLLMProvider) Complete(ctx context.Context, req *llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	resp := m.responses[m.calls%len(m.responses)]
+	m.calls++
+	return &llm.CompletionResponse{Content: resp}, nil
+}
+
+func (m *sequenceLLMProvider) Name() string            { return "sequence" }
+func (m *sequenceLLMProvider) ModelName() string       { return "sequence-model" }
+func (m *sequenceLLMProvider) SupportsStreaming() bool { return false }
+
 // Distiller Tests
 func TestNewDistiller(t *testing.T) {
 	provider := &mockLLMProvider{}
@@ -598,6 +1060,7 @@ func TestDecide(t *testing.T) {
 		state            *workflow.State
 		response         string
 		expectedContinue bool
+		expectedReplan   bool
 		wantErr          bool
 	}{
 		{
@@ -632,6 +1095,56 @@ func TestDecide(t *testing.T) {
 			expectedContinue: true,
 			wantErr:          false,
 		},
+		{
+			name: "LLM says replan",
+			state: &workflow.State{
+				OriginalQuestion: "test",
+				Plan:             &workflow.Plan{Steps: []workflow.PlanStep{{}, {}}},
+				CurrentStepIndex: 1,
+				MaxIterations:    10,
+			},
+			response:         "DECISION: replan\nREASONING: Step 1 found nothing\nSUGGESTED_ACTION: search the annual report\nCONFIDENCE: 0.7",
+			expectedContinue: true,
+			expectedReplan:   true,
+		},
+		{
+			name: "replan limit reached",
+			state: &workflow.State{
+				OriginalQuestion: "test",
+				Plan:             &workflow.Plan{Steps: []workflow.PlanStep{{}, {}}, Revision: 2},
+				CurrentStepIndex: 1,
+				MaxIterations:    10,
+			},
+			response:         "DECISION: replan\nREASONING: Step 1 found nothing",
+			expectedContinue: true,
+			expectedReplan:   false,
+		},
+		{
+			name: "plan complete, LLM says replan",
+			state: &workflow.State{
+				OriginalQuestion: "test",
+				Plan:             &workflow.Plan{Steps: []workflow.PlanStep{{Index: 0}, {Index: 1}}},
+				PastSteps:        []workflow.PastStep{{Step: workflow.PlanStep{Index: 0}}, {Step: workflow.PlanStep{Index: 1}, Summary: "Nothing found"}},
+				CurrentStepIndex: 2,
+				MaxIterations:    10,
+			},
+			response:         "DECISION: replan\nREASONING: The last step found nothing\nSUGGESTED_ACTION: search the annual report",
+			expectedContinue: true,
+			expectedReplan:   true,
+		},
+		{
+			name: "plan complete at replan limit",
+			state: &workflow.State{
+				OriginalQuestion: "test",
+				Plan:             &workflow.Plan{Steps: []workflow.PlanStep{{Index: 0}, {Index: 1}}, Revision: 2},
+				PastSteps:        []workflow.PastStep{{Step: workflow.PlanStep{Index: 0}}, {Step: workflow.PlanStep{Index: 1}}},
+				CurrentStepIndex: 2,
+				MaxIterations:    10,
+			},
+			response:         "DECISION: replan\nREASONING: The last step found nothing",
+			expectedContinue: false,
+			expectedReplan:   false,
+		},
 		{
 			name: "LLM says finish",
 			state: &workflow.State{
@@ -672,6 +1185,9 @@ func TestDecide(t *testing.T) {
 			if decision.ShouldContinue != tt.expectedContinue {
 				t.Errorf("ShouldContinue = %v, want %v", decision.ShouldContinue, tt.expectedContinue)
 			}
+			if decision.Replan != tt.expectedReplan {
+				t.Errorf("Replan = %v, want %v", decision.Replan, tt.expectedReplan)
+			}
 		})
 	}
 
@@ -684,6 +1200,24 @@ func TestDecide(t *testing.T) {
 	}
 }
 
+func TestBuildPolicyPrompt_CompletePlan(t *testing.T) {
+	policy := NewPolicy(&mockLLMProvider{}, nil)
+	state := &workflow.State{
+		OriginalQuestion: "test",
+		Plan:             &workflow.Plan{Steps: []workflow.PlanStep{{Index: 0, SubQuestion: "What was revenue?"}}},
+		PastSteps:        []workflow.PastStep{{Step: workflow.PlanStep{Index: 0, SubQuestion: "What was revenue?"}, Summary: "Nothing found"}},
+		CurrentStepIndex: 1,
+	}
+
+	prompt := policy.buildPolicyPrompt(state)
+	if !strings.Contains(prompt, "DECISION: replan OR finish") {
+		t.Errorf("a completed plan should offer only replan or finish:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "continue") {
+		t.Errorf("a completed plan should not offer continue:\n%s", prompt)
+	}
+}
+
 // Helper function tests
 func TestBuildContextFromPastSteps(t *testing.T) {
 	rewriter := NewRewriter(&mockLLMProvider{}, nil)
@@ -712,7 +1246,10 @@ func TestRerankWithScores(t *testing.T) {
 		{ID: "doc3", Content: "content 3", Score: 0.7},
 	}
 
-	reranked := reranker.RerankWithScores(context.Background(), "test query", docs)
+	reranked, err := reranker.RerankWithScores(context.Background(), "test query", docs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
 	if len(reranked) != 2 {
 		t.Errorf("expected 2 documents, got %d", len(reranked))
@@ -730,7 +1267,7 @@ func TestBuildMetadataFilters(t *testing.T) {
 	tests := []struct {
 		name     string
 		filters  *workflow.SchemaFilters
-		expected map[string]interface{}
+		expected vectorstore.Filter
 	}{
 		{
 			name:     "nil filters",
@@ -742,27 +1279,21 @@ func TestBuildMetadataFilters(t *testing.T) {
 			filters: &workflow.SchemaFilters{
 				DocumentIDs: []string{"doc1", "doc2"},
 			},
-			expected: map[string]interface{}{
-				"doc_id": []string{"doc1", "doc2"},
-			},
+			expected: vectorstore.In("doc_id", "doc1", "doc2"),
 		},
 		{
 			name: "section types only",
 			filters: &workflow.SchemaFilters{
 				SectionTypes: []string{"methodology", "results"},
 			},
-			expected: map[string]interface{}{
-				"section_type": []string{"methodology", "results"},
-			},
+			expected: vectorstore.In("section_type", "methodology", "results"),
 		},
 		{
 			name: "semantic tags only",
 			filters: &workflow.SchemaFilters{
 				SemanticTags: []string{"important", "key-finding"},
 			},
-			expected: map[string]interface{}{
-				"semantic_tags": []string{"important", "key-finding"},
-			},
+			expected: vectorstore.In("semantic_tags", "important", "key-finding"),
 		},
 		{
 			name: "custom attributes only",
@@ -772,10 +1303,10 @@ func TestBuildMetadataFilters(t *testing.T) {
 					"author": "Smith",
 				},
 			},
-			expected: map[string]interface{}{
-				"year":   2025,
-				"author": "Smith",
-			},
+			expected: vectorstore.And(
+				vectorstore.Eq("author", "Smith"),
+				vectorstore.Eq("year", 2025),
+			),
 		},
 		{
 			name: "all filters combined",
@@ -787,12 +1318,12 @@ func TestBuildMetadataFilters(t *testing.T) {
 					"lang": "en",
 				},
 			},
-			expected: map[string]interface{}{
-				"doc_id":        []string{"doc1"},
-				"section_type":  []string{"abstract"},
-				"semantic_tags": []string{"summary"},
-				"lang":          "en",
-			},
+			expected: vectorstore.And(
+				vectorstore.In("doc_id", "doc1"),
+				vectorstore.In("section_type", "abstract"),
+				vectorstore.In("semantic_tags", "summary"),
+				vectorstore.Eq("lang", "en"),
+			),
 		},
 	}
 
@@ -800,32 +1331,8 @@ func TestBuildMetadataFilters(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			result := retriever.buildMetadataFilters(tt.filters)
 
-			if tt.expected == nil {
-				if result != nil {
-					t.Errorf("expected nil, got %v", result)
-				}
-				return
-			}
-
-			if result == nil {
-				t.Fatal("expected non-nil result")
-			}
-
-			if len(result) != len(tt.expected) {
-				t.Errorf("expected %d filters, got %d", len(tt.expected), len(result))
-			}
-
-			for key := range tt.expected {
-				if result[key] == nil {
-					t.Errorf("missing key %s", key)
-				}
-			}
-
-			// Verify all keys from result exist in expected
-			for key := range result {
-				if tt.expected[key] == nil {
-					t.Errorf("unexpected key %s in result", key)
-				}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("buildMetadataFilters() = %#v, want %#v", result, tt.expected)
 			}
 		})
 	}
@@ -862,3 +1369,424 @@ func TestBuildStrategyPrompt(t *testing.T) {
 		t.Error("prompt should contain 'hybrid' strategy")
 	}
 }
+
+// Synthesizer Tests
+func TestNewSynthesizer(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *SynthesizerConfig
+		maxDocs int
+	}{
+		{"with nil config", nil, 10},
+		{"with custom config", &SynthesizerConfig{Temperature: 0.2, MaxTokens: 1000, MaxDocs: 5}, 5},
+		{"with zero max docs", &SynthesizerConfig{Temperature: 0.2, MaxTokens: 1000}, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			synthesizer := NewSynthesizer(&mockLLMProvider{}, tt.config)
+			if synthesizer == nil {
+				t.Fatal("NewSynthesizer returned nil")
+			}
+			if synthesizer.maxDocs != tt.maxDocs {
+				t.Errorf("maxDocs = %d, want %d", synthesizer.maxDocs, tt.maxDocs)
+			}
+		})
+	}
+}
+
+func TestSynthesize(t *testing.T) {
+	state := &workflow.State{
+		OriginalQuestion: "What are the main risks?",
+		PastSteps: []workflow.PastStep{
+			{
+				Step:        workflow.PlanStep{SubQuestion: "What risks are listed?"},
+				Summary:     "Several risks were identified.",
+				KeyFindings: []string{"Market risk", "Credit risk"},
+				RetrievedDocs: []vectorstore.Document{
+					{ID: "doc1", Content: "Market risk is significant."},
+				},
+			},
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		synthesizer := NewSynthesizer(&mockLLMProvider{response: "  The main risks are market and credit risk.  "}, nil)
+		answer, err := synthesizer.Synthesize(context.Background(), state)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if answer != "The main risks are market and credit risk." {
+			t.Errorf("unexpected answer: %q", answer)
+		}
+	})
+
+	t.Run("streaming", func(t *testing.T) {
+		provider := &mockStreamingLLMProvider{deltas: []string{"The main ", "risks are ", "market risk."}}
+		var streamed []string
+		synthesizer := NewSynthesizer(provider, &SynthesizerConfig{
+			StreamHandler: func(delta string) { streamed = append(streamed, delta) },
+		})
+
+		answer, err := synthesizer.Synthesize(context.Background(), state)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if answer != "The main risks are market risk." {
+			t.Errorf("unexpected answer: %q", answer)
+		}
+		if len(streamed) != 3 {
+			t.Errorf("expected 3 streamed deltas, got %d", len(streamed))
+		}
+	})
+
+	t.Run("stream handler without streaming provider", func(t *testing.T) {
+		var streamed []string
+		synthesizer := NewSynthesizer(&mockLLMProvider{response: "Whole answer"}, nil)
+		synthesizer.SetStreamHandler(func(delta string) { streamed = append(streamed, delta) })
+
+		if _, err := synthesizer.Synthesize(context.Background(), state); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(streamed) != 1 || streamed[0] != "Whole answer" {
+			t.Errorf("expected whole answer in one delta, got %q", streamed)
+		}
+	})
+
+	t.Run("context stream handler", func(t *testing.T) {
+		var own, perRun []string
+		synthesizer := NewSynthesizer(&mockLLMProvider{response: "Per-run answer"}, &SynthesizerConfig{
+			StreamHandler: func(delta string) { own = append(own, delta) },
+		})
+
+		ctx := WithStreamHandler(context.Background(), func(delta string) { perRun = append(perRun, delta) })
+		if _, err := synthesizer.Synthesize(ctx, state); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(perRun) != 1 || len(own) != 0 {
+			t.Errorf("context handler got %q, synthesizer handler got %q", perRun, own)
+		}
+	})
+
+	t.Run("nil state", func(t *testing.T) {
+		synthesizer := NewSynthesizer(&mockLLMProvider{}, nil)
+		if _, err := synthesizer.Synthesize(context.Background(), nil); err == nil {
+			t.Error("expected error for nil state")
+		}
+	})
+
+	t.Run("no past steps", func(t *testing.T) {
+		synthesizer := NewSynthesizer(&mockLLMProvider{}, nil)
+		if _, err := synthesizer.Synthesize(context.Background(), workflow.NewState("q")); err == nil {
+			t.Error("expected error for empty history")
+		}
+	})
+
+	t.Run("LLM error", func(t *testing.T) {
+		synthesizer := NewSynthesizer(&mockLLMProvider{err: errors.New("llm error")}, nil)
+		if _, err := synthesizer.Synthesize(context.Background(), state); err == nil {
+			t.Error("expected error from LLM failure")
+		}
+	})
+}
+
+func TestBuildSynthesisPrompt(t *testing.T) {
+	synthesizer := NewSynthesizer(&mockLLMProvider{}, &SynthesizerConfig{MaxDocs: 2})
+
+	state := &workflow.State{
+		OriginalQuestion: "Original question",
+		PastSteps: []workflow.PastStep{
+			{
+				Step:        workflow.PlanStep{SubQuestion: "Q1"},
+				Summary:     "S1",
+				KeyFindings: []string{"F1"},
+				RetrievedDocs: []vectorstore.Document{
+					{ID: "doc1", Content: "content one"},
+					{ID: "doc1", Content: "content one"},
+				},
+			},
+			{
+				Step: workflow.PlanStep{SubQuestion: "Q2"},
+				RetrievedDocs: []vectorstore.Document{
+					{ID: "doc2", Content: "content two"},
+					{ID: "doc3", Content: "content three"},
+				},
+			},
+		},
+	}
+
+	prompt := synthesizer.buildSynthesisPrompt(state)
+
+	for _, want := range []string{"Original question", "Q1", "S1", "- F1", "Q2", "content one", "content two"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q", want)
+		}
+	}
+
+	// Duplicates are collapsed and MaxDocs caps the excerpts
+	if strings.Count(prompt, "content one") != 1 {
+		t.Error("duplicate document should appear once")
+	}
+	if strings.Contains(prompt, "content three") {
+		t.Error("prompt should be limited to MaxDocs sources")
+	}
+}
+
+func TestBuildSynthesisPrompt_RenumbersCitations(t *testing.T) {
+	synthesizer := NewSynthesizer(&mockLLMProvider{}, &SynthesizerConfig{MaxDocs: 2})
+
+	state := &workflow.State{
+		OriginalQuestion: "Original question",
+		PastSteps: []workflow.PastStep{
+			{
+				Step:          workflow.PlanStep{SubQuestion: "Q1"},
+				Summary:       "Revenue grew [1].",
+				RetrievedDocs: []vectorstore.Document{{ID: "doc1", Content: "content one"}},
+			},
+			{
+				Step:          workflow.PlanStep{SubQuestion: "Q2"},
+				Summary:       "Costs fell [1, 3] and margins rose [2].",
+				KeyFindings:   []string{"Margins rose [2]"},
+				RetrievedDocs: []vectorstore.Document{{ID: "doc2", Content: "content two"}, {ID: "doc3", Content: "content three"}, {ID: "doc1", Content: "content one"}},
+			},
+		},
+	}
+
+	prompt := synthesizer.buildSynthesisPrompt(state)
+
+	// Step-local markers refer to global sources; doc3 is beyond MaxDocs
+	for _, want := range []string{"Revenue grew [1].", "Costs fell [2, 1] and margins rose.", "- Margins rose\n"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt)
+		}
+	}
+}
+
+func TestCite(t *testing.T) {
+	sources := []vectorstore.Document{
+		{ID: "c0", Score: 0.9, Metadata: map[string]interface{}{"doc_id": "report.pdf", "section_id": "s1", "section_title": "Risk Factors", "start_pos": 120, "end_pos": int64(480)}},
+		{ID: "c1", Score: 0.7, Metadata: map[string]interface{}{"doc_id": "notes.md", "start_pos": float64(0), "end_pos": float64(64)}},
+		{ID: "w0", Score: 0.5, Metadata: map[string]interface{}{"source_url": "https://example.com"}},
+	}
+
+	citations := Cite("Risks rose [2, 1]. Revenue grew [1]. See [7] and [2024].", sources)
+
+	want := []workflow.Citation{
+		{Marker: 1, ChunkID: "c0", DocID: "report.pdf", SectionID: "s1", SectionTitle: "Risk Factors", StartPos: 120, EndPos: 480, Score: 0.9},
+		{Marker: 2, ChunkID: "c1", DocID: "notes.md", StartPos: 0, EndPos: 64, Score: 0.7},
+	}
+	if !reflect.DeepEqual(citations, want) {
+		t.Errorf("Cite() = %+v, want %+v", citations, want)
+	}
+
+	if citations := Cite("No markers here.", sources); citations != nil {
+		t.Errorf("Cite() without markers = %+v, want nil", citations)
+	}
+	if citations := Cite("Web [3]", sources); len(citations) != 1 || citations[0].SourceURL != "https://example.com" {
+		t.Errorf("Cite() web source = %+v", citations)
+	}
+}
+
+func TestSchemaHintMapper(t *testing.T) {
+	schemas := map[string]*schema.DocumentSchema{
+		"10k.pdf": {
+			DocID: "10k.pdf",
+			Sections: []schema.Section{
+				{ID: "item1a", Title: "Item 1A. Risk Factors", Type: "risk_factors"},
+				{ID: "item7", Title: "Management's Discussion and Analysis", Type: "md_and_a"},
+				{ID: "item8", Title: "Financial Statements", Type: "financial_data"},
+			},
+			SemanticRegions: []schema.SemanticRegion{
+				{ID: "r1", Type: "outlook", Keywords: []string{"guidance", "forecast"}},
+			},
+		},
+		"paper.pdf": {
+			DocID: "paper.pdf",
+			Sections: []schema.Section{
+				{ID: "s2", Title: "Methodology", Type: "methodology"},
+				{ID: "s3", Title: "Results", Type: "results"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		hint   string
+		active *workflow.SchemaFilters
+		want   *workflow.SchemaFilters
+	}{
+		{
+			name: "section type from plural hint",
+			hint: "focus on risk sections",
+			want: &workflow.SchemaFilters{SectionTypes: []string{"risk_factors"}},
+		},
+		{
+			name: "section types named directly",
+			hint: "financial_data, methodology",
+			want: &workflow.SchemaFilters{SectionTypes: []string{"financial_data", "methodology"}},
+		},
+		{
+			name: "section title",
+			hint: "management discussion",
+			want: &workflow.SchemaFilters{DocumentIDs: []string{"10k.pdf"}, HierarchyPaths: []string{"item7"}},
+		},
+		{
+			name: "semantic keywords",
+			hint: "forward-looking guidance",
+			want: &workflow.SchemaFilters{SemanticTags: []string{"guidance"}},
+		},
+		{
+			name: "generic hint",
+			hint: "focus on specific document sections",
+			want: nil,
+		},
+		{
+			name: "no matching section",
+			hint: "executive compensation",
+			want: nil,
+		},
+		{
+			name:   "active document scope",
+			hint:   "results and risks",
+			active: &workflow.SchemaFilters{DocumentIDs: []string{"paper.pdf"}, MinRelevanceScore: 0.5},
+			want:   &workflow.SchemaFilters{DocumentIDs: []string{"paper.pdf"}, SectionTypes: []string{"results"}, MinRelevanceScore: 0.5},
+		},
+	}
+
+	mapper := NewSchemaHintMapper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mapper.Map(tt.hint, schemas, tt.active)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Map(%q) = %+v, want %+v", tt.hint, got, tt.want)
+			}
+		})
+	}
+
+	if got := mapper.Map("risk", nil, nil); got != nil {
+		t.Errorf("Map() without schemas = %+v, want nil", got)
+	}
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	response := `Here is the check:
+{
+  "claims": [
+    {"claim": "Revenue grew 10%", "status": "supported", "evidence": [1], "explanation": "Stated in evidence 1"},
+    {"claim": "Margins were 40%", "status": "Unsupported", "evidence": [], "explanation": "No margin figure"},
+    {"claim": "Costs fell", "status": "contradicted", "evidence": [2, 9], "explanation": "Evidence 2 says costs rose"},
+    {"claim": "Guidance was raised", "status": "unclear"},
+    {"claim": "  "}
+  ]
+}`
+	verifier := NewVerifier(&mockLLMProvider{response: response}, nil)
+
+	state := &workflow.State{
+		FinalAnswer: "Revenue grew 10% [1] with 40% margins while costs fell.",
+		PastSteps: []workflow.PastStep{
+			{RetrievedDocs: []vectorstore.Document{{ID: "c0", Content: "Revenue grew 10%."}}},
+		},
+		RerankedDocs: []vectorstore.Document{{ID: "c0", Content: "Revenue grew 10%."}, {ID: "c1", Content: "Costs rose 5%."}},
+	}
+
+	report, err := verifier.Verify(context.Background(), state)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	want := []workflow.ClaimVerification{
+		{Claim: "Revenue grew 10%", Status: workflow.ClaimSupported, Evidence: []int{1}, Explanation: "Stated in evidence 1"},
+		{Claim: "Margins were 40%", Status: workflow.ClaimUnsupported, Explanation: "No margin figure"},
+		{Claim: "Costs fell", Status: workflow.ClaimContradicted, Evidence: []int{2}, Explanation: "Evidence 2 says costs rose"},
+		{Claim: "Guidance was raised", Status: workflow.ClaimUnsupported},
+	}
+	if !reflect.DeepEqual(report.Claims, want) {
+		t.Errorf("Claims = %+v, want %+v", report.Claims, want)
+	}
+	if failed := report.Failed(); len(failed) != 3 {
+		t.Errorf("Failed() = %d claims, want 3", len(failed))
+	}
+
+	// Evidence is deduplicated across past steps and reranked documents
+	if evidence := verifier.collectEvidence(state); len(evidence) != 2 {
+		t.Errorf("collectEvidence() = %d documents, want 2", len(evidence))
+	}
+
+	if _, err := verifier.Verify(context.Background(), &workflow.State{}); err == nil {
+		t.Error("Verify() without an answer should fail")
+	}
+	if _, err := NewVerifier(&mockLLMProvider{response: "no json"}, nil).Verify(context.Background(), state); err == nil {
+		t.Error("Verify() should fail on an unparseable response")
+	}
+}
+
+func TestVerifier_CorrectiveSteps(t *testing.T) {
+	verifier := NewVerifier(&mockLLMProvider{}, &VerifierConfig{MaxCorrections: 2})
+
+	report := &workflow.VerificationReport{Claims: []workflow.ClaimVerification{
+		{Claim: "A", Status: workflow.ClaimSupported},
+		{Claim: "B", Status: workflow.ClaimUnsupported},
+		{Claim: "C", Status: workflow.ClaimContradicted},
+		{Claim: "D", Status: workflow.ClaimUnsupported},
+	}}
+	plan := &workflow.Plan{Steps: []workflow.PlanStep{{Index: 0}, {Index: 4}}}
+
+	steps := verifier.CorrectiveSteps(plan, report)
+	if len(steps) != 2 {
+		t.Fatalf("CorrectiveSteps() = %d steps, want 2", len(steps))
+	}
+	if steps[0].Index != 5 || steps[1].Index != 6 {
+		t.Errorf("step indices = %d, %d, want 5, 6", steps[0].Index, steps[1].Index)
+	}
+	if !strings.HasSuffix(steps[0].SubQuestion, ": B") || !strings.HasSuffix(steps[1].SubQuestion, ": C") {
+		t.Errorf("sub-questions = %q, %q", steps[0].SubQuestion, steps[1].SubQuestion)
+	}
+}
+
+func TestPlanner_Revise(t *testing.T) {
+	// Step 2 is dropped, step 1 is kept after a new step, and the new step
+	// reuses the completed step's index
+	response := `{
+  "steps": [
+    {"index": 0, "sub_question": "Which segments drove revenue?", "tool_type": "doc_search", "dependencies": [0]},
+    {"index": 1, "sub_question": "What were the margins?", "tool_type": "doc_search", "dependencies": [0, 7]},
+    {"index": 5, "sub_question": "   "}
+  ],
+  "reasoning": "Step 0 found no segment data"
+}`
+	provider := &mockLLMProvider{response: response}
+	planner := NewPlanner(provider, nil)
+
+	state := &workflow.State{
+		OriginalQuestion: "How did the company perform?",
+		Plan: &workflow.Plan{Steps: []workflow.PlanStep{
+			{Index: 0, SubQuestion: "What was revenue?"},
+			{Index: 1, SubQuestion: "What were the margins?"},
+			{Index: 2, SubQuestion: "What did the CEO say?"},
+		}},
+		PastSteps: []workflow.PastStep{{Step: workflow.PlanStep{Index: 0, SubQuestion: "What was revenue?"}, Summary: "No figures found"}},
+		Decision:  &workflow.PolicyDecision{ShouldContinue: true, Replan: true, Reasoning: "Revenue was not found"},
+	}
+
+	plan, err := planner.Revise(context.Background(), state)
+	if err != nil {
+		t.Fatalf("Revise() error = %v", err)
+	}
+
+	want := &workflow.Plan{
+		Steps: []workflow.PlanStep{
+			{Index: 0, SubQuestion: "What was revenue?"},
+			{Index: 3, SubQuestion: "Which segments drove revenue?", ToolType: "doc_search", Dependencies: []int{0}},
+			{Index: 1, SubQuestion: "What were the margins?", ToolType: "doc_search", Dependencies: []int{0}},
+		},
+		Reasoning: "Step 0 found no segment data",
+		Revision:  1,
+	}
+	if !reflect.DeepEqual(plan, want) {
+		t.Errorf("Revise() = %+v, want %+v", plan, want)
+	}
+
+	if _, err := planner.Revise(context.Background(), &workflow.State{}); err == nil {
+		t.Error("Revise() without a plan should fail")
+	}
+}
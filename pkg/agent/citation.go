@@ -0,0 +1,117 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package agent
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"deep-thinking-agent/pkg/vectorstore"
+	"deep-thinking-agent/pkg/websearch"
+	"deep-thinking-agent/pkg/workflow"
+)
+
+// citationMarker matches citation markers such as [1] and [2, 3].
+var citationMarker = regexp.MustCompile(`\[(\d+(?:\s*,\s*\d+)*)\]`)
+
+// Cite resolves the [n] citation markers in text against sources, where [n]
+// refers to sources[n-1]. Markers outside the sources are ignored. The
+// citations are ordered by marker, one per cited source.
+func Cite(text string, sources []vectorstore.Document) []workflow.Citation {
+	cited := make(map[int]bool)
+	for _, match := range citationMarker.FindAllStringSubmatch(text, -1) {
+		for _, number := range markerNumbers(match[1]) {
+			if number >= 1 && number <= len(sources) {
+				cited[number] = true
+			}
+		}
+	}
+	if len(cited) == 0 {
+		return nil
+	}
+
+	markers := make([]int, 0, len(cited))
+	for marker := range cited {
+		markers = append(markers, marker)
+	}
+	sort.Ints(markers)
+
+	citations := make([]workflow.Citation, len(markers))
+	for i, marker := range markers {
+		citations[i] = newCitation(marker, sources[marker-1])
+	}
+	return citations
+}
+
+// newCitation builds a citation from a source chunk and its ingest metadata.
+func newCitation(marker int, doc vectorstore.Document) workflow.Citation {
+	return workflow.Citation{
+		Marker:       marker,
+		ChunkID:      doc.ID,
+		DocID:        metadataString(doc.Metadata, "doc_id"),
+		SectionID:    metadataString(doc.Metadata, "section_id"),
+		SectionTitle: metadataString(doc.Metadata, "section_title"),
+		StartPos:     metadataInt(doc.Metadata, "start_pos"),
+		EndPos:       metadataInt(doc.Metadata, "end_pos"),
+		Score:        doc.Score,
+		SourceURL:    metadataString(doc.Metadata, websearch.MetadataSourceURL),
+	}
+}
+
+// spacedCitationMarker matches a citation marker with its leading spaces.
+var spacedCitationMarker = regexp.MustCompile(`\s*\[(\d+(?:\s*,\s*\d+)*)\]`)
+
+// renumberCitations rewrites the citation markers in text through numbers,
+// dropping numbers with no mapping and markers left empty.
+func renumberCitations(text string, numbers map[int]int) string {
+	return spacedCitationMarker.ReplaceAllStringFunc(text, func(marker string) string {
+		open := strings.IndexByte(marker, '[')
+		var renumbered []string
+		for _, number := range markerNumbers(strings.Trim(marker[open:], "[]")) {
+			if mapped, ok := numbers[number]; ok {
+				renumbered = append(renumbered, strconv.Itoa(mapped))
+			}
+		}
+		if len(renumbered) == 0 {
+			return ""
+		}
+		return marker[:open] + "[" + strings.Join(renumbered, ", ") + "]"
+	})
+}
+
+// markerNumbers parses the comma-separated numbers inside a marker.
+func markerNumbers(list string) []int {
+	var numbers []int
+	for _, field := range strings.Split(list, ",") {
+		if number, err := strconv.Atoi(strings.TrimSpace(field)); err == nil {
+			numbers = append(numbers, number)
+		}
+	}
+	return numbers
+}
+
+// metadataString returns a string metadata value, or "" if absent.
+func metadataString(metadata map[string]interface{}, key string) string {
+	value, _ := metadata[key].(string)
+	return value
+}
+
+// metadataInt returns an integer metadata value, or 0 if absent. Stores may
+// return integers as int64 or, after a JSON round trip, float64.
+func metadataInt(metadata map[string]interface{}, key string) int {
+	switch value := metadata[key].(type) {
+	case int:
+		return value
+	case int64:
+		return int(value)
+	case float64:
+		return int(value)
+	default:
+		return 0
+	}
+}
@@ -82,7 +82,8 @@ func (d *Distiller) buildDistillationPrompt(query string, docs []vectorstore.Doc
 	}
 
 	builder.WriteString("Synthesize the above documents into a coherent, comprehensive summary that addresses the query. ")
-	builder.WriteString("Include all relevant information while removing redundancy.")
+	builder.WriteString("Include all relevant information while removing redundancy. ")
+	builder.WriteString("Cite the documents supporting each statement by number in square brackets, e.g. [1] or [2, 3].")
 
 	return builder.String()
 }
@@ -96,6 +97,7 @@ Guidelines:
 - Preserve key facts, findings, and insights
 - Remove redundancy and irrelevant details
 - Maintain accuracy - do not add information not present in the documents
+- Cite supporting documents by number in square brackets, e.g. [1] or [2, 3]
 - Organize information logically
 - Be concise but comprehensive
 
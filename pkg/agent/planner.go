@@ -9,9 +9,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"deep-thinking-agent/pkg/llm"
+	"deep-thinking-agent/pkg/schema"
 	"deep-thinking-agent/pkg/workflow"
 )
 
@@ -47,7 +49,13 @@ func NewPlanner(llmProvider llm.Provider, config *PlannerConfig) *Planner {
 
 // Plan decomposes a question into an execution plan.
 func (p *Planner) Plan(ctx context.Context, question string) (*workflow.Plan, error) {
-	prompt := p.buildPlanningPrompt(question)
+	return p.PlanWithSchemas(ctx, question, nil)
+}
+
+// PlanWithSchemas decomposes a question into an execution plan, describing
+// the structure of the given documents so steps can target their sections.
+func (p *Planner) PlanWithSchemas(ctx context.Context, question string, schemas map[string]*schema.DocumentSchema) (*workflow.Plan, error) {
+	prompt := p.buildPlanningPrompt(question, schemas)
 
 	resp, err := p.llm.Complete(ctx, &llm.CompletionRequest{
 		Messages: []llm.Message{
@@ -70,12 +78,176 @@ func (p *Planner) Plan(ctx context.Context, question string) (*workflow.Plan, er
 	return plan, nil
 }
 
+// Revise replans the steps of state's plan that have not completed, using
+// the findings so far and the policy's most recent decision. The revised plan
+// keeps the completed steps, followed by the new remaining steps: existing
+// steps the LLM keeps retain their index, new steps are numbered after the
+// highest index used, and dependencies on unknown steps are dropped.
+func (p *Planner) Revise(ctx context.Context, state *workflow.State) (*workflow.Plan, error) {
+	if state == nil || state.Plan == nil {
+		return nil, fmt.Errorf("no plan to revise")
+	}
+
+	prompt := p.buildRevisionPrompt(state)
+
+	resp, err := p.llm.Complete(ctx, &llm.CompletionRequest{
+		Messages: []llm.Message{
+			{Role: "system", Content: systemPromptReviser},
+			{Role: "user", Content: prompt},
+		},
+		Temperature: p.temperature,
+		MaxTokens:   p.maxTokens,
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("LLM plan revision failed: %w", err)
+	}
+
+	revised, err := p.parsePlanResponse(resp.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse revised plan: %w", err)
+	}
+
+	return mergeRevisedPlan(state, revised), nil
+}
+
+// mergeRevisedPlan combines the completed steps of state's plan with the
+// revised remaining steps, renumbering steps so indices stay unique.
+func mergeRevisedPlan(state *workflow.State, revised *workflow.Plan) *workflow.Plan {
+	completed := make(map[int]bool, len(state.PastSteps))
+	for _, past := range state.PastSteps {
+		completed[past.Step.Index] = true
+	}
+
+	plan := &workflow.Plan{
+		Reasoning: revised.Reasoning,
+		Revision:  state.Plan.Revision + 1,
+	}
+
+	pending := make(map[int]bool)
+	next := 0
+	for _, step := range state.Plan.Steps {
+		if completed[step.Index] {
+			plan.Steps = append(plan.Steps, step)
+		} else {
+			pending[step.Index] = true
+		}
+		if step.Index >= next {
+			next = step.Index + 1
+		}
+	}
+
+	// Assign plan indices, keeping those of pending steps. Dependencies use
+	// the first step the LLM gave each index.
+	var steps []workflow.PlanStep
+	assigned := make([]int, 0, len(revised.Steps))
+	indices := make(map[int]int, len(revised.Steps))
+	for _, step := range revised.Steps {
+		if strings.TrimSpace(step.SubQuestion) == "" {
+			continue
+		}
+
+		index := next
+		if pending[step.Index] {
+			index = step.Index
+			delete(pending, step.Index)
+		} else {
+			next++
+		}
+		if _, seen := indices[step.Index]; !seen {
+			indices[step.Index] = index
+		}
+
+		steps = append(steps, step)
+		assigned = append(assigned, index)
+	}
+
+	for i, step := range steps {
+		deps := make([]int, 0, len(step.Dependencies))
+		for _, dep := range step.Dependencies {
+			if completed[dep] {
+				deps = append(deps, dep)
+			} else if mapped, ok := indices[dep]; ok && mapped != assigned[i] {
+				deps = append(deps, mapped)
+			}
+		}
+
+		step.Index = assigned[i]
+		step.Dependencies = deps
+		plan.Steps = append(plan.Steps, step)
+	}
+
+	return plan
+}
+
+// buildRevisionPrompt constructs the plan revision prompt.
+func (p *Planner) buildRevisionPrompt(state *workflow.State) string {
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("Original question: %s\n\n", state.OriginalQuestion))
+
+	builder.WriteString("Completed steps:\n")
+	for _, past := range state.PastSteps {
+		builder.WriteString(fmt.Sprintf("- [%d] %s (%d documents found)\n  Findings: %s\n",
+			past.Step.Index, past.Step.SubQuestion, len(past.RetrievedDocs), past.Summary))
+	}
+
+	builder.WriteString("\nRemaining steps:\n")
+	remaining := state.RemainingSteps()
+	for _, step := range remaining {
+		builder.WriteString(fmt.Sprintf("- [%d] %s (tool: %s)\n", step.Index, step.SubQuestion, step.ToolType))
+	}
+	if len(remaining) == 0 {
+		builder.WriteString("(none)\n")
+	}
+
+	if decision := state.Decision; decision != nil {
+		if decision.Reasoning != "" {
+			builder.WriteString(fmt.Sprintf("\nWhy a revision was requested: %s\n", decision.Reasoning))
+		}
+		if decision.SuggestedAction != "" {
+			builder.WriteString(fmt.Sprintf("Suggested focus: %s\n", decision.SuggestedAction))
+		}
+	}
+
+	if summary := describeSchemas(state.RelevantSchemas, maxPromptSchemas); summary != "" {
+		builder.WriteString("\nAvailable documents and their section types:\n")
+		builder.WriteString(summary)
+	}
+
+	builder.WriteString(`
+Revise the remaining steps so they close the gaps in the findings. You may keep, reword, drop or reorder remaining steps, and add new ones. Keep the index of a remaining step you keep; give new steps a new index. Do not repeat completed steps.
+
+CRITICAL: Respond with ONLY valid JSON in this EXACT format, listing only the remaining steps in execution order:
+{
+  "steps": [
+    {
+      "index": 3,
+      "sub_question": "What specific information does this step need?",
+      "tool_type": "doc_search",
+      "schema_hint": "focus on specific document sections",
+      "expected_outputs": ["expected finding"],
+      "dependencies": []
+    }
+  ],
+  "reasoning": "Explain what changed and why"
+}`)
+
+	return builder.String()
+}
+
 // buildPlanningPrompt constructs the planning prompt.
-func (p *Planner) buildPlanningPrompt(question string) string {
+func (p *Planner) buildPlanningPrompt(question string, schemas map[string]*schema.DocumentSchema) string {
+	documentInfo := ""
+	if summary := describeSchemas(schemas, maxPromptSchemas); summary != "" {
+		documentInfo = "\nAvailable documents and their section types:\n" + summary +
+			"\nWhen a step needs specific sections, name their section types in schema_hint.\n"
+	}
+
 	return fmt.Sprintf(`Decompose the following question into a sequential execution plan.
 
 Question: %s
-
+%s
 Create a plan with 2-5 steps that can be executed independently. Each step should:
 1. Answer a specific sub-question
 2. Specify which tool to use (doc_search, web_search, or schema_filter)
@@ -102,7 +274,50 @@ Respond with valid JSON in this EXACT format:
     }
   ],
   "reasoning": "Explain why this plan will effectively answer the question"
-}`, question)
+}`, question, documentInfo)
+}
+
+// maxPromptSchemas bounds how many document schemas are described in a prompt.
+const maxPromptSchemas = 20
+
+// describeSchemas summarizes document schemas for a prompt, one line per
+// document in doc ID order, listing the document's section types. At most
+// limit documents are described.
+func describeSchemas(schemas map[string]*schema.DocumentSchema, limit int) string {
+	ids := make([]string, 0, len(schemas))
+	for id, docSchema := range schemas {
+		if docSchema != nil {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	var b strings.Builder
+	for _, id := range ids {
+		docSchema := schemas[id]
+
+		var types []string
+		seen := make(map[string]bool)
+		for _, section := range docSchema.Sections {
+			if section.Type != "" && !seen[section.Type] {
+				seen[section.Type] = true
+				types = append(types, section.Type)
+			}
+		}
+
+		fmt.Fprintf(&b, "- %s", id)
+		if docSchema.Title != "" {
+			fmt.Fprintf(&b, " (%s)", docSchema.Title)
+		}
+		if len(types) > 0 {
+			fmt.Fprintf(&b, ": %s", strings.Join(types, ", "))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
 }
 
 // planStepJSON handles flexible JSON parsing for dependencies field.
@@ -279,3 +494,16 @@ Guidelines:
 - Indicate dependencies if a step requires information from previous steps
 
 Always respond with valid JSON matching the requested format.`
+
+const systemPromptReviser = `You are an expert query planner for a deep-thinking RAG system.
+
+Your task is to revise the remaining steps of an execution plan after some steps have run.
+
+Guidelines:
+- Add a step when findings raised a sub-question the plan does not cover
+- Reword or retarget a step whose predecessor found nothing, e.g. with a different tool or schema hint
+- Drop steps made redundant by the findings so far
+- Order steps so dependencies come first; dependencies may refer to completed steps
+- Keep the plan short: at most 5 remaining steps
+
+Always respond with valid JSON matching the requested format.`
@@ -15,17 +15,23 @@ import (
 )
 
 // Policy makes decisions about whether to continue or finish the workflow.
-// It evaluates progress, completeness, and iteration limits.
+// It evaluates progress, completeness, and iteration limits, and can ask for
+// the remaining plan to be revised when the findings reveal a gap.
 type Policy struct {
 	llm         llm.Provider
 	temperature float32
 	maxTokens   int
+	maxReplans  int
 }
 
 // PolicyConfig contains configuration for the policy agent.
 type PolicyConfig struct {
 	Temperature float32
 	MaxTokens   int
+
+	// MaxReplans limits how many times a run's plan may be revised;
+	// zero disables replanning
+	MaxReplans int
 }
 
 // NewPolicy creates a new policy agent.
@@ -34,6 +40,7 @@ func NewPolicy(llmProvider llm.Provider, config *PolicyConfig) *Policy {
 		config = &PolicyConfig{
 			Temperature: 0.3, // Low for consistent decisions
 			MaxTokens:   500,
+			MaxReplans:  2,
 		}
 	}
 
@@ -41,17 +48,21 @@ func NewPolicy(llmProvider llm.Provider, config *PolicyConfig) *Policy {
 		llm:         llmProvider,
 		temperature: config.Temperature,
 		maxTokens:   config.MaxTokens,
+		maxReplans:  config.MaxReplans,
 	}
 }
 
-// Decide determines whether the workflow should continue or finish.
+// Decide determines whether the workflow should continue, finish, or
+// continue after the planner revises the remaining steps.
 func (p *Policy) Decide(ctx context.Context, state *workflow.State) (*workflow.PolicyDecision, error) {
 	if state == nil {
 		return nil, fmt.Errorf("state is nil")
 	}
 
-	// Check hard limits first
-	if state.IsComplete() {
+	// Check hard limits first. A completed plan may still be revised, since
+	// its last step can find nothing or raise a new sub-question.
+	complete := state.IsComplete()
+	if complete && !p.canReplan(state) {
 		return &workflow.PolicyDecision{
 			ShouldContinue: false,
 			Reasoning:      "All plan steps completed",
@@ -84,9 +95,21 @@ func (p *Policy) Decide(ctx context.Context, state *workflow.State) (*workflow.P
 	}
 
 	decision := p.parsePolicyResponse(resp.Content)
+	if decision.Replan && !p.canReplan(state) {
+		decision.Replan = false
+	}
+	if complete && !decision.Replan {
+		// Nothing is left to continue with
+		decision.ShouldContinue = false
+	}
 	return decision, nil
 }
 
+// canReplan reports whether the state's plan may be revised again.
+func (p *Policy) canReplan(state *workflow.State) bool {
+	return state.Plan != nil && state.Plan.Revision < p.maxReplans
+}
+
 // buildPolicyPrompt constructs the policy decision prompt.
 func (p *Policy) buildPolicyPrompt(state *workflow.State) string {
 	var builder strings.Builder
@@ -100,11 +123,32 @@ func (p *Policy) buildPolicyPrompt(state *workflow.State) string {
 
 	builder.WriteString("Progress summary:\n")
 	for i, step := range state.PastSteps {
-		builder.WriteString(fmt.Sprintf("Step %d: %s\n", i+1, step.Summary))
+		builder.WriteString(fmt.Sprintf("Step %d (%d documents found): %s\n", i+1, len(step.RetrievedDocs), step.Summary))
 	}
 
-	builder.WriteString("\nDecide: Should the workflow continue to the next step, or is there sufficient information to answer the original question?")
-	builder.WriteString("\n\nRespond in format:\nDECISION: continue OR finish\nREASONING: [explanation]\nCONFIDENCE: [0.0-1.0]")
+	if !p.canReplan(state) {
+		builder.WriteString("\nDecide: Should the workflow continue to the next step, or is there sufficient information to answer the original question?")
+		builder.WriteString("\n\nRespond in format:\nDECISION: continue OR finish\nREASONING: [explanation]\nCONFIDENCE: [0.0-1.0]")
+		return builder.String()
+	}
+
+	remaining := state.RemainingSteps()
+	if len(remaining) == 0 {
+		builder.WriteString("\nAll planned steps are complete.")
+		builder.WriteString("\nDecide: Should the plan be extended with new steps, or is there sufficient information to answer the original question?")
+		builder.WriteString("\nReplan only if a step found nothing useful or the findings raised a sub-question the plan did not cover.")
+		builder.WriteString("\n\nRespond in format:\nDECISION: replan OR finish\nREASONING: [explanation]\nSUGGESTED_ACTION: [what the new steps should cover, if replanning]\nCONFIDENCE: [0.0-1.0]")
+		return builder.String()
+	}
+
+	builder.WriteString("\nRemaining steps:\n")
+	for _, step := range remaining {
+		builder.WriteString(fmt.Sprintf("- %s\n", step.SubQuestion))
+	}
+
+	builder.WriteString("\nDecide: Should the workflow continue to the next step, replan the remaining steps, or is there sufficient information to answer the original question?")
+	builder.WriteString("\nReplan only if a step found nothing useful or the findings raised a sub-question the remaining steps do not cover.")
+	builder.WriteString("\n\nRespond in format:\nDECISION: continue OR replan OR finish\nREASONING: [explanation]\nSUGGESTED_ACTION: [what the revised plan should cover, if replanning]\nCONFIDENCE: [0.0-1.0]")
 
 	return builder.String()
 }
@@ -126,9 +170,15 @@ func (p *Policy) parsePolicyResponse(response string) *workflow.PolicyDecision {
 			decisionText := strings.TrimSpace(strings.TrimPrefix(upper, "DECISION:"))
 			if strings.Contains(decisionText, "FINISH") || strings.Contains(decisionText, "STOP") {
 				decision.ShouldContinue = false
+			} else if strings.Contains(decisionText, "REPLAN") {
+				decision.Replan = true
 			}
 		}
 
+		if strings.HasPrefix(upper, "SUGGESTED_ACTION:") {
+			decision.SuggestedAction = strings.TrimSpace(line[len("SUGGESTED_ACTION:"):])
+		}
+
 		if strings.HasPrefix(upper, "REASONING:") {
 			decision.Reasoning = strings.TrimSpace(strings.TrimPrefix(line, "REASONING:"))
 			decision.Reasoning = strings.TrimSpace(strings.TrimPrefix(decision.Reasoning, "Reasoning:"))
@@ -153,6 +203,7 @@ Your task is to decide whether the workflow should continue to the next step or
 
 Decision criteria:
 - Continue if: More steps remain and would add valuable information
+- Replan if offered and: A step found nothing useful, or findings raised a new sub-question the remaining steps do not cover
 - Finish if: The original question can be adequately answered with current findings
 - Finish if: Additional steps would be redundant or provide diminishing returns
 
@@ -163,6 +214,6 @@ Guidelines:
 - Be decisive - avoid unnecessary iterations
 
 Respond in format:
-DECISION: continue OR finish
+DECISION: continue OR replan OR finish
 REASONING: [clear explanation]
 CONFIDENCE: [0.0-1.0]`
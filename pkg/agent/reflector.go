@@ -83,6 +83,8 @@ Provide:
 1. A concise summary (2-3 sentences) of what was found
 2. A bulleted list of 3-5 key findings
 
+Keep the [n] citation markers from the synthesized context on the statements they support.
+
 Format your response as:
 SUMMARY: [your summary here]
 
@@ -145,6 +147,7 @@ Guidelines:
 - Extract 3-5 specific key findings that answer the step's question
 - Focus on actionable information that informs future steps
 - Be precise and factual
+- Keep [n] citation markers from the context on the statements they support
 - Follow the requested format
 
 Always structure your response with:
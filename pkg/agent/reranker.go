@@ -7,21 +7,39 @@ package agent
 
 import (
 	"context"
+	"fmt"
 	"sort"
 
 	"deep-thinking-agent/pkg/vectorstore"
 )
 
 // Reranker applies precision ranking to filter retrieval results.
-// In this implementation, it uses a simple scoring approach.
-// Future: can be enhanced with cross-encoder models.
+// When a Scorer is configured, each candidate is rescored against the query
+// (e.g. by an LLM or cross-encoder). Otherwise the retrieval scores are used.
 type Reranker struct {
-	topN int
+	topN     int
+	minScore float32
+	scorer   Scorer
 }
 
 // RerankerConfig contains configuration for the reranker agent.
 type RerankerConfig struct {
 	TopN int
+
+	// MinRelevanceScore drops documents the Scorer rates below this
+	// threshold (0 disables). Retrieval scores are never thresholded: their
+	// scale depends on the strategy (RRF scores are around 0.01-0.03).
+	MinRelevanceScore float32
+
+	// Scorer rescores candidates against the query (nil uses retrieval scores)
+	Scorer Scorer
+}
+
+// Scorer assigns query relevance scores to candidate documents.
+// Implementations return one score per document, in input order,
+// normalized to the range 0.0-1.0.
+type Scorer interface {
+	Score(ctx context.Context, query string, docs []vectorstore.Document) ([]float32, error)
 }
 
 // NewReranker creates a new reranker agent.
@@ -33,35 +51,69 @@ func NewReranker(config *RerankerConfig) *Reranker {
 	}
 
 	return &Reranker{
-		topN: config.TopN,
+		topN:     config.TopN,
+		minScore: config.MinRelevanceScore,
+		scorer:   config.Scorer,
 	}
 }
 
 // Rerank selects the top N most relevant documents.
-func (r *Reranker) Rerank(ctx context.Context, query string, docs []vectorstore.Document) []vectorstore.Document {
+// If a scorer is configured, documents are rescored first and those below
+// the relevance threshold are dropped. On scorer failure the top N documents
+// by retrieval score are returned together with the error, so the caller
+// can continue with them and record what went wrong.
+func (r *Reranker) Rerank(ctx context.Context, query string, docs []vectorstore.Document) ([]vectorstore.Document, error) {
 	if len(docs) == 0 {
-		return docs
+		return docs, nil
 	}
 
-	// Sort by score (already present from vector search)
 	sorted := make([]vectorstore.Document, len(docs))
 	copy(sorted, docs)
 
-	sort.Slice(sorted, func(i, j int) bool {
+	var scoreErr error
+	rescored := false
+	if r.scorer != nil {
+		scores, err := r.scorer.Score(ctx, query, sorted)
+		switch {
+		case err != nil:
+			scoreErr = fmt.Errorf("relevance scoring failed: %w", err)
+		case len(scores) != len(sorted):
+			scoreErr = fmt.Errorf("relevance scoring returned %d scores for %d documents", len(scores), len(sorted))
+		default:
+			for i := range sorted {
+				sorted[i].Score = scores[i]
+			}
+			rescored = true
+		}
+	}
+
+	// Stable sort keeps retrieval order for ties
+	sort.SliceStable(sorted, func(i, j int) bool {
 		return sorted[i].Score > sorted[j].Score
 	})
 
+	// Drop documents below the relevance threshold; it is only meaningful
+	// for scores on the Scorer's 0.0-1.0 scale
+	if rescored && r.minScore > 0 {
+		filtered := sorted[:0]
+		for _, doc := range sorted {
+			if doc.Score >= r.minScore {
+				filtered = append(filtered, doc)
+			}
+		}
+		sorted = filtered
+	}
+
 	// Return top N
-	if len(sorted) > r.topN {
-		return sorted[:r.topN]
+	if r.topN > 0 && len(sorted) > r.topN {
+		sorted = sorted[:r.topN]
 	}
 
-	return sorted
+	return sorted, scoreErr
 }
 
-// RerankWithScores reranks and adds additional scoring.
-// This is a placeholder for future cross-encoder integration.
-func (r *Reranker) RerankWithScores(ctx context.Context, query string, docs []vectorstore.Document) []vectorstore.Document {
-	// For now, just use the existing scores
+// RerankWithScores reranks documents and returns them with their relevance
+// scores replaced by the scorer's output when a scorer is configured.
+func (r *Reranker) RerankWithScores(ctx context.Context, query string, docs []vectorstore.Document) ([]vectorstore.Document, error) {
 	return r.Rerank(ctx, query, docs)
 }
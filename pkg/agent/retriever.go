@@ -10,91 +10,173 @@ import (
 	"fmt"
 
 	"deep-thinking-agent/pkg/embedding"
+	"deep-thinking-agent/pkg/retrieval"
 	"deep-thinking-agent/pkg/vectorstore"
+	"deep-thinking-agent/pkg/websearch"
 	"deep-thinking-agent/pkg/workflow"
 )
 
 // Retriever performs schema-aware document retrieval.
-// It integrates with various retrieval strategies and applies schema filters.
+// It dispatches to the vector, keyword, hybrid, or schema-filtered retriever
+// selected by the supervisor and applies schema filters. Web search steps
+// are sent to the configured web searcher, if any.
 type Retriever struct {
-	vectorStore vectorstore.Store
-	embedder    embedding.Embedder
+	vector          *retrieval.VectorRetriever
+	keyword         *retrieval.KeywordRetriever
+	hybrid          *retrieval.HybridRetriever
+	schema          *retrieval.SchemaRetriever
+	web             websearch.Searcher
+	defaultTopK     int
+	defaultStrategy workflow.RetrievalStrategy
 }
 
 // RetrieverConfig contains configuration for the retriever agent.
 type RetrieverConfig struct {
 	DefaultTopK int
+
+	// DefaultStrategy is used when the retrieval context has no strategy set
+	DefaultStrategy workflow.RetrievalStrategy
+
+	// WebSearcher handles web_search steps (nil disables web search)
+	WebSearcher websearch.Searcher
 }
 
 // NewRetriever creates a new retriever agent.
 func NewRetriever(store vectorstore.Store, embedder embedding.Embedder, config *RetrieverConfig) *Retriever {
+	if config == nil {
+		config = &RetrieverConfig{
+			DefaultTopK:     10,
+			DefaultStrategy: workflow.StrategyHybrid,
+		}
+	}
+
+	defaultTopK := config.DefaultTopK
+	if defaultTopK <= 0 {
+		defaultTopK = 10
+	}
+
+	defaultStrategy := config.DefaultStrategy
+	if defaultStrategy == "" {
+		defaultStrategy = workflow.StrategyHybrid
+	}
+
+	vectorRet := retrieval.NewVectorRetriever(store, embedder)
+	keywordRet := retrieval.NewKeywordRetriever(store)
+
 	return &Retriever{
-		vectorStore: store,
-		embedder:    embedder,
+		vector:          vectorRet,
+		keyword:         keywordRet,
+		hybrid:          retrieval.NewHybridRetriever(vectorRet, keywordRet),
+		schema:          retrieval.NewSchemaRetriever(vectorRet),
+		web:             config.WebSearcher,
+		defaultTopK:     defaultTopK,
+		defaultStrategy: defaultStrategy,
 	}
 }
 
 // Retrieve fetches relevant documents using the specified strategy.
+// If the context has no strategy, the configured default is used and
+// recorded back onto the context so the choice is traceable.
+//
+// Query variants on the context are searched as well, each with the same
+// strategy except HyDE passages, which are searched by embedding. The result
+// lists are fused with RRF, so fused documents carry RRF scores. Web search
+// steps search only the query.
 func (r *Retriever) Retrieve(ctx context.Context, retrivalCtx *workflow.RetrievalContext) ([]vectorstore.Document, error) {
 	if retrivalCtx == nil {
 		return nil, fmt.Errorf("retrieval context is nil")
 	}
 
-	// Generate query embedding
-	embedResp, err := r.embedder.Embed(ctx, &embedding.EmbedRequest{
-		Texts: []string{retrivalCtx.Query},
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to embed query: %w", err)
+	if retrivalCtx.Strategy == "" {
+		retrivalCtx.Strategy = r.defaultStrategy
 	}
 
-	if len(embedResp.Vectors) == 0 {
-		return nil, fmt.Errorf("no embeddings generated")
+	topK := retrivalCtx.TopK
+	if topK <= 0 {
+		topK = r.defaultTopK
 	}
 
-	queryEmbedding := embedResp.Vectors[0].Embedding
-
-	// Build metadata filters from schema filters
-	metadataFilters := r.buildMetadataFilters(retrivalCtx.SchemaFilters)
-
-	// Perform search
-	searchResp, err := r.vectorStore.Search(ctx, &vectorstore.SearchRequest{
-		Vector: queryEmbedding,
-		TopK:   retrivalCtx.TopK,
-		Filter: metadataFilters,
-	})
-
+	docs, err := r.search(ctx, retrivalCtx.Strategy, retrivalCtx.Query, topK, retrivalCtx.SchemaFilters)
 	if err != nil {
-		return nil, fmt.Errorf("vector search failed: %w", err)
+		return nil, err
 	}
 
-	return searchResp.Documents, nil
-}
+	if len(retrivalCtx.Variants) == 0 || retrivalCtx.Strategy == workflow.StrategyWebSearch {
+		return docs, nil
+	}
 
-// buildMetadataFilters converts schema filters to vector store filters.
-func (r *Retriever) buildMetadataFilters(schemaFilters *workflow.SchemaFilters) map[string]interface{} {
-	if schemaFilters == nil {
-		return nil
+	lists := [][]vectorstore.Document{docs}
+	for _, variant := range retrivalCtx.Variants {
+		strategy := retrivalCtx.Strategy
+		if variant.Kind == workflow.VariantHyDE && strategy != workflow.StrategySchemaFiltered {
+			// Hypothetical passages are written to be embedded
+			strategy = workflow.StrategyVector
+		}
+
+		variantDocs, err := r.search(ctx, strategy, variant.Text, topK, retrivalCtx.SchemaFilters)
+		if err != nil {
+			return nil, fmt.Errorf("%s variant: %w", variant.Kind, err)
+		}
+		lists = append(lists, variantDocs)
 	}
 
-	filters := make(map[string]interface{})
+	fused := retrieval.FuseRRF(retrieval.DefaultRRFK, lists...)
+	if len(fused) > topK {
+		fused = fused[:topK]
+	}
+	return fused, nil
+}
 
-	if len(schemaFilters.DocumentIDs) > 0 {
-		filters["doc_id"] = schemaFilters.DocumentIDs
+// search runs a single query with the given strategy.
+func (r *Retriever) search(ctx context.Context, strategy workflow.RetrievalStrategy, query string, topK int, schemaFilters *workflow.SchemaFilters) ([]vectorstore.Document, error) {
+	// Build metadata filters from schema filters
+	metadataFilters := r.buildMetadataFilters(schemaFilters)
+
+	var docs []vectorstore.Document
+	var err error
+
+	switch strategy {
+	case workflow.StrategyVector:
+		docs, err = r.vector.Search(ctx, query, topK, metadataFilters)
+	case workflow.StrategyKeyword:
+		docs, err = r.keyword.Search(ctx, query, topK, metadataFilters)
+	case workflow.StrategyHybrid:
+		docs, err = r.hybrid.Search(ctx, query, topK, metadataFilters)
+	case workflow.StrategySchemaFiltered:
+		docs, err = r.schema.Search(ctx, query, topK, schemaFilters)
+	case workflow.StrategyWebSearch:
+		docs, err = r.searchWeb(ctx, query, topK)
+	default:
+		return nil, fmt.Errorf("unsupported retrieval strategy: %s", strategy)
 	}
 
-	if len(schemaFilters.SectionTypes) > 0 {
-		filters["section_type"] = schemaFilters.SectionTypes
+	if err != nil {
+		return nil, fmt.Errorf("%s retrieval failed: %w", strategy, err)
 	}
 
-	if len(schemaFilters.SemanticTags) > 0 {
-		filters["semantic_tags"] = schemaFilters.SemanticTags
+	return docs, nil
+}
+
+// SupportsWebSearch reports whether a web searcher is configured.
+func (r *Retriever) SupportsWebSearch() bool {
+	return r.web != nil
+}
+
+// searchWeb runs a web search and normalizes the results into documents.
+func (r *Retriever) searchWeb(ctx context.Context, query string, topK int) ([]vectorstore.Document, error) {
+	if r.web == nil {
+		return nil, fmt.Errorf("web search is not configured")
 	}
 
-	// Add custom attributes
-	for key, value := range schemaFilters.CustomAttributes {
-		filters[key] = value
+	results, err := r.web.Search(ctx, query, topK)
+	if err != nil {
+		return nil, err
 	}
 
-	return filters
+	return websearch.ToDocuments(results), nil
+}
+
+// buildMetadataFilters converts schema filters to vector store filters.
+func (r *Retriever) buildMetadataFilters(schemaFilters *workflow.SchemaFilters) vectorstore.Filter {
+	return retrieval.MetadataFilter(schemaFilters)
 }
@@ -7,6 +7,7 @@ package agent
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -15,17 +16,39 @@ import (
 )
 
 // Rewriter enhances queries for better retrieval using a fast LLM.
-// It expands queries with synonyms, related terms, and contextual information.
+// It expands queries with synonyms, related terms, and contextual information,
+// and can generate query variants that are searched alongside the query.
 type Rewriter struct {
 	llm         llm.Provider
 	temperature float32
 	maxTokens   int
+	expansion   *ExpansionConfig
 }
 
 // RewriterConfig contains configuration for the rewriter agent.
 type RewriterConfig struct {
 	Temperature float32
 	MaxTokens   int
+
+	// Expansion enables query variants (nil rewrites to a single query)
+	Expansion *ExpansionConfig
+}
+
+// ExpansionConfig selects the query variants the rewriter generates.
+type ExpansionConfig struct {
+	// Paraphrases is the number of alternative phrasings of the query
+	Paraphrases int
+
+	// Keywords adds a variant reduced to the query's key terms
+	Keywords bool
+
+	// HyDE adds a hypothetical answer passage, searched by embedding
+	HyDE bool
+}
+
+// enabled reports whether the configuration asks for any variants.
+func (c *ExpansionConfig) enabled() bool {
+	return c != nil && (c.Paraphrases > 0 || c.Keywords || c.HyDE)
 }
 
 // NewRewriter creates a new rewriter agent.
@@ -41,6 +64,7 @@ func NewRewriter(llmProvider llm.Provider, config *RewriterConfig) *Rewriter {
 		llm:         llmProvider,
 		temperature: config.Temperature,
 		maxTokens:   config.MaxTokens,
+		expansion:   config.Expansion,
 	}
 }
 
@@ -76,6 +100,135 @@ func (r *Rewriter) Rewrite(ctx context.Context, query string, state *workflow.St
 	return rewritten, nil
 }
 
+// Expand rewrites a query and generates the configured query variants with a
+// single LLM call. Without expansion configured it behaves like Rewrite and
+// returns no variants. A response that is not valid JSON is used as the
+// rewritten query if it has no JSON at all, or else the original query is
+// kept; either way no variants are returned.
+func (r *Rewriter) Expand(ctx context.Context, query string, state *workflow.State) (string, []workflow.QueryVariant, error) {
+	if !r.expansion.enabled() {
+		rewritten, err := r.Rewrite(ctx, query, state)
+		return rewritten, nil, err
+	}
+
+	contextInfo := ""
+	if state != nil && len(state.PastSteps) > 0 {
+		contextInfo = r.buildContextFromPastSteps(state.PastSteps)
+	}
+
+	prompt := r.buildExpansionPrompt(query, contextInfo)
+
+	resp, err := r.llm.Complete(ctx, &llm.CompletionRequest{
+		Messages: []llm.Message{
+			{Role: "system", Content: systemPromptExpander},
+			{Role: "user", Content: prompt},
+		},
+		Temperature: r.temperature,
+		MaxTokens:   r.maxTokens,
+	})
+
+	if err != nil {
+		return "", nil, fmt.Errorf("LLM rewrite failed: %w", err)
+	}
+
+	rewritten, variants := r.parseExpansionResponse(resp.Content, query)
+	return rewritten, variants, nil
+}
+
+// buildExpansionPrompt constructs the prompt for a rewrite with variants.
+func (r *Rewriter) buildExpansionPrompt(query, context string) string {
+	var builder strings.Builder
+
+	builder.WriteString("Rewrite the following query to be more effective for search, and generate alternative forms of it.\n\n")
+	builder.WriteString(fmt.Sprintf("Original query: %s\n\n", query))
+	if context != "" {
+		builder.WriteString(context)
+		builder.WriteString("\n")
+	}
+
+	builder.WriteString("Provide:\n")
+	builder.WriteString("- query: an enhanced version that expands key concepts and keeps the original intent\n")
+	fields := []string{`  "query": "enhanced query"`}
+
+	if r.expansion.Paraphrases > 0 {
+		builder.WriteString(fmt.Sprintf("- paraphrases: %d rephrasings that use different wording and terminology\n", r.expansion.Paraphrases))
+		fields = append(fields, `  "paraphrases": ["alternative phrasing"]`)
+	}
+	if r.expansion.Keywords {
+		builder.WriteString("- keywords: the essential search terms only, space separated, including names, numbers and technical terms\n")
+		fields = append(fields, `  "keywords": "key terms"`)
+	}
+	if r.expansion.HyDE {
+		builder.WriteString("- hypothetical_answer: a short passage (2-4 sentences) written as if quoted from a document that answers the query; plausible details are fine\n")
+		fields = append(fields, `  "hypothetical_answer": "passage"`)
+	}
+
+	builder.WriteString("\nRespond with ONLY a JSON object in this format:\n{\n")
+	builder.WriteString(strings.Join(fields, ",\n"))
+	builder.WriteString("\n}")
+
+	return builder.String()
+}
+
+// parseExpansionResponse extracts the rewritten query and its variants.
+// Variants that are empty or repeat the query or an earlier variant are
+// dropped, and paraphrases are capped at the configured number.
+func (r *Rewriter) parseExpansionResponse(response, query string) (string, []workflow.QueryVariant) {
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start == -1 || end < start {
+		if rewritten := strings.TrimSpace(response); rewritten != "" {
+			return rewritten, nil
+		}
+		return query, nil
+	}
+
+	var parsed struct {
+		Query              string   `json:"query"`
+		Paraphrases        []string `json:"paraphrases"`
+		Keywords           string   `json:"keywords"`
+		HypotheticalAnswer string   `json:"hypothetical_answer"`
+	}
+	if err := json.Unmarshal([]byte(response[start:end+1]), &parsed); err != nil {
+		return query, nil
+	}
+
+	rewritten := strings.TrimSpace(parsed.Query)
+	if rewritten == "" {
+		rewritten = query
+	}
+
+	seen := map[string]bool{strings.ToLower(rewritten): true}
+	var variants []workflow.QueryVariant
+	add := func(kind workflow.QueryVariantKind, text string) bool {
+		text = strings.TrimSpace(text)
+		if text == "" || seen[strings.ToLower(text)] {
+			return false
+		}
+		seen[strings.ToLower(text)] = true
+		variants = append(variants, workflow.QueryVariant{Kind: kind, Text: text})
+		return true
+	}
+
+	paraphrases := 0
+	for _, text := range parsed.Paraphrases {
+		if paraphrases >= r.expansion.Paraphrases {
+			break
+		}
+		if add(workflow.VariantParaphrase, text) {
+			paraphrases++
+		}
+	}
+	if r.expansion.Keywords {
+		add(workflow.VariantKeywords, parsed.Keywords)
+	}
+	if r.expansion.HyDE {
+		add(workflow.VariantHyDE, parsed.HypotheticalAnswer)
+	}
+
+	return rewritten, variants
+}
+
 // buildContextFromPastSteps creates context string from execution history.
 func (r *Rewriter) buildContextFromPastSteps(pastSteps []workflow.PastStep) string {
 	if len(pastSteps) == 0 {
@@ -137,3 +290,15 @@ Guidelines:
 - Consider execution context from previous steps if provided
 
 Return only the rewritten query without explanations or formatting.`
+
+const systemPromptExpander = `You are a query enhancement specialist for a RAG system.
+
+Your task is to rewrite a query and generate alternative forms of it, so that searching all of them finds relevant passages a single wording would miss.
+
+Guidelines:
+- Preserve the original intent in every form
+- Vary vocabulary across forms: synonyms, domain terminology, abbreviations and their expansions
+- Consider execution context from previous steps if provided
+- A hypothetical answer should read like a passage from a source document, not like a reply to the user
+
+Always respond with valid JSON matching the requested format.`
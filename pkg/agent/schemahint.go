@@ -0,0 +1,171 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package agent
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"deep-thinking-agent/pkg/schema"
+	"deep-thinking-agent/pkg/workflow"
+)
+
+// SchemaHintMapper translates a plan step's free-text schema hint
+// ("focus on risk sections") into concrete schema filters, using the section
+// types, section titles and semantic keywords of the documents in scope.
+//
+// Matching is lexical: hint words are compared with the words of each
+// vocabulary entry after lowercasing and light stemming, ignoring filler
+// words such as "focus" or "sections". Since the planner is shown the
+// section types of the documents, hints usually name them directly.
+type SchemaHintMapper struct {
+	stopWords map[string]bool
+}
+
+// defaultHintStopWords are hint words too generic to select sections.
+var defaultHintStopWords = []string{
+	"a", "about", "all", "an", "and", "any", "are", "as", "at", "be", "by",
+	"content", "data", "detail", "doc", "document", "focus", "for", "from",
+	"in", "info", "information", "is", "it", "look", "of", "on", "only", "or",
+	"part", "relevant", "section", "specific", "target", "text", "that",
+	"the", "their", "this", "to", "use", "with",
+}
+
+// NewSchemaHintMapper creates a schema hint mapper.
+func NewSchemaHintMapper() *SchemaHintMapper {
+	stopWords := make(map[string]bool, len(defaultHintStopWords))
+	for _, word := range defaultHintStopWords {
+		stopWords[word] = true
+	}
+
+	return &SchemaHintMapper{stopWords: stopWords}
+}
+
+// Map returns the active filters narrowed by a hint, or nil if the hint
+// matches nothing in the schemas, in which case the active filters should be
+// used unchanged. Active may be nil; when it names documents, only their
+// schemas are considered.
+//
+// Vector stores combine filter fields with AND, so only the most specific
+// kind of match is used: section types if any match, otherwise the hierarchy
+// paths (section IDs) of sections whose titles match, otherwise semantic tags.
+// Hierarchy path filters are limited to the documents the sections belong to.
+func (m *SchemaHintMapper) Map(hint string, schemas map[string]*schema.DocumentSchema, active *workflow.SchemaFilters) *workflow.SchemaFilters {
+	words := m.words(hint)
+	if len(words) == 0 || len(schemas) == 0 {
+		return nil
+	}
+
+	var inScope map[string]bool
+	if active != nil && len(active.DocumentIDs) > 0 {
+		inScope = make(map[string]bool, len(active.DocumentIDs))
+		for _, docID := range active.DocumentIDs {
+			inScope[docID] = true
+		}
+	}
+
+	// Iterate documents in a fixed order so filters are deterministic
+	docIDs := make([]string, 0, len(schemas))
+	for docID, docSchema := range schemas {
+		if docSchema != nil && (inScope == nil || inScope[docID]) {
+			docIDs = append(docIDs, docID)
+		}
+	}
+	sort.Strings(docIDs)
+
+	var sectionTypes, titleDocs, paths, tags []string
+	for _, docID := range docIDs {
+		docSchema := schemas[docID]
+
+		for _, section := range docSchema.Sections {
+			if section.Type != "" && m.matches(words, section.Type) {
+				sectionTypes = appendUnique(sectionTypes, section.Type)
+			}
+			if section.ID != "" && m.matches(words, section.Title) {
+				paths = appendUnique(paths, section.ID)
+				titleDocs = appendUnique(titleDocs, docID)
+			}
+		}
+
+		for _, region := range docSchema.SemanticRegions {
+			for _, keyword := range region.Keywords {
+				if m.matches(words, keyword) {
+					tags = appendUnique(tags, keyword)
+				}
+			}
+		}
+	}
+
+	filters := &workflow.SchemaFilters{}
+	if active != nil {
+		*filters = *active
+	}
+
+	switch {
+	case len(sectionTypes) > 0:
+		filters.SectionTypes = sectionTypes
+	case len(paths) > 0:
+		filters.DocumentIDs = titleDocs
+		filters.HierarchyPaths = paths
+	case len(tags) > 0:
+		filters.SemanticTags = tags
+	default:
+		return nil
+	}
+	return filters
+}
+
+// matches reports whether any significant word of term is in words.
+func (m *SchemaHintMapper) matches(words map[string]bool, term string) bool {
+	for word := range m.words(term) {
+		if words[word] {
+			return true
+		}
+	}
+	return false
+}
+
+// words splits text into stemmed, lowercase words, dropping stop words and
+// words shorter than three letters. Underscores separate words, so section
+// types like "risk_factors" split into "risk" and "factor".
+func (m *SchemaHintMapper) words(text string) map[string]bool {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	words := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		word := stem(field)
+		if len(word) < 3 || m.stopWords[word] || m.stopWords[field] {
+			continue
+		}
+		words[word] = true
+	}
+	return words
+}
+
+// stem reduces simple English plurals to their singular form.
+func stem(word string) string {
+	switch {
+	case len(word) > 4 && strings.HasSuffix(word, "ies"):
+		return word[:len(word)-3] + "y"
+	case len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss"):
+		return word[:len(word)-1]
+	default:
+		return word
+	}
+}
+
+// appendUnique appends value to values if it is not already present.
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
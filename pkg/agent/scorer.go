@@ -0,0 +1,172 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"deep-thinking-agent/pkg/llm"
+	"deep-thinking-agent/pkg/vectorstore"
+)
+
+// LLMScorer scores documents for query relevance using a fast LLM.
+// Candidates are sent in batches and the LLM rates each one, approximating
+// a cross-encoder without requiring a local model.
+type LLMScorer struct {
+	llm          llm.Provider
+	temperature  float32
+	maxTokens    int
+	batchSize    int
+	maxDocLength int
+}
+
+// LLMScorerConfig contains configuration for the LLM scorer.
+type LLMScorerConfig struct {
+	Temperature float32
+	MaxTokens   int
+
+	// BatchSize is the number of documents scored per LLM call
+	BatchSize int
+
+	// MaxDocLength truncates each document (in characters) in the prompt
+	MaxDocLength int
+}
+
+// NewLLMScorer creates a new LLM-based relevance scorer.
+func NewLLMScorer(llmProvider llm.Provider, config *LLMScorerConfig) *LLMScorer {
+	if config == nil {
+		config = &LLMScorerConfig{
+			Temperature:  0.0, // Deterministic scoring
+			MaxTokens:    300,
+			BatchSize:    5,
+			MaxDocLength: 800,
+		}
+	}
+
+	batchSize := config.BatchSize
+	if batchSize <= 0 {
+		batchSize = 5
+	}
+
+	maxDocLength := config.MaxDocLength
+	if maxDocLength <= 0 {
+		maxDocLength = 800
+	}
+
+	return &LLMScorer{
+		llm:          llmProvider,
+		temperature:  config.Temperature,
+		maxTokens:    config.MaxTokens,
+		batchSize:    batchSize,
+		maxDocLength: maxDocLength,
+	}
+}
+
+// Score rates each document's relevance to the query on a 0.0-1.0 scale.
+func (s *LLMScorer) Score(ctx context.Context, query string, docs []vectorstore.Document) ([]float32, error) {
+	scores := make([]float32, 0, len(docs))
+
+	for start := 0; start < len(docs); start += s.batchSize {
+		end := start + s.batchSize
+		if end > len(docs) {
+			end = len(docs)
+		}
+
+		batchScores, err := s.scoreBatch(ctx, query, docs[start:end])
+		if err != nil {
+			return nil, err
+		}
+		scores = append(scores, batchScores...)
+	}
+
+	return scores, nil
+}
+
+// scoreBatch scores a single batch of documents with one LLM call.
+func (s *LLMScorer) scoreBatch(ctx context.Context, query string, docs []vectorstore.Document) ([]float32, error) {
+	prompt := s.buildScoringPrompt(query, docs)
+
+	resp, err := s.llm.Complete(ctx, &llm.CompletionRequest{
+		Messages: []llm.Message{
+			{Role: "system", Content: systemPromptScorer},
+			{Role: "user", Content: prompt},
+		},
+		Temperature: s.temperature,
+		MaxTokens:   s.maxTokens,
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("LLM scoring failed: %w", err)
+	}
+
+	return s.parseScoringResponse(resp.Content, len(docs))
+}
+
+// buildScoringPrompt constructs the relevance scoring prompt for a batch.
+func (s *LLMScorer) buildScoringPrompt(query string, docs []vectorstore.Document) string {
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("Query: %s\n\n", query))
+
+	for i, doc := range docs {
+		builder.WriteString(fmt.Sprintf("--- Document %d ---\n", i+1))
+		builder.WriteString(truncate(doc.Content, s.maxDocLength))
+		builder.WriteString("\n\n")
+	}
+
+	builder.WriteString(fmt.Sprintf(`Rate how relevant each of the %d documents is to the query on a scale from 0 to 10.
+
+Respond with ONLY a JSON array of %d numbers in document order, for example: [8, 2, 5]`, len(docs), len(docs)))
+
+	return builder.String()
+}
+
+// parseScoringResponse extracts per-document scores and normalizes them to 0.0-1.0.
+func (s *LLMScorer) parseScoringResponse(response string, expected int) ([]float32, error) {
+	start := strings.Index(response, "[")
+	end := strings.LastIndex(response, "]")
+	if start == -1 || end == -1 || end < start {
+		return nil, fmt.Errorf("no score array found in response")
+	}
+
+	var raw []float64
+	if err := json.Unmarshal([]byte(response[start:end+1]), &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse scores: %w", err)
+	}
+
+	if len(raw) != expected {
+		return nil, fmt.Errorf("expected %d scores, got %d", expected, len(raw))
+	}
+
+	scores := make([]float32, len(raw))
+	for i, v := range raw {
+		if v < 0 {
+			v = 0
+		}
+		if v > 10 {
+			v = 10
+		}
+		scores[i] = float32(v / 10.0)
+	}
+
+	return scores, nil
+}
+
+const systemPromptScorer = `You are a relevance assessment expert for a RAG system.
+
+Your task is to judge how well each document answers or supports the query.
+
+Scoring guidelines:
+- 10: Directly and completely answers the query
+- 7-9: Contains key information needed for the query
+- 4-6: Partially relevant or provides useful context
+- 1-3: Mentions related topics but does not help answer the query
+- 0: Irrelevant
+
+Judge each document independently. Respond with only a JSON array of numbers.`
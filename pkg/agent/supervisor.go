@@ -73,6 +73,14 @@ func (s *Supervisor) buildStrategyPrompt(query string, state *workflow.State) st
 		step := state.CurrentStep()
 		contextInfo = fmt.Sprintf("\nTool type: %s\nSchema hint: %s", step.ToolType, step.SchemaHint)
 	}
+	if state != nil {
+		if summary := describeSchemas(state.RelevantSchemas, maxPromptSchemas); summary != "" {
+			contextInfo += "\nDocuments in scope and their section types:\n" + summary
+		}
+		if retrieval := state.Retrieval; retrieval != nil && retrieval.StepIndex == state.CurrentStepIndex && retrieval.FiltersFromHint {
+			contextInfo += "\nThe schema hint matched " + describeFilters(retrieval.SchemaFilters)
+		}
+	}
 
 	return fmt.Sprintf(`Select the optimal retrieval strategy for this query.
 
@@ -126,3 +134,17 @@ Consider:
 - Schema hints that suggest targeted retrieval
 
 Return only the strategy name without explanation.`
+
+// describeFilters summarizes the section filters of a retrieval for a prompt.
+func describeFilters(filters *workflow.SchemaFilters) string {
+	switch {
+	case len(filters.SectionTypes) > 0:
+		return "section types: " + strings.Join(filters.SectionTypes, ", ")
+	case len(filters.HierarchyPaths) > 0:
+		return "sections: " + strings.Join(filters.HierarchyPaths, ", ")
+	case len(filters.SemanticTags) > 0:
+		return "topics: " + strings.Join(filters.SemanticTags, ", ")
+	default:
+		return "no specific sections"
+	}
+}
@@ -0,0 +1,245 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"deep-thinking-agent/pkg/llm"
+	"deep-thinking-agent/pkg/vectorstore"
+	"deep-thinking-agent/pkg/workflow"
+)
+
+// Synthesizer produces the final answer once the workflow has finished.
+// It combines step summaries, key findings, and retrieved documents into a
+// single grounded response using the reasoning LLM.
+type Synthesizer struct {
+	llm         llm.Provider
+	temperature float32
+	maxTokens   int
+	maxDocs     int
+	onDelta     func(delta string)
+}
+
+// SynthesizerConfig contains configuration for the synthesizer agent.
+type SynthesizerConfig struct {
+	Temperature float32
+	MaxTokens   int
+
+	// MaxDocs limits how many source excerpts are included in the prompt
+	MaxDocs int
+
+	// StreamHandler, if set, receives the answer text as it is generated
+	StreamHandler func(delta string)
+}
+
+// NewSynthesizer creates a new synthesizer agent.
+func NewSynthesizer(llmProvider llm.Provider, config *SynthesizerConfig) *Synthesizer {
+	if config == nil {
+		config = &SynthesizerConfig{
+			Temperature: 0.3, // Low for faithful answers
+			MaxTokens:   2000,
+			MaxDocs:     10,
+		}
+	}
+
+	maxDocs := config.MaxDocs
+	if maxDocs <= 0 {
+		maxDocs = 10
+	}
+
+	return &Synthesizer{
+		llm:         llmProvider,
+		temperature: config.Temperature,
+		maxTokens:   config.MaxTokens,
+		maxDocs:     maxDocs,
+		onDelta:     config.StreamHandler,
+	}
+}
+
+// SetStreamHandler sets the function that receives answer text as it is
+// generated, or clears it when handler is nil. It must not be called while
+// Synthesize is running.
+func (s *Synthesizer) SetStreamHandler(handler func(delta string)) {
+	s.onDelta = handler
+}
+
+type streamHandlerKey struct{}
+
+// WithStreamHandler returns a context whose Synthesize calls deliver answer
+// text to handler instead of the synthesizer's own handler. Use it to stream
+// a single run when the synthesizer is shared between concurrent runs.
+func WithStreamHandler(ctx context.Context, handler func(delta string)) context.Context {
+	return context.WithValue(ctx, streamHandlerKey{}, handler)
+}
+
+// Synthesize generates the final answer to the original question from the
+// accumulated execution history.
+func (s *Synthesizer) Synthesize(ctx context.Context, state *workflow.State) (string, error) {
+	if state == nil {
+		return "", fmt.Errorf("state is nil")
+	}
+	if len(state.PastSteps) == 0 {
+		return "", fmt.Errorf("no findings to synthesize")
+	}
+
+	prompt := s.buildSynthesisPrompt(state)
+
+	onDelta := s.onDelta
+	if handler, ok := ctx.Value(streamHandlerKey{}).(func(delta string)); ok {
+		onDelta = handler
+	}
+
+	resp, err := llm.CompleteWithStream(ctx, s.llm, &llm.CompletionRequest{
+		Messages: []llm.Message{
+			{Role: "system", Content: systemPromptSynthesizer},
+			{Role: "user", Content: prompt},
+		},
+		Temperature: s.temperature,
+		MaxTokens:   s.maxTokens,
+	}, onDelta)
+
+	if err != nil {
+		return "", fmt.Errorf("LLM synthesis failed: %w", err)
+	}
+
+	return strings.TrimSpace(resp.Content), nil
+}
+
+// Citations resolves the [n] markers in the state's final answer to the
+// source excerpts they refer to.
+func (s *Synthesizer) Citations(state *workflow.State) []workflow.Citation {
+	if state == nil || state.FinalAnswer == "" {
+		return nil
+	}
+	return Cite(state.FinalAnswer, s.collectSourceDocs(state.PastSteps))
+}
+
+// buildSynthesisPrompt constructs the final answer prompt. Step summaries
+// cite their step's documents by position; those markers are renumbered to
+// match the source excerpts.
+func (s *Synthesizer) buildSynthesisPrompt(state *workflow.State) string {
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("Original question: %s\n\n", state.OriginalQuestion))
+
+	docs := s.collectSourceDocs(state.PastSteps)
+	sourceNumbers := make(map[string]int, len(docs))
+	for i, doc := range docs {
+		sourceNumbers[sourceKey(doc)] = i + 1
+	}
+
+	builder.WriteString("Research steps:\n\n")
+	for i, step := range state.PastSteps {
+		numbers := make(map[int]int, len(step.RetrievedDocs))
+		for j, doc := range step.RetrievedDocs {
+			if number, ok := sourceNumbers[sourceKey(doc)]; ok {
+				numbers[j+1] = number
+			}
+		}
+
+		builder.WriteString(fmt.Sprintf("Step %d: %s\n", i+1, step.Step.SubQuestion))
+		if step.Summary != "" {
+			builder.WriteString(fmt.Sprintf("Summary: %s\n", renumberCitations(step.Summary, numbers)))
+		}
+		if len(step.KeyFindings) > 0 {
+			builder.WriteString("Key findings:\n")
+			for _, finding := range step.KeyFindings {
+				builder.WriteString(fmt.Sprintf("- %s\n", renumberCitations(finding, numbers)))
+			}
+		}
+		builder.WriteString("\n")
+	}
+
+	if len(docs) > 0 {
+		builder.WriteString("Source excerpts:\n\n")
+		for i, doc := range docs {
+			builder.WriteString(fmt.Sprintf("--- Source %d%s ---\n", i+1, describeSource(doc)))
+			builder.WriteString(truncate(doc.Content, 1000))
+			builder.WriteString("\n\n")
+		}
+	}
+
+	builder.WriteString("Using only the research steps and source excerpts above, write a complete answer to the original question. ")
+	if len(docs) > 0 {
+		builder.WriteString("Cite the sources supporting each claim by number in square brackets, e.g. [1] or [2, 3]. ")
+	}
+	builder.WriteString("If the information is insufficient to answer part of the question, say so explicitly.")
+
+	return builder.String()
+}
+
+// describeSource returns " (doc, section)" for a source excerpt header, or ""
+// if the chunk has no document metadata.
+func describeSource(doc vectorstore.Document) string {
+	parts := make([]string, 0, 2)
+	if docID := metadataString(doc.Metadata, "doc_id"); docID != "" {
+		parts = append(parts, docID)
+	}
+	if title := metadataString(doc.Metadata, "section_title"); title != "" {
+		parts = append(parts, title)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+// collectSourceDocs gathers unique documents from past steps, up to maxDocs.
+func (s *Synthesizer) collectSourceDocs(pastSteps []workflow.PastStep) []vectorstore.Document {
+	seen := make(map[string]bool)
+	docs := make([]vectorstore.Document, 0, s.maxDocs)
+
+	for _, step := range pastSteps {
+		for _, doc := range step.RetrievedDocs {
+			if len(docs) >= s.maxDocs {
+				return docs
+			}
+			key := sourceKey(doc)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			docs = append(docs, doc)
+		}
+	}
+
+	return docs
+}
+
+// sourceKey identifies a document across steps by ID, or by content if it has none.
+func sourceKey(doc vectorstore.Document) string {
+	if doc.ID != "" {
+		return doc.ID
+	}
+	return doc.Content
+}
+
+// truncate shortens text to at most maxLen runes, appending an ellipsis if cut.
+func truncate(text string, maxLen int) string {
+	runes := []rune(text)
+	if len(runes) <= maxLen {
+		return text
+	}
+	return string(runes[:maxLen]) + "..."
+}
+
+const systemPromptSynthesizer = `You are an answer synthesis expert for a deep-thinking RAG system.
+
+Your task is to write the final answer to the user's original question using the research gathered by earlier steps.
+
+Guidelines:
+- Answer the original question directly and completely
+- Ground every claim in the provided summaries, findings, or source excerpts
+- Cite the source excerpts supporting each claim with their numbers in square brackets, e.g. [1] or [2, 3]
+- Do not introduce facts, numbers, or names that are not present in the provided material
+- Reconcile overlapping findings and note any contradictions between sources
+- State clearly when the available information does not cover part of the question
+- Organize the answer logically; use short paragraphs or bullet points where helpful
+
+Provide only the answer without meta-commentary about the research process.`
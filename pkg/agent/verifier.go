@@ -0,0 +1,274 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"deep-thinking-agent/pkg/llm"
+	"deep-thinking-agent/pkg/vectorstore"
+	"deep-thinking-agent/pkg/workflow"
+)
+
+// Verifier checks the final answer for faithfulness to the retrieved
+// evidence. A fast LLM splits the answer into atomic claims and judges each
+// one against the retrieved excerpts as supported, unsupported or
+// contradicted, catching facts and numbers that no source backs.
+type Verifier struct {
+	llm            llm.Provider
+	temperature    float32
+	maxTokens      int
+	maxEvidence    int
+	maxDocLength   int
+	maxCorrections int
+}
+
+// VerifierConfig contains configuration for the verifier agent.
+type VerifierConfig struct {
+	Temperature float32
+	MaxTokens   int
+
+	// MaxEvidence limits how many evidence excerpts are included in the prompt
+	MaxEvidence int
+
+	// MaxDocLength truncates each excerpt (in characters) in the prompt
+	MaxDocLength int
+
+	// MaxCorrections limits how many failed claims get a corrective plan step
+	MaxCorrections int
+}
+
+// NewVerifier creates a new verifier agent.
+func NewVerifier(llmProvider llm.Provider, config *VerifierConfig) *Verifier {
+	if config == nil {
+		config = &VerifierConfig{
+			Temperature: 0.0, // Deterministic verdicts
+			MaxTokens:   1500,
+		}
+	}
+
+	maxEvidence := config.MaxEvidence
+	if maxEvidence <= 0 {
+		maxEvidence = 15
+	}
+
+	maxDocLength := config.MaxDocLength
+	if maxDocLength <= 0 {
+		maxDocLength = 800
+	}
+
+	maxCorrections := config.MaxCorrections
+	if maxCorrections <= 0 {
+		maxCorrections = 3
+	}
+
+	return &Verifier{
+		llm:            llmProvider,
+		temperature:    config.Temperature,
+		maxTokens:      config.MaxTokens,
+		maxEvidence:    maxEvidence,
+		maxDocLength:   maxDocLength,
+		maxCorrections: maxCorrections,
+	}
+}
+
+// Verify checks the state's final answer against the documents retrieved by
+// its past steps and the current reranked documents.
+func (v *Verifier) Verify(ctx context.Context, state *workflow.State) (*workflow.VerificationReport, error) {
+	if state == nil {
+		return nil, fmt.Errorf("state is nil")
+	}
+	if state.FinalAnswer == "" {
+		return nil, fmt.Errorf("no answer to verify")
+	}
+
+	evidence := v.collectEvidence(state)
+	prompt := v.buildVerificationPrompt(state.FinalAnswer, evidence)
+
+	resp, err := v.llm.Complete(ctx, &llm.CompletionRequest{
+		Messages: []llm.Message{
+			{Role: "system", Content: systemPromptVerifier},
+			{Role: "user", Content: prompt},
+		},
+		Temperature: v.temperature,
+		MaxTokens:   v.maxTokens,
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("LLM verification failed: %w", err)
+	}
+
+	claims, err := v.parseVerificationResponse(resp.Content, len(evidence))
+	if err != nil {
+		return nil, err
+	}
+
+	return &workflow.VerificationReport{Claims: claims}, nil
+}
+
+// CorrectiveSteps returns plan steps that search for evidence on the failed
+// claims of a report, up to the configured limit. Step indices continue
+// after the highest index in plan, which may be nil.
+func (v *Verifier) CorrectiveSteps(plan *workflow.Plan, report *workflow.VerificationReport) []workflow.PlanStep {
+	failed := report.Failed()
+	if len(failed) > v.maxCorrections {
+		failed = failed[:v.maxCorrections]
+	}
+
+	next := 0
+	if plan != nil {
+		for _, step := range plan.Steps {
+			if step.Index >= next {
+				next = step.Index + 1
+			}
+		}
+	}
+
+	steps := make([]workflow.PlanStep, len(failed))
+	for i, claim := range failed {
+		steps[i] = workflow.PlanStep{
+			Index:           next + i,
+			SubQuestion:     fmt.Sprintf("Find evidence that confirms or refutes: %s", claim.Claim),
+			ToolType:        "doc_search",
+			ExpectedOutputs: []string{"Source passages stating or contradicting the claim"},
+		}
+	}
+	return steps
+}
+
+// collectEvidence gathers unique documents from past steps and the current
+// reranked documents, up to maxEvidence.
+func (v *Verifier) collectEvidence(state *workflow.State) []vectorstore.Document {
+	seen := make(map[string]bool)
+	evidence := make([]vectorstore.Document, 0, v.maxEvidence)
+
+	add := func(docs []vectorstore.Document) {
+		for _, doc := range docs {
+			if len(evidence) >= v.maxEvidence {
+				return
+			}
+			key := sourceKey(doc)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			evidence = append(evidence, doc)
+		}
+	}
+
+	for _, step := range state.PastSteps {
+		add(step.RetrievedDocs)
+	}
+	add(state.RerankedDocs)
+
+	return evidence
+}
+
+// buildVerificationPrompt constructs the claim verification prompt.
+func (v *Verifier) buildVerificationPrompt(answer string, evidence []vectorstore.Document) string {
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("Answer to verify:\n%s\n\n", answer))
+
+	if len(evidence) == 0 {
+		builder.WriteString("Evidence: none was retrieved.\n\n")
+	} else {
+		builder.WriteString("Evidence excerpts:\n\n")
+		for i, doc := range evidence {
+			builder.WriteString(fmt.Sprintf("--- Evidence %d ---\n", i+1))
+			builder.WriteString(truncate(doc.Content, v.maxDocLength))
+			builder.WriteString("\n\n")
+		}
+	}
+
+	builder.WriteString(`Split the answer into atomic factual claims and judge each one against the evidence.
+
+Respond with ONLY a JSON object in this format:
+{
+  "claims": [
+    {
+      "claim": "one atomic factual statement from the answer",
+      "status": "supported|unsupported|contradicted",
+      "evidence": [1],
+      "explanation": "brief justification"
+    }
+  ]
+}`)
+
+	return builder.String()
+}
+
+// parseVerificationResponse extracts claim verdicts from the LLM response.
+// Unknown statuses are treated as unsupported, and evidence numbers outside
+// the excerpts are dropped.
+func (v *Verifier) parseVerificationResponse(response string, evidenceCount int) ([]workflow.ClaimVerification, error) {
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start == -1 || end == -1 || end < start {
+		return nil, fmt.Errorf("no JSON found in response")
+	}
+
+	var parsed struct {
+		Claims []struct {
+			Claim       string `json:"claim"`
+			Status      string `json:"status"`
+			Evidence    []int  `json:"evidence"`
+			Explanation string `json:"explanation"`
+		} `json:"claims"`
+	}
+	if err := json.Unmarshal([]byte(response[start:end+1]), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse verification: %w", err)
+	}
+
+	claims := make([]workflow.ClaimVerification, 0, len(parsed.Claims))
+	for _, c := range parsed.Claims {
+		claim := strings.TrimSpace(c.Claim)
+		if claim == "" {
+			continue
+		}
+
+		status := workflow.ClaimStatus(strings.ToLower(strings.TrimSpace(c.Status)))
+		switch status {
+		case workflow.ClaimSupported, workflow.ClaimUnsupported, workflow.ClaimContradicted:
+		default:
+			status = workflow.ClaimUnsupported
+		}
+
+		var evidence []int
+		for _, n := range c.Evidence {
+			if n >= 1 && n <= evidenceCount {
+				evidence = append(evidence, n)
+			}
+		}
+
+		claims = append(claims, workflow.ClaimVerification{
+			Claim:       claim,
+			Status:      status,
+			Evidence:    evidence,
+			Explanation: strings.TrimSpace(c.Explanation),
+		})
+	}
+
+	return claims, nil
+}
+
+const systemPromptVerifier = `You are a fact-checking expert for a RAG system.
+
+Your task is to verify that an answer is faithful to the evidence it was based on.
+
+Guidelines:
+- Split the answer into atomic claims: each states exactly one fact, number, name, or relationship
+- Ignore citation markers such as [1] when extracting claims
+- "supported": the evidence states the claim or directly implies it
+- "contradicted": the evidence states something incompatible with the claim
+- "unsupported": the evidence neither supports nor contradicts the claim
+- Check numbers, dates, and names exactly; a figure not present in the evidence is unsupported
+- Judge only against the evidence, not your own knowledge
+
+Respond with only the JSON object.`
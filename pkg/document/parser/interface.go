@@ -5,7 +5,10 @@
 
 package parser
 
-import "io"
+import (
+	"io"
+	"strings"
+)
 
 // Document represents a parsed document with its content and metadata.
 type Document struct {
@@ -59,7 +62,8 @@ func NewParserRegistry() *ParserRegistry {
 	// Register default parsers
 	registry.Register(NewTextParser())
 	registry.Register(NewMarkdownParser())
-	// PDF and HTML parsers will be registered when implemented
+	registry.Register(NewPDFParser())
+	registry.Register(NewHTMLParser())
 
 	return registry
 }
@@ -72,9 +76,13 @@ func (r *ParserRegistry) Register(parser Parser) {
 }
 
 // GetParser returns the appropriate parser for the given file extension.
-// extension should include the dot (e.g., ".pdf", ".txt")
+// extension should include the dot (e.g., ".pdf", ".txt"); if no parser is
+// registered for it exactly, the lowercase extension is tried.
 func (r *ParserRegistry) GetParser(extension string) (Parser, bool) {
-	parser, ok := r.parsers[extension]
+	if parser, ok := r.parsers[extension]; ok {
+		return parser, true
+	}
+	parser, ok := r.parsers[strings.ToLower(extension)]
 	return parser, ok
 }
 
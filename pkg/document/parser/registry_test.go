@@ -150,3 +150,31 @@ func TestParserRegistry_MultipleFormats(t *testing.T) {
 		t.Error("markdown parser not found")
 	}
 }
+
+func TestParserRegistry_DefaultParsers(t *testing.T) {
+	registry := NewParserRegistry()
+
+	tests := []struct {
+		extension string
+		wantName  string
+	}{
+		{".txt", "text"},
+		{".md", "markdown"},
+		{".pdf", "pdf"},
+		{".html", "html"},
+		{".htm", "html"},
+		{".Pdf", "pdf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.extension, func(t *testing.T) {
+			parser, found := registry.GetParser(tt.extension)
+			if !found {
+				t.Fatalf("no default parser for %s", tt.extension)
+			}
+			if parser.Name() != tt.wantName {
+				t.Errorf("GetParser(%s) = %s, want %s", tt.extension, parser.Name(), tt.wantName)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package embedding
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultOllamaBaseURL is the default address of a local Ollama server.
+const DefaultOllamaBaseURL = "http://localhost:11434"
+
+// OllamaEmbedder implements the Embedder interface using Ollama's embeddings API.
+// Embedding dimensions are discovered from the first response rather than
+// looked up by model name, so any locally pulled embedding model works.
+type OllamaEmbedder struct {
+	client  *http.Client
+	model   string
+	baseURL string
+	config  *Config
+
+	mu         sync.Mutex
+	dimensions int
+}
+
+// NewOllamaEmbedder creates a new Ollama embedder instance.
+// model: Embedding model to use (e.g., "nomic-embed-text", "mxbai-embed-large")
+// config: Optional configuration (can be nil for defaults); BaseURL selects the server
+func NewOllamaEmbedder(model string, config *Config) (*OllamaEmbedder, error) {
+	if model == "" {
+		return nil, errors.New("embedding model name is required")
+	}
+
+	// Apply default config if not provided
+	if config == nil {
+		config = &Config{
+			Provider:       "ollama",
+			Model:          model,
+			TimeoutSeconds: 120,
+		}
+	}
+
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultOllamaBaseURL
+	}
+
+	return &OllamaEmbedder{
+		client:  &http.Client{},
+		model:   model,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		config:  config,
+	}, nil
+}
+
+// Embed generates embeddings for the given texts.
+// Ollama's /api/embeddings endpoint embeds one text per request.
+func (e *OllamaEmbedder) Embed(ctx context.Context, req *EmbedRequest) (*EmbedResponse, error) {
+	if req == nil {
+		return nil, errors.New("embed request cannot be nil")
+	}
+	if len(req.Texts) == 0 {
+		return nil, errors.New("texts cannot be empty")
+	}
+
+	// Apply timeout
+	if e.config.TimeoutSeconds > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(e.config.TimeoutSeconds)*time.Second)
+		defer cancel()
+	}
+
+	vectors := make([]Vector, 0, len(req.Texts))
+	for _, text := range req.Texts {
+		embedding, err := e.embedOne(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+
+		vector := Vector{
+			Embedding: embedding,
+			Text:      text,
+			Metadata:  make(map[string]interface{}),
+		}
+
+		// Copy metadata from request if provided
+		for k, v := range req.Metadata {
+			vector.Metadata[k] = v
+		}
+
+		vectors = append(vectors, vector)
+	}
+
+	return &EmbedResponse{
+		Vectors: vectors,
+		Model:   e.model,
+	}, nil
+}
+
+// embedOne embeds a single text and records the dimensions on first use.
+func (e *OllamaEmbedder) embedOne(ctx context.Context, text string) ([]float32, error) {
+	body, err := json.Marshal(map[string]string{
+		"model":  e.model,
+		"prompt": text,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL+"/api/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("Ollama embedding API error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var result struct {
+		Embedding []float32 `json:"embedding"`
+		Error     string    `json:"error"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil && resp.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		message := result.Error
+		if message == "" {
+			message = strings.TrimSpace(string(respBody))
+		}
+		return nil, &APIError{
+			Provider:   "Ollama",
+			StatusCode: resp.StatusCode,
+			Message:    message,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+
+	if len(result.Embedding) == 0 {
+		return nil, fmt.Errorf("Ollama returned an empty embedding for model %s", e.model)
+	}
+
+	e.mu.Lock()
+	if e.dimensions == 0 {
+		e.dimensions = len(result.Embedding)
+	}
+	e.mu.Unlock()
+
+	return result.Embedding, nil
+}
+
+// parseRetryAfter converts a Retry-After header given in seconds to a
+// duration, returning 0 if it is absent or not a number of seconds.
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// Dimensions returns the dimensionality of the embeddings produced by this embedder.
+// If no embedding has been generated yet, a probe request discovers it.
+// Returns 0 if the server cannot be reached.
+func (e *OllamaEmbedder) Dimensions() int {
+	e.mu.Lock()
+	dimensions := e.dimensions
+	e.mu.Unlock()
+
+	if dimensions > 0 {
+		return dimensions
+	}
+
+	ctx := context.Background()
+	if e.config.TimeoutSeconds > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(e.config.TimeoutSeconds)*time.Second)
+		defer cancel()
+	}
+
+	embedding, err := e.embedOne(ctx, "dimension probe")
+	if err != nil {
+		return 0
+	}
+	return len(embedding)
+}
+
+// ModelName returns the name of the embedding model being used.
+func (e *OllamaEmbedder) ModelName() string {
+	return e.model
+}
@@ -0,0 +1,128 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package embedding
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// newOllamaServer returns a fake Ollama server producing 4-dimensional embeddings.
+func newOllamaServer(t *testing.T, calls *int32) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		if r.URL.Path != "/api/embeddings" {
+			t.Errorf("path = %v, want /api/embeddings", r.URL.Path)
+		}
+
+		var req map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req["model"] != "nomic-embed-text" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "model not found"}`))
+			return
+		}
+
+		length := float32(len(req["prompt"]))
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"embedding": []float32{length, 0.1, 0.2, 0.3},
+		})
+	}))
+}
+
+func TestNewOllamaEmbedder(t *testing.T) {
+	if _, err := NewOllamaEmbedder("", nil); err == nil {
+		t.Error("expected error for missing model")
+	}
+
+	embedder, err := NewOllamaEmbedder("nomic-embed-text", nil)
+	if err != nil {
+		t.Fatalf("NewOllamaEmbedder() error = %v", err)
+	}
+	if embedder.baseURL != DefaultOllamaBaseURL {
+		t.Errorf("baseURL = %v, want %v", embedder.baseURL, DefaultOllamaBaseURL)
+	}
+	if embedder.ModelName() != "nomic-embed-text" {
+		t.Errorf("ModelName() = %v", embedder.ModelName())
+	}
+}
+
+func TestOllamaEmbedder_Embed(t *testing.T) {
+	var calls int32
+	server := newOllamaServer(t, &calls)
+	defer server.Close()
+
+	embedder, _ := NewOllamaEmbedder("nomic-embed-text", &Config{BaseURL: server.URL})
+
+	resp, err := embedder.Embed(context.Background(), &EmbedRequest{
+		Texts:    []string{"a", "bcd"},
+		Metadata: map[string]interface{}{"doc_id": "doc1"},
+	})
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+
+	if len(resp.Vectors) != 2 {
+		t.Fatalf("expected 2 vectors, got %d", len(resp.Vectors))
+	}
+	if resp.Vectors[1].Embedding[0] != 3 || resp.Vectors[1].Text != "bcd" {
+		t.Errorf("vectors out of order: %+v", resp.Vectors)
+	}
+	if resp.Vectors[0].Metadata["doc_id"] != "doc1" {
+		t.Errorf("metadata not copied: %v", resp.Vectors[0].Metadata)
+	}
+
+	// Dimensions are known after the first call without another request
+	before := atomic.LoadInt32(&calls)
+	if embedder.Dimensions() != 4 {
+		t.Errorf("Dimensions() = %d, want 4", embedder.Dimensions())
+	}
+	if atomic.LoadInt32(&calls) != before {
+		t.Error("Dimensions() should not call the server once known")
+	}
+}
+
+func TestOllamaEmbedder_DimensionsProbe(t *testing.T) {
+	var calls int32
+	server := newOllamaServer(t, &calls)
+	defer server.Close()
+
+	embedder, _ := NewOllamaEmbedder("nomic-embed-text", &Config{BaseURL: server.URL})
+	if embedder.Dimensions() != 4 {
+		t.Errorf("Dimensions() = %d, want 4", embedder.Dimensions())
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Errorf("expected a single probe request, got %d", calls)
+	}
+}
+
+func TestOllamaEmbedder_Errors(t *testing.T) {
+	var calls int32
+	server := newOllamaServer(t, &calls)
+	defer server.Close()
+
+	embedder, _ := NewOllamaEmbedder("unknown-model", &Config{BaseURL: server.URL})
+
+	if _, err := embedder.Embed(context.Background(), &EmbedRequest{Texts: []string{"a"}}); err == nil {
+		t.Error("expected error for unknown model")
+	}
+	if embedder.Dimensions() != 0 {
+		t.Errorf("Dimensions() = %d, want 0 when discovery fails", embedder.Dimensions())
+	}
+	if _, err := embedder.Embed(context.Background(), nil); err == nil {
+		t.Error("expected error for nil request")
+	}
+	if _, err := embedder.Embed(context.Background(), &EmbedRequest{}); err == nil {
+		t.Error("expected error for empty texts")
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package embedding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// APIError is returned by HTTP embedders when the server responds with an
+// error status. RetryAfter is set if the server sent a Retry-After header.
+type APIError struct {
+	Provider   string
+	StatusCode int
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s embedding API error (status %d): %s", e.Provider, e.StatusCode, e.Message)
+}
+
+// RetryConfig controls how RetryEmbedder retries failed requests.
+type RetryConfig struct {
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries int
+
+	// InitialBackoff is the wait before the first retry; it doubles on each retry
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the exponential backoff; a server's Retry-After is
+	// honored even if it is longer
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryConfig returns the default retry policy.
+func DefaultRetryConfig() *RetryConfig {
+	return &RetryConfig{
+		MaxRetries:     5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     30 * time.Second,
+	}
+}
+
+// RetryEmbedder wraps an Embedder and retries requests that fail with a rate
+// limit (HTTP 429), a server error, or a network error. Rate-limited requests
+// wait for the server's Retry-After when it is known, and back off
+// exponentially with jitter otherwise. Other errors are returned immediately.
+type RetryEmbedder struct {
+	embedder Embedder
+	config   *RetryConfig
+
+	// sleep waits between attempts; replaced in tests
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewRetryEmbedder creates a retrying wrapper around embedder.
+// config can be nil for defaults.
+func NewRetryEmbedder(embedder Embedder, config *RetryConfig) *RetryEmbedder {
+	if config == nil {
+		config = DefaultRetryConfig()
+	}
+
+	return &RetryEmbedder{
+		embedder: embedder,
+		config:   config,
+		sleep:    sleepContext,
+	}
+}
+
+// Embed generates embeddings, retrying transient failures.
+func (e *RetryEmbedder) Embed(ctx context.Context, req *EmbedRequest) (*EmbedResponse, error) {
+	backoff := e.config.InitialBackoff
+
+	for attempt := 0; ; attempt++ {
+		resp, err := e.embedder.Embed(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+
+		retryAfter, retryable := retryDelay(err)
+		if !retryable || attempt >= e.config.MaxRetries || ctx.Err() != nil {
+			if attempt > 0 {
+				return nil, fmt.Errorf("embedding failed after %d attempts: %w", attempt+1, err)
+			}
+			return nil, err
+		}
+
+		// Jitter spreads out retries from concurrent callers
+		wait := retryAfter
+		if wait <= 0 {
+			wait = backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+		}
+		if err := e.sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+
+		backoff *= 2
+		if e.config.MaxBackoff > 0 && backoff > e.config.MaxBackoff {
+			backoff = e.config.MaxBackoff
+		}
+	}
+}
+
+// Dimensions returns the dimensionality of the wrapped embedder.
+func (e *RetryEmbedder) Dimensions() int {
+	return e.embedder.Dimensions()
+}
+
+// ModelName returns the model name of the wrapped embedder.
+func (e *RetryEmbedder) ModelName() string {
+	return e.embedder.ModelName()
+}
+
+// retryDelay reports whether err is transient, along with the server's
+// requested delay if it sent one.
+func retryDelay(err error) (time.Duration, bool) {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return 0, false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.RetryAfter, retryableStatus(apiErr.StatusCode)
+	}
+
+	var openaiErr *openai.APIError
+	if errors.As(err, &openaiErr) {
+		return 0, retryableStatus(openaiErr.HTTPStatusCode)
+	}
+
+	var requestErr *openai.RequestError
+	if errors.As(err, &requestErr) {
+		return 0, retryableStatus(requestErr.HTTPStatusCode)
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// retryableStatus returns true for rate limits and server errors.
+func retryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package embedding
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyEmbedder fails with the queued errors before succeeding.
+type flakyEmbedder struct {
+	errs  []error
+	calls int
+}
+
+func (f *flakyEmbedder) Embed(ctx context.Context, req *EmbedRequest) (*EmbedResponse, error) {
+	f.calls++
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		return nil, err
+	}
+	return &EmbedResponse{Vectors: []Vector{{Embedding: []float32{1}, Text: req.Texts[0]}}}, nil
+}
+
+func (f *flakyEmbedder) Dimensions() int   { return 1 }
+func (f *flakyEmbedder) ModelName() string { return "flaky" }
+
+func newTestRetryEmbedder(inner Embedder, maxRetries int, waits *[]time.Duration) *RetryEmbedder {
+	e := NewRetryEmbedder(inner, &RetryConfig{
+		MaxRetries:     maxRetries,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+	})
+	e.sleep = func(ctx context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return ctx.Err()
+	}
+	return e
+}
+
+func TestRetryEmbedder(t *testing.T) {
+	req := &EmbedRequest{Texts: []string{"text"}}
+	rateLimited := &APIError{Provider: "Test", StatusCode: http.StatusTooManyRequests, Message: "slow down"}
+
+	t.Run("retries rate limits", func(t *testing.T) {
+		var waits []time.Duration
+		inner := &flakyEmbedder{errs: []error{rateLimited, &APIError{StatusCode: http.StatusBadGateway}}}
+		e := newTestRetryEmbedder(inner, 3, &waits)
+
+		if _, err := e.Embed(context.Background(), req); err != nil {
+			t.Fatalf("Embed() error = %v", err)
+		}
+		if inner.calls != 3 {
+			t.Errorf("calls = %d, want 3", inner.calls)
+		}
+		if len(waits) != 2 || waits[1] < 100*time.Millisecond {
+			t.Errorf("waits = %v, want two with exponential backoff", waits)
+		}
+	})
+
+	t.Run("honors Retry-After", func(t *testing.T) {
+		var waits []time.Duration
+		limited := &APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: 700 * time.Millisecond}
+		e := newTestRetryEmbedder(&flakyEmbedder{errs: []error{limited}}, 3, &waits)
+
+		if _, err := e.Embed(context.Background(), req); err != nil {
+			t.Fatalf("Embed() error = %v", err)
+		}
+		if len(waits) != 1 || waits[0] != 700*time.Millisecond {
+			t.Errorf("waits = %v, want [700ms]", waits)
+		}
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		var waits []time.Duration
+		inner := &flakyEmbedder{errs: []error{rateLimited, rateLimited, rateLimited}}
+		e := newTestRetryEmbedder(inner, 2, &waits)
+
+		_, err := e.Embed(context.Background(), req)
+		if !errors.Is(err, rateLimited) {
+			t.Fatalf("Embed() error = %v, want wrapped rate limit error", err)
+		}
+		if inner.calls != 3 {
+			t.Errorf("calls = %d, want 3", inner.calls)
+		}
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		var waits []time.Duration
+		inner := &flakyEmbedder{errs: []error{&APIError{StatusCode: http.StatusUnauthorized}}}
+		e := newTestRetryEmbedder(inner, 3, &waits)
+
+		if _, err := e.Embed(context.Background(), req); err == nil {
+			t.Fatal("Embed() expected error")
+		}
+		if inner.calls != 1 || len(waits) != 0 {
+			t.Errorf("calls = %d, waits = %v; want a single attempt", inner.calls, waits)
+		}
+	})
+
+	t.Run("stops when cancelled", func(t *testing.T) {
+		var waits []time.Duration
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		inner := &flakyEmbedder{errs: []error{rateLimited}}
+		e := newTestRetryEmbedder(inner, 3, &waits)
+
+		if _, err := e.Embed(ctx, req); err == nil {
+			t.Fatal("Embed() expected error")
+		}
+		if inner.calls != 1 {
+			t.Errorf("calls = %d, want 1", inner.calls)
+		}
+	})
+}
+
+func TestOllamaEmbedder_RateLimit(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error": "too many requests"}`))
+			return
+		}
+		w.Write([]byte(`{"embedding": [0.1, 0.2]}`))
+	}))
+	defer server.Close()
+
+	inner, err := NewOllamaEmbedder("nomic-embed-text", &Config{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var waits []time.Duration
+	e := newTestRetryEmbedder(inner, 3, &waits)
+	resp, err := e.Embed(context.Background(), &EmbedRequest{Texts: []string{"text"}})
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	if len(resp.Vectors) != 1 {
+		t.Errorf("got %d vectors, want 1", len(resp.Vectors))
+	}
+	if len(waits) != 1 || waits[0] != 2*time.Second {
+		t.Errorf("waits = %v, want [2s] from Retry-After", waits)
+	}
+}
@@ -0,0 +1,269 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package anthropic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"deep-thinking-agent/pkg/llm"
+)
+
+const (
+	// DefaultBaseURL is the Anthropic API endpoint
+	DefaultBaseURL = "https://api.anthropic.com"
+
+	// APIVersion is the Messages API version sent with every request
+	APIVersion = "2023-06-01"
+)
+
+// Provider implements the llm.Provider interface for Anthropic's Messages API.
+type Provider struct {
+	client  *http.Client
+	apiKey  string
+	model   string
+	baseURL string
+	config  *llm.Config
+}
+
+// messagesRequest is the request body for POST /v1/messages.
+type messagesRequest struct {
+	Model         string    `json:"model"`
+	System        string    `json:"system,omitempty"`
+	Messages      []message `json:"messages"`
+	MaxTokens     int       `json:"max_tokens"`
+	Temperature   *float32  `json:"temperature,omitempty"`
+	TopP          *float32  `json:"top_p,omitempty"`
+	StopSequences []string  `json:"stop_sequences,omitempty"`
+}
+
+type message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// messagesResponse is the response body for POST /v1/messages.
+type messagesResponse struct {
+	Model      string         `json:"model"`
+	Content    []contentBlock `json:"content"`
+	StopReason string         `json:"stop_reason"`
+	Usage      struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+type contentBlock struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// errorResponse is the body returned with non-2xx status codes.
+type errorResponse struct {
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// NewProvider creates a new Anthropic provider instance.
+// apiKey: Anthropic API key for authentication
+// model: Model to use (e.g., "claude-sonnet-4-5", "claude-haiku-4-5")
+// config: Optional configuration (can be nil for defaults)
+func NewProvider(apiKey, model string, config *llm.Config) (*Provider, error) {
+	if apiKey == "" {
+		return nil, errors.New("Anthropic API key is required")
+	}
+	if model == "" {
+		return nil, errors.New("model name is required")
+	}
+
+	// Apply default config if not provided
+	if config == nil {
+		config = &llm.Config{
+			Provider:           "anthropic",
+			APIKey:             apiKey,
+			Model:              model,
+			DefaultTemperature: 0.7,
+			DefaultMaxTokens:   2048,
+			TimeoutSeconds:     60,
+		}
+	}
+
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &Provider{
+		client:  &http.Client{},
+		apiKey:  apiKey,
+		model:   model,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		config:  config,
+	}, nil
+}
+
+// Complete generates a completion for the given request.
+func (p *Provider) Complete(ctx context.Context, req *llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	if req == nil {
+		return nil, errors.New("completion request cannot be nil")
+	}
+	if len(req.Messages) == 0 {
+		return nil, errors.New("messages cannot be empty")
+	}
+
+	// Apply timeout
+	if p.config.TimeoutSeconds > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(p.config.TimeoutSeconds)*time.Second)
+		defer cancel()
+	}
+
+	body, err := json.Marshal(p.buildRequest(req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-api-key", p.apiKey)
+	httpReq.Header.Set("anthropic-version", APIVersion)
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("Anthropic API error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr errorResponse
+		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Error.Message != "" {
+			return nil, fmt.Errorf("Anthropic API error (status %d, %s): %s", resp.StatusCode, apiErr.Error.Type, apiErr.Error.Message)
+		}
+		return nil, fmt.Errorf("Anthropic API error (status %d): %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var messagesResp messagesResponse
+	if err := json.Unmarshal(respBody, &messagesResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	// Concatenate text blocks; other block types (thinking, tool use) are skipped
+	var content strings.Builder
+	for _, block := range messagesResp.Content {
+		if block.Type == "text" {
+			content.WriteString(block.Text)
+		}
+	}
+
+	// Convert response to our format
+	return &llm.CompletionResponse{
+		Content:      content.String(),
+		FinishReason: mapStopReason(messagesResp.StopReason),
+		Usage: llm.UsageStats{
+			PromptTokens:     messagesResp.Usage.InputTokens,
+			CompletionTokens: messagesResp.Usage.OutputTokens,
+			TotalTokens:      messagesResp.Usage.InputTokens + messagesResp.Usage.OutputTokens,
+		},
+		Model: messagesResp.Model,
+	}, nil
+}
+
+// buildRequest converts a completion request to the Messages API format.
+// System messages are moved to the top-level system field, and consecutive
+// messages with the same role are merged since the API requires turns to
+// alternate between user and assistant.
+func (p *Provider) buildRequest(req *llm.CompletionRequest) *messagesRequest {
+	var systemParts []string
+	messages := make([]message, 0, len(req.Messages))
+
+	for _, msg := range req.Messages {
+		if msg.Role == "system" {
+			systemParts = append(systemParts, msg.Content)
+			continue
+		}
+
+		if n := len(messages); n > 0 && messages[n-1].Role == msg.Role {
+			messages[n-1].Content += "\n\n" + msg.Content
+			continue
+		}
+		messages = append(messages, message{Role: msg.Role, Content: msg.Content})
+	}
+
+	// Apply defaults for unspecified parameters
+	maxTokens := req.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = p.config.DefaultMaxTokens
+	}
+	if maxTokens == 0 {
+		maxTokens = 1024 // max_tokens is required by the Messages API
+	}
+
+	temperature := req.Temperature
+	if temperature == 0 {
+		temperature = p.config.DefaultTemperature
+	}
+
+	messagesReq := &messagesRequest{
+		Model:         p.model,
+		System:        strings.Join(systemParts, "\n\n"),
+		Messages:      messages,
+		MaxTokens:     maxTokens,
+		StopSequences: req.StopSequences,
+	}
+	if temperature > 0 {
+		messagesReq.Temperature = &temperature
+	}
+	if req.TopP > 0 {
+		topP := req.TopP
+		messagesReq.TopP = &topP
+	}
+
+	return messagesReq
+}
+
+// mapStopReason converts Anthropic stop reasons to the common finish reasons.
+func mapStopReason(reason string) string {
+	switch reason {
+	case "end_turn", "stop_sequence":
+		return "stop"
+	case "max_tokens":
+		return "length"
+	default:
+		return reason
+	}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return "anthropic"
+}
+
+// ModelName returns the specific model being used.
+func (p *Provider) ModelName() string {
+	return p.model
+}
+
+// SupportsStreaming indicates if this provider supports streaming responses.
+func (p *Provider) SupportsStreaming() bool {
+	return false
+}
@@ -0,0 +1,214 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"deep-thinking-agent/pkg/llm"
+)
+
+func TestNewProvider(t *testing.T) {
+	tests := []struct {
+		name    string
+		apiKey  string
+		model   string
+		config  *llm.Config
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "valid provider with defaults",
+			apiKey:  "test-api-key",
+			model:   "claude-sonnet-4-5",
+			config:  nil,
+			wantErr: false,
+		},
+		{
+			name:    "valid provider with custom config",
+			apiKey:  "test-api-key",
+			model:   "claude-haiku-4-5",
+			config:  &llm.Config{DefaultTemperature: 0.5, DefaultMaxTokens: 1000, BaseURL: "http://localhost:8080/"},
+			wantErr: false,
+		},
+		{
+			name:    "missing API key",
+			apiKey:  "",
+			model:   "claude-sonnet-4-5",
+			wantErr: true,
+			errMsg:  "Anthropic API key is required",
+		},
+		{
+			name:    "missing model",
+			apiKey:  "test-api-key",
+			model:   "",
+			wantErr: true,
+			errMsg:  "model name is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := NewProvider(tt.apiKey, tt.model, tt.config)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("NewProvider() expected error but got nil")
+				} else if tt.errMsg != "" && err.Error() != tt.errMsg {
+					t.Errorf("NewProvider() error = %v, want %v", err.Error(), tt.errMsg)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("NewProvider() unexpected error: %v", err)
+			}
+			if provider.Name() != "anthropic" {
+				t.Errorf("Name() = %v, want anthropic", provider.Name())
+			}
+			if provider.ModelName() != tt.model {
+				t.Errorf("ModelName() = %v, want %v", provider.ModelName(), tt.model)
+			}
+			if strings.HasSuffix(provider.baseURL, "/") {
+				t.Errorf("baseURL should not end with a slash: %v", provider.baseURL)
+			}
+		})
+	}
+}
+
+func TestProvider_Complete(t *testing.T) {
+	var received messagesRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" {
+			t.Errorf("path = %v, want /v1/messages", r.URL.Path)
+		}
+		if r.Header.Get("x-api-key") != "test-key" {
+			t.Errorf("x-api-key = %v", r.Header.Get("x-api-key"))
+		}
+		if r.Header.Get("anthropic-version") != APIVersion {
+			t.Errorf("anthropic-version = %v", r.Header.Get("anthropic-version"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+
+		w.Write([]byte(`{
+			"id": "msg_1",
+			"type": "message",
+			"role": "assistant",
+			"model": "claude-sonnet-4-5-20250929",
+			"content": [
+				{"type": "thinking", "thinking": "hidden"},
+				{"type": "text", "text": "Hello"},
+				{"type": "text", "text": " world"}
+			],
+			"stop_reason": "end_turn",
+			"usage": {"input_tokens": 12, "output_tokens": 5}
+		}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider("test-key", "claude-sonnet-4-5", &llm.Config{
+		BaseURL:            server.URL,
+		DefaultTemperature: 0.7,
+		DefaultMaxTokens:   500,
+	})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+
+	resp, err := provider.Complete(context.Background(), &llm.CompletionRequest{
+		Messages: []llm.Message{
+			{Role: "system", Content: "You are helpful."},
+			{Role: "user", Content: "Hi"},
+			{Role: "user", Content: "Say hello"},
+		},
+		StopSequences: []string{"END"},
+	})
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+
+	// Request mapping
+	if received.System != "You are helpful." {
+		t.Errorf("system = %q", received.System)
+	}
+	if len(received.Messages) != 1 || received.Messages[0].Role != "user" || received.Messages[0].Content != "Hi\n\nSay hello" {
+		t.Errorf("messages = %+v", received.Messages)
+	}
+	if received.MaxTokens != 500 {
+		t.Errorf("max_tokens = %v, want 500", received.MaxTokens)
+	}
+	if received.Temperature == nil || *received.Temperature != 0.7 {
+		t.Errorf("temperature = %v, want 0.7", received.Temperature)
+	}
+	if len(received.StopSequences) != 1 || received.StopSequences[0] != "END" {
+		t.Errorf("stop_sequences = %v", received.StopSequences)
+	}
+
+	// Response mapping
+	if resp.Content != "Hello world" {
+		t.Errorf("Content = %q, want %q", resp.Content, "Hello world")
+	}
+	if resp.FinishReason != "stop" {
+		t.Errorf("FinishReason = %v, want stop", resp.FinishReason)
+	}
+	if resp.Usage.PromptTokens != 12 || resp.Usage.CompletionTokens != 5 || resp.Usage.TotalTokens != 17 {
+		t.Errorf("Usage = %+v", resp.Usage)
+	}
+	if resp.Model != "claude-sonnet-4-5-20250929" {
+		t.Errorf("Model = %v", resp.Model)
+	}
+}
+
+func TestProvider_Complete_Errors(t *testing.T) {
+	t.Run("API error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"type": "error", "error": {"type": "invalid_request_error", "message": "max_tokens: field required"}}`))
+		}))
+		defer server.Close()
+
+		provider, _ := NewProvider("test-key", "claude-sonnet-4-5", &llm.Config{BaseURL: server.URL})
+		_, err := provider.Complete(context.Background(), &llm.CompletionRequest{
+			Messages: []llm.Message{{Role: "user", Content: "Hi"}},
+		})
+		if err == nil || !strings.Contains(err.Error(), "max_tokens: field required") {
+			t.Errorf("expected API error message, got %v", err)
+		}
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		provider, _ := NewProvider("test-key", "claude-sonnet-4-5", nil)
+		if _, err := provider.Complete(context.Background(), nil); err == nil {
+			t.Error("expected error for nil request")
+		}
+		if _, err := provider.Complete(context.Background(), &llm.CompletionRequest{}); err == nil {
+			t.Error("expected error for empty messages")
+		}
+	})
+}
+
+func TestMapStopReason(t *testing.T) {
+	tests := map[string]string{
+		"end_turn":      "stop",
+		"stop_sequence": "stop",
+		"max_tokens":    "length",
+		"tool_use":      "tool_use",
+	}
+
+	for reason, want := range tests {
+		if got := mapStopReason(reason); got != want {
+			t.Errorf("mapStopReason(%q) = %v, want %v", reason, got, want)
+		}
+	}
+}
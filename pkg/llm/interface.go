@@ -31,7 +31,8 @@ type CompletionRequest struct {
 	// Stop sequences that will halt generation
 	StopSequences []string
 
-	// Stream enables streaming responses (not implemented in Phase 1)
+	// Stream enables streaming responses; set by CompleteStream implementations
+	// and ignored by Complete
 	Stream bool
 }
 
@@ -74,6 +75,34 @@ type Provider interface {
 	SupportsStreaming() bool
 }
 
+// StreamChunk is one incremental piece of a streamed completion.
+// The final chunk carries the FinishReason and, when the provider reports it,
+// token usage. A chunk with a non-nil Err ends the stream.
+type StreamChunk struct {
+	// Delta is the newly generated text since the previous chunk
+	Delta string
+
+	// FinishReason is set on the last chunk ("stop", "length", ...)
+	FinishReason string
+
+	// Usage contains token usage statistics, if reported (final chunk only)
+	Usage *UsageStats
+
+	// Err is set if the stream failed
+	Err error
+}
+
+// StreamingProvider is implemented by providers that can stream completions.
+// Callers should check SupportsStreaming as well, or use CompleteWithStream,
+// which falls back to Complete.
+type StreamingProvider interface {
+	Provider
+
+	// CompleteStream starts a completion and returns a channel of deltas.
+	// The channel is closed when generation ends, fails, or ctx is cancelled.
+	CompleteStream(ctx context.Context, req *CompletionRequest) (<-chan StreamChunk, error)
+}
+
 // Config contains common configuration options for LLM providers.
 type Config struct {
 	// Provider specifies which LLM provider to use
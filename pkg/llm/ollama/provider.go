@@ -0,0 +1,217 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package ollama
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"deep-thinking-agent/pkg/llm"
+)
+
+// DefaultBaseURL is the default address of a local Ollama server.
+const DefaultBaseURL = "http://localhost:11434"
+
+// Provider implements the llm.Provider interface for Ollama's chat API.
+// It talks to a local or self-hosted server and needs no API key.
+type Provider struct {
+	client  *http.Client
+	model   string
+	baseURL string
+	config  *llm.Config
+}
+
+// chatRequest is the request body for POST /api/chat.
+type chatRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+	Stream   bool          `json:"stream"`
+	Options  chatOptions   `json:"options"`
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatOptions struct {
+	Temperature float32  `json:"temperature,omitempty"`
+	NumPredict  int      `json:"num_predict,omitempty"`
+	TopP        float32  `json:"top_p,omitempty"`
+	Stop        []string `json:"stop,omitempty"`
+}
+
+// chatResponse is the non-streaming response body for POST /api/chat.
+type chatResponse struct {
+	Model           string      `json:"model"`
+	Message         chatMessage `json:"message"`
+	Done            bool        `json:"done"`
+	DoneReason      string      `json:"done_reason"`
+	PromptEvalCount int         `json:"prompt_eval_count"`
+	EvalCount       int         `json:"eval_count"`
+}
+
+// NewProvider creates a new Ollama provider instance.
+// model: Model to use (e.g., "llama3.1", "qwen2.5:14b")
+// config: Optional configuration (can be nil for defaults); BaseURL selects the server
+func NewProvider(model string, config *llm.Config) (*Provider, error) {
+	if model == "" {
+		return nil, errors.New("model name is required")
+	}
+
+	// Apply default config if not provided
+	if config == nil {
+		config = &llm.Config{
+			Provider:           "ollama",
+			Model:              model,
+			DefaultTemperature: 0.7,
+			DefaultMaxTokens:   2048,
+			TimeoutSeconds:     300, // Local models can be slow to load
+		}
+	}
+
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &Provider{
+		client:  &http.Client{},
+		model:   model,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		config:  config,
+	}, nil
+}
+
+// Complete generates a completion for the given request.
+func (p *Provider) Complete(ctx context.Context, req *llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	if req == nil {
+		return nil, errors.New("completion request cannot be nil")
+	}
+	if len(req.Messages) == 0 {
+		return nil, errors.New("messages cannot be empty")
+	}
+
+	// Apply timeout
+	if p.config.TimeoutSeconds > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(p.config.TimeoutSeconds)*time.Second)
+		defer cancel()
+	}
+
+	// Convert our messages to Ollama format
+	messages := make([]chatMessage, len(req.Messages))
+	for i, msg := range req.Messages {
+		messages[i] = chatMessage{Role: msg.Role, Content: msg.Content}
+	}
+
+	// Apply defaults for unspecified parameters
+	temperature := req.Temperature
+	if temperature == 0 {
+		temperature = p.config.DefaultTemperature
+	}
+
+	maxTokens := req.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = p.config.DefaultMaxTokens
+	}
+
+	body, err := json.Marshal(chatRequest{
+		Model:    p.model,
+		Messages: messages,
+		Stream:   false,
+		Options: chatOptions{
+			Temperature: temperature,
+			NumPredict:  maxTokens,
+			TopP:        req.TopP,
+			Stop:        req.StopSequences,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	var chatResp chatResponse
+	if err := postJSON(ctx, p.client, p.baseURL+"/api/chat", body, &chatResp); err != nil {
+		return nil, fmt.Errorf("Ollama API error: %w", err)
+	}
+
+	finishReason := chatResp.DoneReason
+	if finishReason == "" && chatResp.Done {
+		finishReason = "stop"
+	}
+
+	// Convert response to our format
+	return &llm.CompletionResponse{
+		Content:      chatResp.Message.Content,
+		FinishReason: finishReason,
+		Usage: llm.UsageStats{
+			PromptTokens:     chatResp.PromptEvalCount,
+			CompletionTokens: chatResp.EvalCount,
+			TotalTokens:      chatResp.PromptEvalCount + chatResp.EvalCount,
+		},
+		Model: chatResp.Model,
+	}, nil
+}
+
+// postJSON sends a JSON request and decodes the JSON response into out.
+// Ollama reports failures as {"error": "..."} with a non-2xx status.
+func postJSON(ctx context.Context, client *http.Client, url string, body []byte, out interface{}) error {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Error != "" {
+			return fmt.Errorf("status %d: %s", resp.StatusCode, apiErr.Error)
+		}
+		return fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return nil
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return "ollama"
+}
+
+// ModelName returns the specific model being used.
+func (p *Provider) ModelName() string {
+	return p.model
+}
+
+// SupportsStreaming indicates if this provider supports streaming responses.
+func (p *Provider) SupportsStreaming() bool {
+	return false
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"deep-thinking-agent/pkg/llm"
+)
+
+func TestNewProvider(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		provider, err := NewProvider("llama3.1", nil)
+		if err != nil {
+			t.Fatalf("NewProvider() error = %v", err)
+		}
+		if provider.Name() != "ollama" {
+			t.Errorf("Name() = %v, want ollama", provider.Name())
+		}
+		if provider.ModelName() != "llama3.1" {
+			t.Errorf("ModelName() = %v, want llama3.1", provider.ModelName())
+		}
+		if provider.baseURL != DefaultBaseURL {
+			t.Errorf("baseURL = %v, want %v", provider.baseURL, DefaultBaseURL)
+		}
+	})
+
+	t.Run("custom base URL", func(t *testing.T) {
+		provider, err := NewProvider("llama3.1", &llm.Config{BaseURL: "http://gpu-box:11434/"})
+		if err != nil {
+			t.Fatalf("NewProvider() error = %v", err)
+		}
+		if provider.baseURL != "http://gpu-box:11434" {
+			t.Errorf("baseURL = %v", provider.baseURL)
+		}
+	})
+
+	t.Run("missing model", func(t *testing.T) {
+		if _, err := NewProvider("", nil); err == nil || err.Error() != "model name is required" {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestProvider_Complete(t *testing.T) {
+	var received chatRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("path = %v, want /api/chat", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		w.Write([]byte(`{
+			"model": "llama3.1",
+			"message": {"role": "assistant", "content": "Paris"},
+			"done": true,
+			"done_reason": "stop",
+			"prompt_eval_count": 20,
+			"eval_count": 3
+		}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider("llama3.1", &llm.Config{
+		BaseURL:            server.URL,
+		DefaultTemperature: 0.4,
+		DefaultMaxTokens:   256,
+	})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+
+	resp, err := provider.Complete(context.Background(), &llm.CompletionRequest{
+		Messages: []llm.Message{
+			{Role: "system", Content: "Be brief."},
+			{Role: "user", Content: "Capital of France?"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+
+	if received.Model != "llama3.1" || received.Stream {
+		t.Errorf("unexpected request: %+v", received)
+	}
+	if len(received.Messages) != 2 || received.Messages[0].Role != "system" {
+		t.Errorf("messages = %+v", received.Messages)
+	}
+	if received.Options.Temperature != 0.4 || received.Options.NumPredict != 256 {
+		t.Errorf("options = %+v", received.Options)
+	}
+
+	if resp.Content != "Paris" || resp.FinishReason != "stop" || resp.Model != "llama3.1" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if resp.Usage.PromptTokens != 20 || resp.Usage.CompletionTokens != 3 || resp.Usage.TotalTokens != 23 {
+		t.Errorf("Usage = %+v", resp.Usage)
+	}
+}
+
+func TestProvider_Complete_Errors(t *testing.T) {
+	t.Run("model not found", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "model \"missing\" not found, try pulling it first"}`))
+		}))
+		defer server.Close()
+
+		provider, _ := NewProvider("missing", &llm.Config{BaseURL: server.URL})
+		_, err := provider.Complete(context.Background(), &llm.CompletionRequest{
+			Messages: []llm.Message{{Role: "user", Content: "Hi"}},
+		})
+		if err == nil || !strings.Contains(err.Error(), "try pulling it first") {
+			t.Errorf("expected server error message, got %v", err)
+		}
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		provider, _ := NewProvider("llama3.1", nil)
+		if _, err := provider.Complete(context.Background(), nil); err == nil {
+			t.Error("expected error for nil request")
+		}
+		if _, err := provider.Complete(context.Background(), &llm.CompletionRequest{}); err == nil {
+			t.Error("expected error for empty messages")
+		}
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
@@ -79,6 +80,108 @@ func (p *Provider) Complete(ctx context.Context, req *llm.CompletionRequest) (*l
 		defer cancel()
 	}
 
+	// Execute request
+	resp, err := p.client.CreateChatCompletion(ctx, p.buildRequest(req))
+	if err != nil {
+		return nil, fmt.Errorf("OpenAI API error: %w", err)
+	}
+
+	// Validate response
+	if len(resp.Choices) == 0 {
+		return nil, errors.New("OpenAI returned no choices")
+	}
+
+	// Convert response to our format
+	return &llm.CompletionResponse{
+		Content:      resp.Choices[0].Message.Content,
+		FinishReason: string(resp.Choices[0].FinishReason),
+		Usage: llm.UsageStats{
+			PromptTokens:     resp.Usage.PromptTokens,
+			CompletionTokens: resp.Usage.CompletionTokens,
+			TotalTokens:      resp.Usage.TotalTokens,
+		},
+		Model: resp.Model,
+	}, nil
+}
+
+// CompleteStream generates a completion, delivering content deltas over
+// server-sent events as they are produced.
+func (p *Provider) CompleteStream(ctx context.Context, req *llm.CompletionRequest) (<-chan llm.StreamChunk, error) {
+	if req == nil {
+		return nil, errors.New("completion request cannot be nil")
+	}
+	if len(req.Messages) == 0 {
+		return nil, errors.New("messages cannot be empty")
+	}
+
+	// Apply timeout to the whole stream; released when the stream ends
+	cancel := context.CancelFunc(func() {})
+	if p.config.TimeoutSeconds > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(p.config.TimeoutSeconds)*time.Second)
+	}
+
+	openaiReq := p.buildRequest(req)
+	openaiReq.Stream = true
+	openaiReq.StreamOptions = &openai.StreamOptions{IncludeUsage: true}
+
+	stream, err := p.client.CreateChatCompletionStream(ctx, openaiReq)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("OpenAI API error: %w", err)
+	}
+
+	chunks := make(chan llm.StreamChunk)
+	go func() {
+		defer close(chunks)
+		defer cancel()
+		defer stream.Close()
+
+		send := func(chunk llm.StreamChunk) bool {
+			select {
+			case chunks <- chunk:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		for {
+			resp, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				send(llm.StreamChunk{Err: fmt.Errorf("OpenAI stream error: %w", err)})
+				return
+			}
+
+			var chunk llm.StreamChunk
+			if len(resp.Choices) > 0 {
+				chunk.Delta = resp.Choices[0].Delta.Content
+				chunk.FinishReason = string(resp.Choices[0].FinishReason)
+			}
+			if resp.Usage != nil {
+				chunk.Usage = &llm.UsageStats{
+					PromptTokens:     resp.Usage.PromptTokens,
+					CompletionTokens: resp.Usage.CompletionTokens,
+					TotalTokens:      resp.Usage.TotalTokens,
+				}
+			}
+
+			if chunk.Delta == "" && chunk.FinishReason == "" && chunk.Usage == nil {
+				continue
+			}
+			if !send(chunk) {
+				return
+			}
+		}
+	}()
+
+	return chunks, nil
+}
+
+// buildRequest converts our request to OpenAI format, applying config defaults.
+func (p *Provider) buildRequest(req *llm.CompletionRequest) openai.ChatCompletionRequest {
 	// Convert our messages to OpenAI format
 	openaiMessages := make([]openai.ChatCompletionMessage, len(req.Messages))
 	for i, msg := range req.Messages {
@@ -116,8 +219,7 @@ func (p *Provider) Complete(ctx context.Context, req *llm.CompletionRequest) (*l
 	}
 	// else: leave at 0 for reasoning models (omitempty will exclude from JSON)
 
-	// Create OpenAI request
-	openaiReq := openai.ChatCompletionRequest{
+	return openai.ChatCompletionRequest{
 		Model:               p.model,
 		Messages:            openaiMessages,
 		Temperature:         finalTemp,
@@ -125,29 +227,6 @@ func (p *Provider) Complete(ctx context.Context, req *llm.CompletionRequest) (*l
 		TopP:                finalTopP,
 		Stop:                req.StopSequences,
 	}
-
-	// Execute request
-	resp, err := p.client.CreateChatCompletion(ctx, openaiReq)
-	if err != nil {
-		return nil, fmt.Errorf("OpenAI API error: %w", err)
-	}
-
-	// Validate response
-	if len(resp.Choices) == 0 {
-		return nil, errors.New("OpenAI returned no choices")
-	}
-
-	// Convert response to our format
-	return &llm.CompletionResponse{
-		Content:      resp.Choices[0].Message.Content,
-		FinishReason: string(resp.Choices[0].FinishReason),
-		Usage: llm.UsageStats{
-			PromptTokens:     resp.Usage.PromptTokens,
-			CompletionTokens: resp.Usage.CompletionTokens,
-			TotalTokens:      resp.Usage.TotalTokens,
-		},
-		Model: resp.Model,
-	}, nil
 }
 
 // Name returns the provider name.
@@ -162,5 +241,5 @@ func (p *Provider) ModelName() string {
 
 // SupportsStreaming indicates if this provider supports streaming responses.
 func (p *Provider) SupportsStreaming() bool {
-	return true // OpenAI supports streaming, but not implemented in Phase 1
+	return true
 }
@@ -7,6 +7,10 @@ package openai
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"deep-thinking-agent/pkg/llm"
@@ -453,3 +457,76 @@ func TestProvider_ConfigPreservation(t *testing.T) {
 // test file (e.g., provider_integration_test.go) and run with a build tag:
 // //go:build integration
 // This allows unit tests to run quickly without API dependencies.
+
+func TestProvider_CompleteStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if body["stream"] != true {
+			t.Errorf("request stream = %v, want true", body["stream"])
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		events := []string{
+			`{"id":"1","model":"gpt-4","choices":[{"index":0,"delta":{"role":"assistant","content":"Hello"}}]}`,
+			`{"id":"1","model":"gpt-4","choices":[{"index":0,"delta":{"content":", world"}}]}`,
+			`{"id":"1","model":"gpt-4","choices":[{"index":0,"delta":{},"finish_reason":"stop"}]}`,
+			`{"id":"1","model":"gpt-4","choices":[],"usage":{"prompt_tokens":5,"completion_tokens":3,"total_tokens":8}}`,
+		}
+		for _, event := range events {
+			fmt.Fprintf(w, "data: %s\n\n", event)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key", "gpt-4", &llm.Config{BaseURL: server.URL + "/v1", DefaultMaxTokens: 100})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+
+	var deltas []string
+	resp, err := llm.CompleteWithStream(context.Background(), provider, &llm.CompletionRequest{
+		Messages: []llm.Message{{Role: "user", Content: "Hi"}},
+	}, func(delta string) {
+		deltas = append(deltas, delta)
+	})
+	if err != nil {
+		t.Fatalf("CompleteWithStream() error = %v", err)
+	}
+
+	if len(deltas) != 2 || deltas[0] != "Hello" || deltas[1] != ", world" {
+		t.Errorf("deltas = %q, want [Hello , world]", deltas)
+	}
+	if resp.Content != "Hello, world" {
+		t.Errorf("Content = %q, want %q", resp.Content, "Hello, world")
+	}
+	if resp.FinishReason != "stop" {
+		t.Errorf("FinishReason = %q, want stop", resp.FinishReason)
+	}
+	if resp.Usage.TotalTokens != 8 {
+		t.Errorf("Usage.TotalTokens = %d, want 8", resp.Usage.TotalTokens)
+	}
+}
+
+func TestProvider_CompleteStream_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":{"message":"invalid key","type":"invalid_request_error"}}`)
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider("bad-key", "gpt-4", &llm.Config{BaseURL: server.URL + "/v1"})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+
+	if _, err := provider.CompleteStream(context.Background(), &llm.CompletionRequest{
+		Messages: []llm.Message{{Role: "user", Content: "Hi"}},
+	}); err == nil {
+		t.Error("CompleteStream() expected error for unauthorized response")
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package llm
+
+import (
+	"context"
+	"strings"
+)
+
+// CompleteWithStream runs a completion, calling onDelta with each piece of
+// generated text as it arrives, and returns the assembled response.
+// Providers that do not stream fall back to Complete, and onDelta receives
+// the whole content at once. A nil onDelta behaves like Complete.
+func CompleteWithStream(ctx context.Context, provider Provider, req *CompletionRequest, onDelta func(delta string)) (*CompletionResponse, error) {
+	streamer, ok := provider.(StreamingProvider)
+	if onDelta == nil || !ok || !provider.SupportsStreaming() {
+		resp, err := provider.Complete(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if onDelta != nil && resp.Content != "" {
+			onDelta(resp.Content)
+		}
+		return resp, nil
+	}
+
+	chunks, err := streamer.CompleteStream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &CompletionResponse{Model: provider.ModelName()}
+	var content strings.Builder
+	for chunk := range chunks {
+		if chunk.Err != nil {
+			return nil, chunk.Err
+		}
+		if chunk.Delta != "" {
+			content.WriteString(chunk.Delta)
+			onDelta(chunk.Delta)
+		}
+		if chunk.FinishReason != "" {
+			resp.FinishReason = chunk.FinishReason
+		}
+		if chunk.Usage != nil {
+			resp.Usage = *chunk.Usage
+		}
+	}
+
+	resp.Content = content.String()
+	return resp, nil
+}
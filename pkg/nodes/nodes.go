@@ -7,30 +7,80 @@ package nodes
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"strings"
 
 	"deep-thinking-agent/pkg/agent"
+	"deep-thinking-agent/pkg/schema"
 	"deep-thinking-agent/pkg/vectorstore"
 	"deep-thinking-agent/pkg/workflow"
 )
 
+// maxPlanningSchemas bounds how many stored schemas the planner node loads
+// when no documents are explicitly in scope.
+const maxPlanningSchemas = 20
+
+// candidateSearchTopK is how many chunks the planner node retrieves to find
+// the documents most relevant to the question.
+const candidateSearchTopK = 50
+
 // PlannerNode wraps the planner agent as a workflow node.
 type PlannerNode struct {
-	planner *agent.Planner
-	ctx     context.Context
+	planner   *agent.Planner
+	schemas   schema.SchemaStore
+	retriever *agent.Retriever
 }
 
 // NewPlannerNode creates a new planner node.
-func NewPlannerNode(ctx context.Context, planner *agent.Planner) *PlannerNode {
+func NewPlannerNode(planner *agent.Planner) *PlannerNode {
 	return &PlannerNode{
 		planner: planner,
-		ctx:     ctx,
 	}
 }
 
+// SetSchemaStore sets the store document schemas are loaded from before
+// planning. Loaded schemas are kept in State.RelevantSchemas.
+func (n *PlannerNode) SetSchemaStore(store schema.SchemaStore) {
+	n.schemas = store
+}
+
+// SetRetriever sets the retriever used to find the documents most relevant
+// to the question when none are explicitly in scope. Without one, the first
+// stored schemas by doc ID are loaded.
+func (n *PlannerNode) SetRetriever(retriever *agent.Retriever) {
+	n.retriever = retriever
+}
+
 // Execute runs the planner to create a query execution plan.
-func (n *PlannerNode) Execute(state *workflow.State) (*workflow.NodeResult, error) {
-	plan, err := n.planner.Plan(n.ctx, state.OriginalQuestion)
+// Documents are in scope when named by the active filters; otherwise the
+// schemas of the documents a vector search for the question finds are loaded
+// so the planner knows what is available. When the policy asks for a replan,
+// the remaining steps of the existing plan are revised instead.
+func (n *PlannerNode) Execute(ctx context.Context, state *workflow.State) (*workflow.NodeResult, error) {
+	if state.Plan != nil && state.Decision != nil && state.Decision.Replan {
+		return n.revise(ctx, state)
+	}
+
+	if n.schemas != nil {
+		var docIDs []string
+		if state.ActiveFilters != nil && len(state.ActiveFilters.DocumentIDs) > 0 {
+			docIDs = state.ActiveFilters.DocumentIDs
+		} else {
+			ids, err := n.candidateDocuments(ctx, state.OriginalQuestion)
+			if err != nil {
+				return nil, err
+			}
+			docIDs = ids
+		}
+
+		if err := loadSchemas(ctx, n.schemas, state, docIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	plan, err := n.planner.PlanWithSchemas(ctx, state.OriginalQuestion, state.RelevantSchemas)
 	if err != nil {
 		return nil, fmt.Errorf("planning failed: %w", err)
 	}
@@ -39,40 +89,139 @@ func (n *PlannerNode) Execute(state *workflow.State) (*workflow.NodeResult, erro
 	return &workflow.NodeResult{UpdatedState: state}, nil
 }
 
+// candidateDocuments returns up to maxPlanningSchemas doc IDs, most relevant
+// to the question first.
+func (n *PlannerNode) candidateDocuments(ctx context.Context, question string) ([]string, error) {
+	if n.retriever == nil {
+		ids, err := n.schemas.List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list schemas: %w", err)
+		}
+		if len(ids) > maxPlanningSchemas {
+			ids = ids[:maxPlanningSchemas]
+		}
+		return ids, nil
+	}
+
+	docs, err := n.retriever.Retrieve(ctx, &workflow.RetrievalContext{
+		Query:    question,
+		Strategy: workflow.StrategyVector,
+		TopK:     candidateSearchTopK,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find candidate documents: %w", err)
+	}
+
+	var ids []string
+	seen := make(map[string]bool)
+	for _, doc := range docs {
+		docID, _ := doc.Metadata["doc_id"].(string)
+		if docID == "" || seen[docID] {
+			continue
+		}
+		seen[docID] = true
+		ids = append(ids, docID)
+		if len(ids) == maxPlanningSchemas {
+			break
+		}
+	}
+	return ids, nil
+}
+
+// revise replaces the plan with the planner's revision. A failed revision
+// is not fatal: the workflow continues with the existing plan, and the
+// failure is noted in a new policy decision that no longer asks to replan.
+func (n *PlannerNode) revise(ctx context.Context, state *workflow.State) (*workflow.NodeResult, error) {
+	plan, err := n.planner.Revise(ctx, state)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("plan revision failed: %w", err)
+		}
+
+		// Replace rather than modify the decision, which the states of plan
+		// steps running in parallel may share
+		decision := *state.Decision
+		decision.Replan = false
+		decision.Reasoning = strings.TrimSpace(fmt.Sprintf("%s (plan revision failed, keeping the current plan: %v)", decision.Reasoning, err))
+		state.Decision = &decision
+		return &workflow.NodeResult{UpdatedState: state}, nil
+	}
+
+	state.SetPlan(plan)
+	return &workflow.NodeResult{UpdatedState: state}, nil
+}
+
 // Name returns the node name.
 func (n *PlannerNode) Name() string {
 	return "planner"
 }
 
+// loadSchemas adds the stored schemas of docIDs that are missing from
+// State.RelevantSchemas. Documents without a stored schema are skipped.
+// The map is replaced rather than modified, since the states of plan steps
+// running in parallel share it.
+func loadSchemas(ctx context.Context, store schema.SchemaStore, state *workflow.State, docIDs []string) error {
+	var loaded map[string]*schema.DocumentSchema
+	for _, docID := range docIDs {
+		if _, ok := state.RelevantSchemas[docID]; ok {
+			continue
+		}
+		if _, ok := loaded[docID]; ok {
+			continue
+		}
+
+		entry, err := store.Load(ctx, docID)
+		if errors.Is(err, schema.ErrSchemaNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load schema: %w", err)
+		}
+
+		if loaded == nil {
+			loaded = maps.Clone(state.RelevantSchemas)
+			if loaded == nil {
+				loaded = make(map[string]*schema.DocumentSchema)
+			}
+		}
+		loaded[docID] = entry.Schema
+	}
+
+	if loaded != nil {
+		state.RelevantSchemas = loaded
+	}
+	return nil
+}
+
 // RewriterNode wraps the rewriter agent as a workflow node.
 type RewriterNode struct {
 	rewriter *agent.Rewriter
-	ctx      context.Context
 }
 
 // NewRewriterNode creates a new rewriter node.
-func NewRewriterNode(ctx context.Context, rewriter *agent.Rewriter) *RewriterNode {
+func NewRewriterNode(rewriter *agent.Rewriter) *RewriterNode {
 	return &RewriterNode{
 		rewriter: rewriter,
-		ctx:      ctx,
 	}
 }
 
-// Execute enhances the current query for better retrieval.
-func (n *RewriterNode) Execute(state *workflow.State) (*workflow.NodeResult, error) {
+// Execute enhances the current query for better retrieval, along with any
+// query variants the rewriter is configured to generate.
+func (n *RewriterNode) Execute(ctx context.Context, state *workflow.State) (*workflow.NodeResult, error) {
 	currentStep := state.CurrentStep()
 	if currentStep == nil {
 		return nil, fmt.Errorf("no current step available")
 	}
 
-	rewritten, err := n.rewriter.Rewrite(n.ctx, currentStep.SubQuestion, state)
+	rewritten, variants, err := n.rewriter.Expand(ctx, currentStep.SubQuestion, state)
 	if err != nil {
 		return nil, fmt.Errorf("rewriting failed: %w", err)
 	}
 
 	// Update the query in retrieval context
-	if state.GetRetrievalContext() != nil {
-		state.GetRetrievalContext().Query = rewritten
+	if retrievalCtx := state.GetRetrievalContext(); retrievalCtx != nil {
+		retrievalCtx.Query = rewritten
+		retrievalCtx.Variants = variants
 	}
 
 	return &workflow.NodeResult{UpdatedState: state}, nil
@@ -86,34 +235,55 @@ func (n *RewriterNode) Name() string {
 // SupervisorNode wraps the supervisor agent as a workflow node.
 type SupervisorNode struct {
 	supervisor *agent.Supervisor
-	ctx        context.Context
+	schemas    schema.SchemaStore
+	hints      *agent.SchemaHintMapper
 }
 
 // NewSupervisorNode creates a new supervisor node.
-func NewSupervisorNode(ctx context.Context, supervisor *agent.Supervisor) *SupervisorNode {
+func NewSupervisorNode(supervisor *agent.Supervisor) *SupervisorNode {
 	return &SupervisorNode{
 		supervisor: supervisor,
-		ctx:        ctx,
+		hints:      agent.NewSchemaHintMapper(),
 	}
 }
 
-// Execute selects the optimal retrieval strategy.
-func (n *SupervisorNode) Execute(state *workflow.State) (*workflow.NodeResult, error) {
+// SetSchemaStore sets the store document schemas are loaded from before
+// selecting a strategy. Loaded schemas are kept in State.RelevantSchemas.
+func (n *SupervisorNode) SetSchemaStore(store schema.SchemaStore) {
+	n.schemas = store
+}
+
+// Execute selects the optimal retrieval strategy and turns the step's
+// schema hint into schema filters for retrieval.
+// Documents are in scope when named by the active filters or retrieved by
+// an earlier step.
+func (n *SupervisorNode) Execute(ctx context.Context, state *workflow.State) (*workflow.NodeResult, error) {
 	currentStep := state.CurrentStep()
 	if currentStep == nil {
 		return nil, fmt.Errorf("no current step available")
 	}
 
-	strategy, err := n.supervisor.SelectStrategy(n.ctx, currentStep.SubQuestion, state)
+	if n.schemas != nil {
+		if err := loadSchemas(ctx, n.schemas, state, documentsInScope(state)); err != nil {
+			return nil, err
+		}
+	}
+
+	// Narrow this step's filters by its hint; a hint that matches no known
+	// section leaves the active filters in place
+	retrievalCtx := state.GetRetrievalContext()
+	if filters := n.hints.Map(currentStep.SchemaHint, state.RelevantSchemas, state.ActiveFilters); filters != nil {
+		retrievalCtx.SchemaFilters = filters
+		retrievalCtx.FiltersFromHint = true
+	}
+
+	strategy, err := n.supervisor.SelectStrategy(ctx, currentStep.SubQuestion, state)
 	if err != nil {
 		return nil, fmt.Errorf("strategy selection failed: %w", err)
 	}
 
 	// Update retrieval context with selected strategy
-	retrievalCtx := state.GetRetrievalContext()
-	if retrievalCtx != nil {
-		retrievalCtx.Strategy = strategy
-	}
+	retrievalCtx.Strategy = strategy
 
 	return &workflow.NodeResult{UpdatedState: state}, nil
 }
@@ -123,32 +293,74 @@ func (n *SupervisorNode) Name() string {
 	return "supervisor"
 }
 
+// documentsInScope returns the IDs of documents named by the active filters
+// or retrieved by past steps, in first-seen order.
+func documentsInScope(state *workflow.State) []string {
+	var docIDs []string
+	seen := make(map[string]bool)
+	add := func(docID string) {
+		if docID != "" && !seen[docID] {
+			seen[docID] = true
+			docIDs = append(docIDs, docID)
+		}
+	}
+
+	if state.ActiveFilters != nil {
+		for _, docID := range state.ActiveFilters.DocumentIDs {
+			add(docID)
+		}
+	}
+	for _, past := range state.PastSteps {
+		for _, doc := range past.RetrievedDocs {
+			docID, _ := doc.Metadata["doc_id"].(string)
+			add(docID)
+		}
+	}
+	return docIDs
+}
+
 // RetrieverNode wraps the retriever agent as a workflow node.
 type RetrieverNode struct {
 	retriever *agent.Retriever
-	ctx       context.Context
 }
 
 // NewRetrieverNode creates a new retriever node.
-func NewRetrieverNode(ctx context.Context, retriever *agent.Retriever) *RetrieverNode {
+func NewRetrieverNode(retriever *agent.Retriever) *RetrieverNode {
 	return &RetrieverNode{
 		retriever: retriever,
-		ctx:       ctx,
 	}
 }
 
 // Execute retrieves relevant documents.
-func (n *RetrieverNode) Execute(state *workflow.State) (*workflow.NodeResult, error) {
+// Steps planned as web_search are routed to web search when it is configured;
+// otherwise they fall back to the supervisor's document retrieval strategy.
+func (n *RetrieverNode) Execute(ctx context.Context, state *workflow.State) (*workflow.NodeResult, error) {
 	retrievalCtx := state.GetRetrievalContext()
 	if retrievalCtx == nil {
 		return nil, fmt.Errorf("no retrieval context available")
 	}
 
-	docs, err := n.retriever.Retrieve(n.ctx, retrievalCtx)
+	if step := state.CurrentStep(); step != nil && step.ToolType == "web_search" && n.retriever.SupportsWebSearch() {
+		retrievalCtx.Strategy = workflow.StrategyWebSearch
+	}
+
+	docs, err := n.retriever.Retrieve(ctx, retrievalCtx)
 	if err != nil {
 		return nil, fmt.Errorf("retrieval failed: %w", err)
 	}
 
+	// Filters derived from a schema hint can be too narrow; widen to the
+	// run's active filters rather than return nothing
+	if len(docs) == 0 && retrievalCtx.FiltersFromHint {
+		retrievalCtx.SchemaFilters = state.ActiveFilters
+		retrievalCtx.FiltersFromHint = false
+
+		docs, err = n.retriever.Retrieve(ctx, retrievalCtx)
+		if err != nil {
+			return nil, fmt.Errorf("retrieval failed: %w", err)
+		}
+	}
+
 	state.RetrievedDocs = docs
 	return &workflow.NodeResult{UpdatedState: state}, nil
 }
@@ -161,19 +373,17 @@ func (n *RetrieverNode) Name() string {
 // RerankerNode wraps the reranker agent as a workflow node.
 type RerankerNode struct {
 	reranker *agent.Reranker
-	ctx      context.Context
 }
 
 // NewRerankerNode creates a new reranker node.
-func NewRerankerNode(ctx context.Context, reranker *agent.Reranker) *RerankerNode {
+func NewRerankerNode(reranker *agent.Reranker) *RerankerNode {
 	return &RerankerNode{
 		reranker: reranker,
-		ctx:      ctx,
 	}
 }
 
 // Execute reranks retrieved documents for precision.
-func (n *RerankerNode) Execute(state *workflow.State) (*workflow.NodeResult, error) {
+func (n *RerankerNode) Execute(ctx context.Context, state *workflow.State) (*workflow.NodeResult, error) {
 	if len(state.RetrievedDocs) == 0 {
 		// No documents to rerank, continue
 		state.RerankedDocs = []vectorstore.Document{}
@@ -185,8 +395,16 @@ func (n *RerankerNode) Execute(state *workflow.State) (*workflow.NodeResult, err
 		return nil, fmt.Errorf("no current step available")
 	}
 
-	reranked := n.reranker.Rerank(n.ctx, currentStep.SubQuestion, state.RetrievedDocs)
+	// A scoring failure is not fatal: the documents come back in retrieval
+	// order and the error is kept with the step's retrieval context
+	reranked, err := n.reranker.Rerank(ctx, currentStep.SubQuestion, state.RetrievedDocs)
 	state.RerankedDocs = reranked
+	if state.Retrieval != nil {
+		state.Retrieval.RerankError = ""
+		if err != nil {
+			state.Retrieval.RerankError = err.Error()
+		}
+	}
 
 	return &workflow.NodeResult{UpdatedState: state}, nil
 }
@@ -199,19 +417,17 @@ func (n *RerankerNode) Name() string {
 // DistillerNode wraps the distiller agent as a workflow node.
 type DistillerNode struct {
 	distiller *agent.Distiller
-	ctx       context.Context
 }
 
 // NewDistillerNode creates a new distiller node.
-func NewDistillerNode(ctx context.Context, distiller *agent.Distiller) *DistillerNode {
+func NewDistillerNode(distiller *agent.Distiller) *DistillerNode {
 	return &DistillerNode{
 		distiller: distiller,
-		ctx:       ctx,
 	}
 }
 
 // Execute synthesizes documents into coherent context.
-func (n *DistillerNode) Execute(state *workflow.State) (*workflow.NodeResult, error) {
+func (n *DistillerNode) Execute(ctx context.Context, state *workflow.State) (*workflow.NodeResult, error) {
 	if len(state.RerankedDocs) == 0 {
 		// No documents to distill
 		state.SynthesizedContext = ""
@@ -223,7 +439,7 @@ func (n *DistillerNode) Execute(state *workflow.State) (*workflow.NodeResult, er
 		return nil, fmt.Errorf("no current step available")
 	}
 
-	synthesized, err := n.distiller.Distill(n.ctx, currentStep.SubQuestion, state.RerankedDocs)
+	synthesized, err := n.distiller.Distill(ctx, currentStep.SubQuestion, state.RerankedDocs)
 	if err != nil {
 		return nil, fmt.Errorf("distillation failed: %w", err)
 	}
@@ -240,25 +456,23 @@ func (n *DistillerNode) Name() string {
 // ReflectorNode wraps the reflector agent as a workflow node.
 type ReflectorNode struct {
 	reflector *agent.Reflector
-	ctx       context.Context
 }
 
 // NewReflectorNode creates a new reflector node.
-func NewReflectorNode(ctx context.Context, reflector *agent.Reflector) *ReflectorNode {
+func NewReflectorNode(reflector *agent.Reflector) *ReflectorNode {
 	return &ReflectorNode{
 		reflector: reflector,
-		ctx:       ctx,
 	}
 }
 
 // Execute reflects on the completed step and extracts key findings.
-func (n *ReflectorNode) Execute(state *workflow.State) (*workflow.NodeResult, error) {
+func (n *ReflectorNode) Execute(ctx context.Context, state *workflow.State) (*workflow.NodeResult, error) {
 	currentStep := state.CurrentStep()
 	if currentStep == nil {
 		return nil, fmt.Errorf("no current step available")
 	}
 
-	summary, keyFindings, err := n.reflector.Reflect(n.ctx, currentStep, state.SynthesizedContext)
+	summary, keyFindings, err := n.reflector.Reflect(ctx, currentStep, state.SynthesizedContext)
 	if err != nil {
 		return nil, fmt.Errorf("reflection failed: %w", err)
 	}
@@ -270,6 +484,9 @@ func (n *ReflectorNode) Execute(state *workflow.State) (*workflow.NodeResult, er
 		Summary:       summary,
 		KeyFindings:   keyFindings,
 	}
+	if retrievalCtx := state.GetRetrievalContext(); retrievalCtx != nil {
+		pastStep.Strategy = retrievalCtx.Strategy
+	}
 
 	state.AddPastStep(pastStep)
 	state.IncrementStep()
@@ -285,41 +502,135 @@ func (n *ReflectorNode) Name() string {
 // PolicyNode wraps the policy agent as a workflow node.
 type PolicyNode struct {
 	policy *agent.Policy
-	ctx    context.Context
 }
 
 // NewPolicyNode creates a new policy node.
-func NewPolicyNode(ctx context.Context, policy *agent.Policy) *PolicyNode {
+func NewPolicyNode(policy *agent.Policy) *PolicyNode {
 	return &PolicyNode{
 		policy: policy,
-		ctx:    ctx,
 	}
 }
 
-// Execute decides whether to continue or finish the workflow.
-func (n *PolicyNode) Execute(state *workflow.State) (*workflow.NodeResult, error) {
-	decision, err := n.policy.Decide(n.ctx, state)
+// Execute decides whether to continue or finish the workflow. The decision
+// is recorded on the state; the graph routes on it.
+func (n *PolicyNode) Execute(ctx context.Context, state *workflow.State) (*workflow.NodeResult, error) {
+	decision, err := n.policy.Decide(ctx, state)
 	if err != nil {
 		return nil, fmt.Errorf("policy decision failed: %w", err)
 	}
 
 	state.ShouldContinue = decision.ShouldContinue
+	state.Decision = decision
 
-	// Determine next node
-	nextNode := ""
-	if decision.ShouldContinue {
-		nextNode = "rewriter" // Continue to next iteration
-	} else {
-		nextNode = "finish" // End workflow
-	}
-
-	return &workflow.NodeResult{
-		UpdatedState: state,
-		NextNode:     nextNode,
-	}, nil
+	return &workflow.NodeResult{UpdatedState: state}, nil
 }
 
 // Name returns the node name.
 func (n *PolicyNode) Name() string {
 	return "policy"
 }
+
+// SynthesizerNode wraps the synthesizer agent as a workflow node.
+type SynthesizerNode struct {
+	synthesizer *agent.Synthesizer
+}
+
+// NewSynthesizerNode creates a new synthesizer node.
+func NewSynthesizerNode(synthesizer *agent.Synthesizer) *SynthesizerNode {
+	return &SynthesizerNode{
+		synthesizer: synthesizer,
+	}
+}
+
+// Execute produces the final answer from the accumulated findings.
+func (n *SynthesizerNode) Execute(ctx context.Context, state *workflow.State) (*workflow.NodeResult, error) {
+	if len(state.PastSteps) == 0 {
+		// Nothing was researched, leave the final answer empty
+		return &workflow.NodeResult{UpdatedState: state}, nil
+	}
+
+	answer, err := n.synthesizer.Synthesize(ctx, state)
+	if err != nil {
+		return nil, fmt.Errorf("synthesis failed: %w", err)
+	}
+
+	state.FinalAnswer = answer
+	state.Citations = n.synthesizer.Citations(state)
+	return &workflow.NodeResult{UpdatedState: state}, nil
+}
+
+// Name returns the node name.
+func (n *SynthesizerNode) Name() string {
+	return "synthesizer"
+}
+
+// VerifierNode checks the final answer against the retrieved evidence and
+// attaches the report to the state. With corrective retrieval enabled, failed
+// claims send the run back through the step pipeline once, with a plan step
+// per claim, so the answer is synthesized and verified again.
+type VerifierNode struct {
+	verifier       *agent.Verifier
+	correctionNode string
+}
+
+// NewVerifierNode creates a new verifier node.
+func NewVerifierNode(verifier *agent.Verifier) *VerifierNode {
+	return &VerifierNode{
+		verifier: verifier,
+	}
+}
+
+// SetCorrectionNode enables corrective retrieval, resuming the run at the
+// named node (the first node of the step pipeline). An empty name disables it.
+func (n *VerifierNode) SetCorrectionNode(name string) {
+	n.correctionNode = name
+}
+
+// Execute verifies the final answer. A failed check is recorded in the
+// report rather than failing the run, since the answer itself is complete.
+func (n *VerifierNode) Execute(ctx context.Context, state *workflow.State) (*workflow.NodeResult, error) {
+	if state.FinalAnswer == "" {
+		return &workflow.NodeResult{UpdatedState: state}, nil
+	}
+
+	corrected := state.Verification != nil && state.Verification.Corrected
+
+	report, err := n.verifier.Verify(ctx, state)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("verification failed: %w", err)
+		}
+		report = &workflow.VerificationReport{Error: err.Error()}
+	}
+	report.Corrected = corrected
+	state.Verification = report
+
+	if n.correctionNode == "" || corrected || len(report.Failed()) == 0 {
+		return &workflow.NodeResult{UpdatedState: state}, nil
+	}
+
+	// Queue a corrective step per failed claim after the existing plan,
+	// leaving the original plan untouched
+	steps := n.verifier.CorrectiveSteps(state.Plan, report)
+	plan := &workflow.Plan{}
+	if state.Plan != nil {
+		*plan = *state.Plan
+	}
+	state.CurrentStepIndex = len(plan.Steps)
+	plan.Steps = append(append([]workflow.PlanStep(nil), plan.Steps...), steps...)
+	state.Plan = plan
+
+	// The corrective steps run even if the loop stopped at its iteration limit
+	if limit := len(state.PastSteps) + len(steps); state.MaxIterations < limit {
+		state.MaxIterations = limit
+	}
+	state.ShouldContinue = true
+	report.Corrected = true
+
+	return &workflow.NodeResult{UpdatedState: state, NextNode: n.correctionNode}, nil
+}
+
+// Name returns the node name.
+func (n *VerifierNode) Name() string {
+	return "verifier"
+}
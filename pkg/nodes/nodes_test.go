@@ -7,11 +7,19 @@ package nodes
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
 	"deep-thinking-agent/pkg/agent"
+	"deep-thinking-agent/pkg/embedding"
 	"deep-thinking-agent/pkg/llm"
+	"deep-thinking-agent/pkg/schema"
 	"deep-thinking-agent/pkg/vectorstore"
+	"deep-thinking-agent/pkg/vectorstore/memory"
+	"deep-thinking-agent/pkg/websearch"
 	"deep-thinking-agent/pkg/workflow"
 )
 
@@ -19,6 +27,10 @@ import (
 type mockLLM struct{}
 
 func (m *mockLLM) Complete(ctx context.Context, req *llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// Return valid JSON for planner agent
 	content := `{
 		"steps": [
@@ -35,7 +47,6 @@ func (m *mockLLM) SupportsStreaming() bool { return false }
 
 // TestLLMBasedNodes tests construction and naming of LLM-based nodes
 func TestLLMBasedNodes(t *testing.T) {
-	ctx := context.Background()
 	mockLLMProvider := &mockLLM{}
 
 	tests := []struct {
@@ -47,7 +58,7 @@ func TestLLMBasedNodes(t *testing.T) {
 			name: "PlannerNode",
 			createNode: func() interface{} {
 				planner := agent.NewPlanner(mockLLMProvider, nil)
-				return NewPlannerNode(ctx, planner)
+				return NewPlannerNode(planner)
 			},
 			expectedName: "planner",
 		},
@@ -55,7 +66,7 @@ func TestLLMBasedNodes(t *testing.T) {
 			name: "RewriterNode",
 			createNode: func() interface{} {
 				rewriter := agent.NewRewriter(mockLLMProvider, nil)
-				return NewRewriterNode(ctx, rewriter)
+				return NewRewriterNode(rewriter)
 			},
 			expectedName: "rewriter",
 		},
@@ -63,7 +74,7 @@ func TestLLMBasedNodes(t *testing.T) {
 			name: "SupervisorNode",
 			createNode: func() interface{} {
 				supervisor := agent.NewSupervisor(mockLLMProvider, nil)
-				return NewSupervisorNode(ctx, supervisor)
+				return NewSupervisorNode(supervisor)
 			},
 			expectedName: "supervisor",
 		},
@@ -71,7 +82,7 @@ func TestLLMBasedNodes(t *testing.T) {
 			name: "RerankerNode",
 			createNode: func() interface{} {
 				reranker := agent.NewReranker(nil)
-				return NewRerankerNode(ctx, reranker)
+				return NewRerankerNode(reranker)
 			},
 			expectedName: "reranker",
 		},
@@ -79,7 +90,7 @@ func TestLLMBasedNodes(t *testing.T) {
 			name: "DistillerNode",
 			createNode: func() interface{} {
 				distiller := agent.NewDistiller(mockLLMProvider, nil)
-				return NewDistillerNode(ctx, distiller)
+				return NewDistillerNode(distiller)
 			},
 			expectedName: "distiller",
 		},
@@ -87,7 +98,7 @@ func TestLLMBasedNodes(t *testing.T) {
 			name: "ReflectorNode",
 			createNode: func() interface{} {
 				reflector := agent.NewReflector(mockLLMProvider, nil)
-				return NewReflectorNode(ctx, reflector)
+				return NewReflectorNode(reflector)
 			},
 			expectedName: "reflector",
 		},
@@ -95,7 +106,7 @@ func TestLLMBasedNodes(t *testing.T) {
 			name: "PolicyNode",
 			createNode: func() interface{} {
 				policy := agent.NewPolicy(mockLLMProvider, nil)
-				return NewPolicyNode(ctx, policy)
+				return NewPolicyNode(policy)
 			},
 			expectedName: "policy",
 		},
@@ -126,17 +137,16 @@ func TestLLMBasedNodes(t *testing.T) {
 
 // TestNodeNamesAreUnique ensures all node names are distinct
 func TestNodeNamesAreUnique(t *testing.T) {
-	ctx := context.Background()
 	mockLLMProvider := &mockLLM{}
 
 	// Create all LLM-based nodes
-	planner := NewPlannerNode(ctx, agent.NewPlanner(mockLLMProvider, nil))
-	rewriter := NewRewriterNode(ctx, agent.NewRewriter(mockLLMProvider, nil))
-	supervisor := NewSupervisorNode(ctx, agent.NewSupervisor(mockLLMProvider, nil))
-	reranker := NewRerankerNode(ctx, agent.NewReranker(nil))
-	distiller := NewDistillerNode(ctx, agent.NewDistiller(mockLLMProvider, nil))
-	reflector := NewReflectorNode(ctx, agent.NewReflector(mockLLMProvider, nil))
-	policy := NewPolicyNode(ctx, agent.NewPolicy(mockLLMProvider, nil))
+	planner := NewPlannerNode(agent.NewPlanner(mockLLMProvider, nil))
+	rewriter := NewRewriterNode(agent.NewRewriter(mockLLMProvider, nil))
+	supervisor := NewSupervisorNode(agent.NewSupervisor(mockLLMProvider, nil))
+	reranker := NewRerankerNode(agent.NewReranker(nil))
+	distiller := NewDistillerNode(agent.NewDistiller(mockLLMProvider, nil))
+	reflector := NewReflectorNode(agent.NewReflector(mockLLMProvider, nil))
+	policy := NewPolicyNode(agent.NewPolicy(mockLLMProvider, nil))
 
 	// Verify all have unique names
 	names := make(map[string]bool)
@@ -167,20 +177,56 @@ func TestNodeNamesAreUnique(t *testing.T) {
 	}
 }
 
-// Note: RetrieverNode testing requires complex interface mocking and is deferred to integration tests.
+// Note: RetrieverNode document retrieval requires complex interface mocking and is deferred to integration tests.
+
+// mockSearcher implements websearch.Searcher for testing
+type mockSearcher struct{}
+
+func (m *mockSearcher) Search(ctx context.Context, query string, maxResults int) ([]websearch.Result, error) {
+	return []websearch.Result{{Title: "Result", URL: "https://example.com", Snippet: query}}, nil
+}
+
+func (m *mockSearcher) Name() string { return "mock-search" }
+
+func TestRetrieverNode_WebSearch(t *testing.T) {
+	ctx := context.Background()
+	retriever := agent.NewRetriever(nil, nil, &agent.RetrieverConfig{
+		DefaultTopK: 5,
+		WebSearcher: &mockSearcher{},
+	})
+	node := NewRetrieverNode(retriever)
+
+	state := workflow.NewState("test")
+	state.Plan = &workflow.Plan{Steps: []workflow.PlanStep{
+		{SubQuestion: "latest release", ToolType: "web_search"},
+	}}
+
+	result, err := node.Execute(ctx, state)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if result.UpdatedState.GetRetrievalContext().Strategy != workflow.StrategyWebSearch {
+		t.Errorf("Strategy = %v, want web_search", result.UpdatedState.GetRetrievalContext().Strategy)
+	}
+	docs := result.UpdatedState.RetrievedDocs
+	if len(docs) != 1 || docs[0].Metadata[websearch.MetadataSourceURL] != "https://example.com" {
+		t.Errorf("unexpected documents: %v", docs)
+	}
+}
 
 func TestPlannerNode_Execute(t *testing.T) {
 	ctx := context.Background()
 	mockLLMProvider := &mockLLM{}
 	planner := agent.NewPlanner(mockLLMProvider, nil)
-	node := NewPlannerNode(ctx, planner)
+	node := NewPlannerNode(planner)
 
 	t.Run("successful planning", func(t *testing.T) {
 		state := &workflow.State{
 			OriginalQuestion: "What is the capital of France?",
 		}
 
-		result, err := node.Execute(state)
+		result, err := node.Execute(ctx, state)
 		if err != nil {
 			t.Fatalf("Execute() failed: %v", err)
 		}
@@ -198,6 +244,20 @@ func TestPlannerNode_Execute(t *testing.T) {
 		}
 	})
 
+	t.Run("cancelled context", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		state := &workflow.State{
+			OriginalQuestion: "What is the capital of France?",
+		}
+
+		_, err := node.Execute(cancelled, state)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Execute() error = %v, want context canceled", err)
+		}
+	})
+
 	t.Run("node name", func(t *testing.T) {
 		if node.Name() != "planner" {
 			t.Errorf("expected name 'planner', got %s", node.Name())
@@ -205,23 +265,337 @@ func TestPlannerNode_Execute(t *testing.T) {
 	})
 }
 
+// promptLLM records the last user prompt and returns a fixed response.
+type promptLLM struct {
+	response string
+	prompt   string
+}
+
+func (p *promptLLM) Complete(ctx context.Context, req *llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	p.prompt = req.Messages[len(req.Messages)-1].Content
+	return &llm.CompletionResponse{Content: p.response, FinishReason: "stop", Model: "mock"}, nil
+}
+
+func (p *promptLLM) Name() string            { return "mock" }
+func (p *promptLLM) ModelName() string       { return "mock-model" }
+func (p *promptLLM) SupportsStreaming() bool { return false }
+
+// newSchemaStore returns a store holding a schema for each doc ID.
+func newSchemaStore(t *testing.T, docIDs ...string) schema.SchemaStore {
+	t.Helper()
+	store := schema.NewMemorySchemaStore()
+	for _, docID := range docIDs {
+		err := store.Save(context.Background(), &schema.IndexEntry{
+			DocID: docID,
+			Schema: &schema.DocumentSchema{
+				DocID:    docID,
+				Title:    "Report " + docID,
+				Sections: []schema.Section{{ID: "s1", Type: "risk_factors"}},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store
+}
+
+func TestPlannerNode_SchemaStore(t *testing.T) {
+	ctx := context.Background()
+	provider := &promptLLM{response: `{"steps": [{"index": 0, "sub_question": "What are the risks?", "dependencies": []}]}`}
+	node := NewPlannerNode(agent.NewPlanner(provider, nil))
+	node.SetSchemaStore(newSchemaStore(t, "a.pdf", "b.pdf"))
+
+	t.Run("loads stored schemas", func(t *testing.T) {
+		result, err := node.Execute(ctx, workflow.NewState("What are the risks?"))
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+
+		if schemas := result.UpdatedState.RelevantSchemas; len(schemas) != 2 || schemas["a.pdf"] == nil {
+			t.Errorf("RelevantSchemas = %v, want a.pdf and b.pdf", schemas)
+		}
+		if !strings.Contains(provider.prompt, "- a.pdf (Report a.pdf): risk_factors") {
+			t.Errorf("planning prompt does not describe the schemas:\n%s", provider.prompt)
+		}
+	})
+
+	t.Run("documents in scope", func(t *testing.T) {
+		state := workflow.NewState("What are the risks?")
+		state.ActiveFilters = &workflow.SchemaFilters{DocumentIDs: []string{"b.pdf", "missing.pdf"}}
+
+		result, err := node.Execute(ctx, state)
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if schemas := result.UpdatedState.RelevantSchemas; len(schemas) != 1 || schemas["b.pdf"] == nil {
+			t.Errorf("RelevantSchemas = %v, want only b.pdf", schemas)
+		}
+	})
+}
+
+// topicEmbedder embeds texts mentioning zebras apart from all others.
+type topicEmbedder struct{}
+
+func (e *topicEmbedder) Embed(ctx context.Context, req *embedding.EmbedRequest) (*embedding.EmbedResponse, error) {
+	vectors := make([]embedding.Vector, len(req.Texts))
+	for i, text := range req.Texts {
+		vector := []float32{0, 1}
+		if strings.Contains(strings.ToLower(text), "zebra") {
+			vector = []float32{1, 0}
+		}
+		vectors[i] = embedding.Vector{Embedding: vector, Text: text}
+	}
+	return &embedding.EmbedResponse{Vectors: vectors}, nil
+}
+
+func (e *topicEmbedder) Dimensions() int   { return 2 }
+func (e *topicEmbedder) ModelName() string { return "topic" }
+
+func TestPlannerNode_CandidateDocuments(t *testing.T) {
+	ctx := context.Background()
+	store, err := memory.NewStore(&vectorstore.Config{DefaultCollection: "documents"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemas := schema.NewMemorySchemaStore()
+
+	// More documents than the planner loads; the relevant one sorts last
+	var chunks []vectorstore.Document
+	for i := 0; i < maxPlanningSchemas+5; i++ {
+		docID := fmt.Sprintf("doc-%02d.pdf", i)
+		docSchema := &schema.DocumentSchema{
+			DocID:    docID,
+			Title:    "Report " + docID,
+			Sections: []schema.Section{{ID: "s1", Title: "Overview", Type: "overview"}},
+		}
+		content, vector := "Quarterly overview.", []float32{0, 1}
+		if i == maxPlanningSchemas+4 {
+			docSchema.Sections = append(docSchema.Sections, schema.Section{ID: "s2", Title: "Zebra Migration", Type: "field_notes"})
+			content, vector = "Zebra herds migrate north.", []float32{1, 0}
+		}
+		if err := schemas.Save(ctx, &schema.IndexEntry{DocID: docID, Schema: docSchema}); err != nil {
+			t.Fatal(err)
+		}
+		chunks = append(chunks, vectorstore.Document{
+			ID:        fmt.Sprintf("c%02d", i),
+			Content:   content,
+			Embedding: vector,
+			Metadata:  map[string]interface{}{"doc_id": docID},
+		})
+	}
+	if _, err := store.Insert(ctx, &vectorstore.InsertRequest{Documents: chunks}); err != nil {
+		t.Fatal(err)
+	}
+	lastDoc := fmt.Sprintf("doc-%02d.pdf", maxPlanningSchemas+4)
+
+	provider := &promptLLM{response: `{"steps": [{"index": 0, "sub_question": "Where do zebras migrate?", "schema_hint": "zebra migration", "dependencies": []}]}`}
+	planner := NewPlannerNode(agent.NewPlanner(provider, nil))
+	planner.SetSchemaStore(schemas)
+	planner.SetRetriever(agent.NewRetriever(store, &topicEmbedder{}, nil))
+
+	result, err := planner.Execute(ctx, workflow.NewState("Where do zebras migrate?"))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	state := result.UpdatedState
+
+	if len(state.RelevantSchemas) != maxPlanningSchemas || state.RelevantSchemas[lastDoc] == nil {
+		t.Fatalf("RelevantSchemas has %d documents, want %d including %s", len(state.RelevantSchemas), maxPlanningSchemas, lastDoc)
+	}
+	if !strings.Contains(provider.prompt, lastDoc) {
+		t.Errorf("planning prompt does not describe %s:\n%s", lastDoc, provider.prompt)
+	}
+
+	// The supervisor maps the step's hint onto the document's sections
+	supervisor := NewSupervisorNode(agent.NewSupervisor(&promptLLM{response: "schema_filtered"}, nil))
+	supervisor.SetSchemaStore(schemas)
+	result, err = supervisor.Execute(ctx, state)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	filters := result.UpdatedState.GetRetrievalContext().SchemaFilters
+	if filters == nil || !reflect.DeepEqual(filters.DocumentIDs, []string{lastDoc}) || !reflect.DeepEqual(filters.HierarchyPaths, []string{"s2"}) {
+		t.Errorf("SchemaFilters = %+v, want section s2 of %s", filters, lastDoc)
+	}
+}
+
+func TestSupervisorNode_SchemaStore(t *testing.T) {
+	ctx := context.Background()
+	provider := &promptLLM{response: "schema_filtered"}
+	node := NewSupervisorNode(agent.NewSupervisor(provider, nil))
+	node.SetSchemaStore(newSchemaStore(t, "a.pdf", "b.pdf"))
+
+	shared := map[string]*schema.DocumentSchema{}
+	state := workflow.NewState("What are the risks?")
+	state.RelevantSchemas = shared
+	state.Plan = &workflow.Plan{Steps: []workflow.PlanStep{{Index: 0}, {Index: 1, SubQuestion: "How are risks mitigated?"}}}
+	state.AddPastStep(workflow.PastStep{
+		Step:          state.Plan.Steps[0],
+		RetrievedDocs: []vectorstore.Document{{ID: "c1", Metadata: map[string]interface{}{"doc_id": "a.pdf"}}},
+	})
+	state.IncrementStep()
+
+	result, err := node.Execute(ctx, state)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if schemas := result.UpdatedState.RelevantSchemas; len(schemas) != 1 || schemas["a.pdf"] == nil {
+		t.Errorf("RelevantSchemas = %v, want only the retrieved a.pdf", schemas)
+	}
+	if len(shared) != 0 {
+		t.Error("loading schemas should not modify the shared map")
+	}
+	if !strings.Contains(provider.prompt, "- a.pdf (Report a.pdf): risk_factors") {
+		t.Errorf("strategy prompt does not describe the schemas:\n%s", provider.prompt)
+	}
+	if result.UpdatedState.GetRetrievalContext().Strategy != workflow.StrategySchemaFiltered {
+		t.Errorf("Strategy = %v, want schema_filtered", result.UpdatedState.GetRetrievalContext().Strategy)
+	}
+}
+
+func TestSupervisorNode_SchemaHint(t *testing.T) {
+	ctx := context.Background()
+	node := NewSupervisorNode(agent.NewSupervisor(&promptLLM{response: "schema_filtered"}, nil))
+
+	state := workflow.NewState("What are the risks?")
+	state.RelevantSchemas["a.pdf"] = &schema.DocumentSchema{
+		DocID:    "a.pdf",
+		Sections: []schema.Section{{ID: "s1", Type: "risk_factors"}, {ID: "s2", Type: "results"}},
+	}
+	state.Plan = &workflow.Plan{Steps: []workflow.PlanStep{
+		{Index: 0, SubQuestion: "What are the risks?", SchemaHint: "focus on risk sections"},
+		{Index: 1, SubQuestion: "What else?", SchemaHint: "anything relevant"},
+	}}
+
+	result, err := node.Execute(ctx, state)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	retrievalCtx := result.UpdatedState.GetRetrievalContext()
+	if !retrievalCtx.FiltersFromHint || retrievalCtx.SchemaFilters == nil ||
+		len(retrievalCtx.SchemaFilters.SectionTypes) != 1 || retrievalCtx.SchemaFilters.SectionTypes[0] != "risk_factors" {
+		t.Errorf("SchemaFilters = %+v, want section type risk_factors from the hint", retrievalCtx.SchemaFilters)
+	}
+	if state.ActiveFilters != nil {
+		t.Error("hint filters should apply to the step, not the whole run")
+	}
+
+	// A hint that matches no section leaves retrieval unfiltered
+	state.IncrementStep()
+	result, err = node.Execute(ctx, state)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if retrievalCtx := result.UpdatedState.GetRetrievalContext(); retrievalCtx.FiltersFromHint || retrievalCtx.SchemaFilters != nil {
+		t.Errorf("SchemaFilters = %+v, want none", retrievalCtx.SchemaFilters)
+	}
+}
+
+// unitEmbedder returns the same vector for every text.
+type unitEmbedder struct{}
+
+func (u *unitEmbedder) Embed(ctx context.Context, req *embedding.EmbedRequest) (*embedding.EmbedResponse, error) {
+	vectors := make([]embedding.Vector, len(req.Texts))
+	for i, text := range req.Texts {
+		vectors[i] = embedding.Vector{Embedding: []float32{1, 0}, Text: text}
+	}
+	return &embedding.EmbedResponse{Vectors: vectors}, nil
+}
+
+func (u *unitEmbedder) Dimensions() int   { return 2 }
+func (u *unitEmbedder) ModelName() string { return "unit" }
+
+func TestRetrieverNode_HintFallback(t *testing.T) {
+	ctx := context.Background()
+	store, err := memory.NewStore(&vectorstore.Config{DefaultCollection: "documents"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.Insert(ctx, &vectorstore.InsertRequest{Documents: []vectorstore.Document{
+		{ID: "c1", Content: "Markets may decline.", Embedding: []float32{1, 0}, Metadata: map[string]interface{}{"section_type": "risk_factors"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	node := NewRetrieverNode(agent.NewRetriever(store, &unitEmbedder{}, nil))
+
+	newState := func(sectionType string) *workflow.State {
+		state := workflow.NewState("What are the risks?")
+		state.Plan = &workflow.Plan{Steps: []workflow.PlanStep{{Index: 0, SubQuestion: "What are the risks?"}}}
+		retrievalCtx := state.GetRetrievalContext()
+		retrievalCtx.Strategy = workflow.StrategySchemaFiltered
+		retrievalCtx.SchemaFilters = &workflow.SchemaFilters{SectionTypes: []string{sectionType}}
+		retrievalCtx.FiltersFromHint = true
+		return state
+	}
+
+	t.Run("matching filters", func(t *testing.T) {
+		result, err := node.Execute(ctx, newState("risk_factors"))
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if len(result.UpdatedState.RetrievedDocs) != 1 || !result.UpdatedState.GetRetrievalContext().FiltersFromHint {
+			t.Errorf("expected the filtered search to find c1")
+		}
+	})
+
+	t.Run("too narrow", func(t *testing.T) {
+		result, err := node.Execute(ctx, newState("results"))
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if len(result.UpdatedState.RetrievedDocs) != 1 {
+			t.Errorf("retrieved %d documents, want the unfiltered fallback to find c1", len(result.UpdatedState.RetrievedDocs))
+		}
+		if retrievalCtx := result.UpdatedState.GetRetrievalContext(); retrievalCtx.FiltersFromHint || retrievalCtx.SchemaFilters != nil {
+			t.Errorf("fallback should clear the hint filters, got %+v", retrievalCtx.SchemaFilters)
+		}
+	})
+}
+
 func TestRewriterNode_Execute(t *testing.T) {
 	ctx := context.Background()
 	mockLLMProvider := &mockLLM{}
 	rewriter := agent.NewRewriter(mockLLMProvider, nil)
-	node := NewRewriterNode(ctx, rewriter)
+	node := NewRewriterNode(rewriter)
 
 	t.Run("no current step", func(t *testing.T) {
 		state := &workflow.State{
 			OriginalQuestion: "Test question",
 		}
 
-		_, err := node.Execute(state)
+		_, err := node.Execute(ctx, state)
 		if err == nil {
 			t.Error("expected error for no current step, got nil")
 		}
 	})
 
+	t.Run("query variants", func(t *testing.T) {
+		provider := &promptLLM{response: `{"query": "revenue growth 2024", "paraphrases": ["How much did sales rise?"], "hypothetical_answer": "Revenue rose 12%."}`}
+		expanding := NewRewriterNode(agent.NewRewriter(provider, &agent.RewriterConfig{
+			Expansion: &agent.ExpansionConfig{Paraphrases: 1, HyDE: true},
+		}))
+
+		state := workflow.NewState("How did revenue change?")
+		state.Plan = &workflow.Plan{Steps: []workflow.PlanStep{{SubQuestion: "What was revenue growth?"}}}
+
+		result, err := expanding.Execute(ctx, state)
+		if err != nil {
+			t.Fatalf("Execute() failed: %v", err)
+		}
+
+		retrievalCtx := result.UpdatedState.GetRetrievalContext()
+		if retrievalCtx.Query != "revenue growth 2024" {
+			t.Errorf("Query = %q", retrievalCtx.Query)
+		}
+		if len(retrievalCtx.Variants) != 2 || retrievalCtx.Variants[1].Kind != workflow.VariantHyDE {
+			t.Errorf("Variants = %+v", retrievalCtx.Variants)
+		}
+	})
+
 	t.Run("node name", func(t *testing.T) {
 		if node.Name() != "rewriter" {
 			t.Errorf("expected name 'rewriter', got %s", node.Name())
@@ -233,14 +607,14 @@ func TestSupervisorNode_Execute(t *testing.T) {
 	ctx := context.Background()
 	mockLLMProvider := &mockLLM{}
 	supervisor := agent.NewSupervisor(mockLLMProvider, nil)
-	node := NewSupervisorNode(ctx, supervisor)
+	node := NewSupervisorNode(supervisor)
 
 	t.Run("no current step", func(t *testing.T) {
 		state := &workflow.State{
 			OriginalQuestion: "Test question",
 		}
 
-		_, err := node.Execute(state)
+		_, err := node.Execute(ctx, state)
 		if err == nil {
 			t.Error("expected error for no current step, got nil")
 		}
@@ -256,7 +630,7 @@ func TestSupervisorNode_Execute(t *testing.T) {
 func TestRerankerNode_Execute(t *testing.T) {
 	ctx := context.Background()
 	reranker := agent.NewReranker(nil)
-	node := NewRerankerNode(ctx, reranker)
+	node := NewRerankerNode(reranker)
 
 	t.Run("no documents to rerank", func(t *testing.T) {
 		state := &workflow.State{
@@ -264,7 +638,7 @@ func TestRerankerNode_Execute(t *testing.T) {
 			RetrievedDocs:    []vectorstore.Document{},
 		}
 
-		result, err := node.Execute(state)
+		result, err := node.Execute(ctx, state)
 		if err != nil {
 			t.Fatalf("Execute() failed: %v", err)
 		}
@@ -286,12 +660,43 @@ func TestRerankerNode_Execute(t *testing.T) {
 			},
 		}
 
-		_, err := node.Execute(state)
+		_, err := node.Execute(ctx, state)
 		if err == nil {
 			t.Error("expected error for no current step, got nil")
 		}
 	})
 
+	t.Run("scoring failure is recorded", func(t *testing.T) {
+		node := NewRerankerNode(agent.NewReranker(&agent.RerankerConfig{
+			TopN:              2,
+			MinRelevanceScore: 0.5,
+			Scorer:            failingScorer{},
+		}))
+		state := &workflow.State{
+			OriginalQuestion: "Test question",
+			Plan: &workflow.Plan{Steps: []workflow.PlanStep{
+				{Index: 0, SubQuestion: "What is X?"},
+			}},
+			Retrieval: &workflow.RetrievalContext{Query: "What is X?"},
+			RetrievedDocs: []vectorstore.Document{
+				{ID: "a", Score: 0.016},
+				{ID: "b", Score: 0.033},
+			},
+		}
+
+		result, err := node.Execute(ctx, state)
+		if err != nil {
+			t.Fatalf("Execute() failed: %v", err)
+		}
+
+		if len(result.UpdatedState.RerankedDocs) != 2 || result.UpdatedState.RerankedDocs[0].ID != "b" {
+			t.Errorf("expected retrieval order [b, a], got %v", result.UpdatedState.RerankedDocs)
+		}
+		if !strings.Contains(result.UpdatedState.Retrieval.RerankError, "scorer unavailable") {
+			t.Errorf("expected rerank error on state, got %q", result.UpdatedState.Retrieval.RerankError)
+		}
+	})
+
 	t.Run("node name", func(t *testing.T) {
 		if node.Name() != "reranker" {
 			t.Errorf("expected name 'reranker', got %s", node.Name())
@@ -299,11 +704,18 @@ func TestRerankerNode_Execute(t *testing.T) {
 	})
 }
 
+// failingScorer is an agent.Scorer that always fails
+type failingScorer struct{}
+
+func (failingScorer) Score(ctx context.Context, query string, docs []vectorstore.Document) ([]float32, error) {
+	return nil, errors.New("scorer unavailable")
+}
+
 func TestDistillerNode_Execute(t *testing.T) {
 	ctx := context.Background()
 	mockLLMProvider := &mockLLM{}
 	distiller := agent.NewDistiller(mockLLMProvider, nil)
-	node := NewDistillerNode(ctx, distiller)
+	node := NewDistillerNode(distiller)
 
 	t.Run("no documents to distill", func(t *testing.T) {
 		state := &workflow.State{
@@ -311,7 +723,7 @@ func TestDistillerNode_Execute(t *testing.T) {
 			RerankedDocs:     []vectorstore.Document{},
 		}
 
-		result, err := node.Execute(state)
+		result, err := node.Execute(ctx, state)
 		if err != nil {
 			t.Fatalf("Execute() failed: %v", err)
 		}
@@ -333,7 +745,7 @@ func TestDistillerNode_Execute(t *testing.T) {
 			},
 		}
 
-		_, err := node.Execute(state)
+		_, err := node.Execute(ctx, state)
 		if err == nil {
 			t.Error("expected error for no current step, got nil")
 		}
@@ -350,14 +762,14 @@ func TestReflectorNode_Execute(t *testing.T) {
 	ctx := context.Background()
 	mockLLMProvider := &mockLLM{}
 	reflector := agent.NewReflector(mockLLMProvider, nil)
-	node := NewReflectorNode(ctx, reflector)
+	node := NewReflectorNode(reflector)
 
 	t.Run("no current step", func(t *testing.T) {
 		state := &workflow.State{
 			OriginalQuestion: "Test question",
 		}
 
-		_, err := node.Execute(state)
+		_, err := node.Execute(ctx, state)
 		if err == nil {
 			t.Error("expected error for no current step, got nil")
 		}
@@ -374,7 +786,7 @@ func TestPolicyNode_Execute(t *testing.T) {
 	ctx := context.Background()
 	mockLLMProvider := &mockLLM{}
 	policy := agent.NewPolicy(mockLLMProvider, nil)
-	node := NewPolicyNode(ctx, policy)
+	node := NewPolicyNode(policy)
 
 	t.Run("continue decision", func(t *testing.T) {
 		state := &workflow.State{
@@ -388,7 +800,7 @@ func TestPolicyNode_Execute(t *testing.T) {
 			},
 		}
 
-		result, err := node.Execute(state)
+		result, err := node.Execute(ctx, state)
 		if err != nil {
 			t.Fatalf("Execute() failed: %v", err)
 		}
@@ -402,9 +814,12 @@ func TestPolicyNode_Execute(t *testing.T) {
 			t.Error("expected updated state, got nil")
 		}
 
-		// Verify next node is set
-		if result.NextNode == "" {
-			t.Error("expected next node to be set")
+		// Routing is left to the graph
+		if result.NextNode != "" {
+			t.Errorf("NextNode = %q, want graph routing", result.NextNode)
+		}
+		if result.UpdatedState.Decision == nil {
+			t.Error("expected decision to be recorded")
 		}
 	})
 
@@ -414,3 +829,253 @@ func TestPolicyNode_Execute(t *testing.T) {
 		}
 	})
 }
+
+func TestReplanning(t *testing.T) {
+	ctx := context.Background()
+
+	newState := func() *workflow.State {
+		state := workflow.NewState("How did the company perform?")
+		state.Plan = &workflow.Plan{Steps: []workflow.PlanStep{
+			{Index: 0, SubQuestion: "What was revenue?"},
+			{Index: 1, SubQuestion: "What did the CEO say?"},
+		}}
+		state.AddPastStep(workflow.PastStep{Step: state.Plan.Steps[0], Summary: "Nothing found"})
+		state.IncrementStep()
+		return state
+	}
+
+	t.Run("policy records the replan", func(t *testing.T) {
+		node := NewPolicyNode(agent.NewPolicy(&promptLLM{response: "DECISION: replan\nREASONING: Revenue was not found"}, nil))
+
+		result, err := node.Execute(ctx, newState())
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if !result.UpdatedState.Decision.Replan || !result.UpdatedState.ShouldContinue {
+			t.Errorf("Decision = %+v, ShouldContinue = %v", result.UpdatedState.Decision, result.UpdatedState.ShouldContinue)
+		}
+	})
+
+	t.Run("planner revises remaining steps", func(t *testing.T) {
+		provider := &promptLLM{response: `{"steps": [{"index": 2, "sub_question": "Which segments drove revenue?"}, {"index": 1, "sub_question": "What did the CEO say?"}]}`}
+		node := NewPlannerNode(agent.NewPlanner(provider, nil))
+
+		state := newState()
+		state.Decision = &workflow.PolicyDecision{ShouldContinue: true, Replan: true, Reasoning: "Revenue was not found"}
+
+		result, err := node.Execute(ctx, state)
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+
+		plan := result.UpdatedState.Plan
+		if len(plan.Steps) != 3 || plan.Revision != 1 || plan.Steps[1].SubQuestion != "Which segments drove revenue?" {
+			t.Fatalf("Plan = %+v", plan)
+		}
+		if result.UpdatedState.CurrentStepIndex != 1 {
+			t.Errorf("CurrentStepIndex = %d, want 1", result.UpdatedState.CurrentStepIndex)
+		}
+		if !strings.Contains(provider.prompt, "Nothing found") || !strings.Contains(provider.prompt, "Revenue was not found") {
+			t.Errorf("prompt missing findings or reasoning:\n%s", provider.prompt)
+		}
+	})
+
+	t.Run("failed revision keeps the plan", func(t *testing.T) {
+		node := NewPlannerNode(agent.NewPlanner(&promptLLM{response: "not json"}, nil))
+
+		state := newState()
+		requested := &workflow.PolicyDecision{ShouldContinue: true, Replan: true, Reasoning: "Revenue was not found"}
+		state.Decision = requested
+		plan := state.Plan
+
+		result, err := node.Execute(ctx, state)
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if result.UpdatedState.Plan != plan || result.UpdatedState.CurrentStepIndex != 1 {
+			t.Errorf("plan changed after a failed revision")
+		}
+
+		decision := result.UpdatedState.Decision
+		if decision == requested || decision.Replan || !decision.ShouldContinue {
+			t.Errorf("Decision = %+v, want a new decision that continues without replanning", decision)
+		}
+		if !strings.HasPrefix(decision.Reasoning, "Revenue was not found") || !strings.Contains(decision.Reasoning, "plan revision failed") {
+			t.Errorf("Reasoning = %q, want the failure noted", decision.Reasoning)
+		}
+		if !requested.Replan {
+			t.Error("the original decision was modified")
+		}
+	})
+
+	t.Run("policy lists steps not yet completed", func(t *testing.T) {
+		provider := &promptLLM{response: "DECISION: continue"}
+		node := NewPolicyNode(agent.NewPolicy(provider, nil))
+
+		// Step 2 ran in parallel with step 0, so only step 1 remains
+		state := workflow.NewState("How did the company perform?")
+		state.Plan = &workflow.Plan{Steps: []workflow.PlanStep{
+			{Index: 0, SubQuestion: "What was revenue?"},
+			{Index: 1, SubQuestion: "What did the CEO say?", Dependencies: []int{0}},
+			{Index: 2, SubQuestion: "What were the costs?"},
+		}}
+		state.AddPastStep(workflow.PastStep{Step: state.Plan.Steps[0], Summary: "Revenue grew"})
+		state.AddPastStep(workflow.PastStep{Step: state.Plan.Steps[2], Summary: "Costs fell"})
+		state.IncrementStep()
+
+		if _, err := node.Execute(ctx, state); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if !strings.Contains(provider.prompt, "- What did the CEO say?") || strings.Contains(provider.prompt, "- What were the costs?") {
+			t.Errorf("remaining steps should list only step 1:\n%s", provider.prompt)
+		}
+	})
+}
+
+func TestSynthesizerNode_Execute(t *testing.T) {
+	ctx := context.Background()
+	mockLLMProvider := &mockLLM{}
+	synthesizer := agent.NewSynthesizer(mockLLMProvider, nil)
+	node := NewSynthesizerNode(synthesizer)
+
+	t.Run("no past steps", func(t *testing.T) {
+		state := &workflow.State{
+			OriginalQuestion: "Test question",
+		}
+
+		result, err := node.Execute(ctx, state)
+		if err != nil {
+			t.Fatalf("Execute() failed: %v", err)
+		}
+
+		if result.UpdatedState.FinalAnswer != "" {
+			t.Errorf("expected empty final answer, got %s", result.UpdatedState.FinalAnswer)
+		}
+	})
+
+	t.Run("sets final answer", func(t *testing.T) {
+		state := &workflow.State{
+			OriginalQuestion: "Test question",
+			PastSteps: []workflow.PastStep{
+				{Step: workflow.PlanStep{SubQuestion: "Step 1"}, Summary: "Found something"},
+			},
+		}
+
+		result, err := node.Execute(ctx, state)
+		if err != nil {
+			t.Fatalf("Execute() failed: %v", err)
+		}
+
+		if result.UpdatedState.FinalAnswer == "" {
+			t.Error("expected final answer to be set")
+		}
+	})
+
+	t.Run("resolves citations", func(t *testing.T) {
+		citing := NewSynthesizerNode(agent.NewSynthesizer(&promptLLM{response: "Revenue grew [2]."}, nil))
+		state := &workflow.State{
+			OriginalQuestion: "Test question",
+			PastSteps: []workflow.PastStep{
+				{
+					Step:    workflow.PlanStep{SubQuestion: "Step 1"},
+					Summary: "Found something",
+					RetrievedDocs: []vectorstore.Document{
+						{ID: "c0", Content: "costs", Score: 0.8, Metadata: map[string]interface{}{"doc_id": "a.md"}},
+						{ID: "c1", Content: "revenue", Score: 0.6, Metadata: map[string]interface{}{"doc_id": "b.md", "start_pos": 10, "end_pos": 17}},
+					},
+				},
+			},
+		}
+
+		result, err := citing.Execute(ctx, state)
+		if err != nil {
+			t.Fatalf("Execute() failed: %v", err)
+		}
+
+		citations := result.UpdatedState.Citations
+		if len(citations) != 1 || citations[0].ChunkID != "c1" || citations[0].DocID != "b.md" || citations[0].EndPos != 17 {
+			t.Errorf("Citations = %+v, want chunk c1 of b.md", citations)
+		}
+	})
+
+	t.Run("node name", func(t *testing.T) {
+		if node.Name() != "synthesizer" {
+			t.Errorf("expected name 'synthesizer', got %s", node.Name())
+		}
+	})
+}
+
+func TestVerifierNode(t *testing.T) {
+	ctx := context.Background()
+	response := `{"claims": [{"claim": "Revenue grew 10%", "status": "supported"}, {"claim": "Margins were 40%", "status": "unsupported"}]}`
+
+	newState := func() *workflow.State {
+		state := workflow.NewState("How did the company do?")
+		state.Plan = &workflow.Plan{Steps: []workflow.PlanStep{{Index: 0, SubQuestion: "Revenue?"}}}
+		state.AddPastStep(workflow.PastStep{Step: state.Plan.Steps[0], RetrievedDocs: []vectorstore.Document{{ID: "c0", Content: "Revenue grew 10%."}}})
+		state.CurrentStepIndex = 1
+		state.MaxIterations = 1
+		state.ShouldContinue = false
+		state.FinalAnswer = "Revenue grew 10% with 40% margins."
+		return state
+	}
+
+	t.Run("attaches report", func(t *testing.T) {
+		node := NewVerifierNode(agent.NewVerifier(&promptLLM{response: response}, nil))
+
+		result, err := node.Execute(ctx, newState())
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+
+		report := result.UpdatedState.Verification
+		if report == nil || len(report.Claims) != 2 || len(report.Failed()) != 1 {
+			t.Fatalf("Verification = %+v", report)
+		}
+		if result.NextNode != "" {
+			t.Errorf("NextNode = %q without corrective retrieval", result.NextNode)
+		}
+	})
+
+	t.Run("corrective retrieval runs once", func(t *testing.T) {
+		node := NewVerifierNode(agent.NewVerifier(&promptLLM{response: response}, nil))
+		node.SetCorrectionNode("rewriter")
+
+		result, err := node.Execute(ctx, newState())
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+
+		state := result.UpdatedState
+		if result.NextNode != "rewriter" || !state.Verification.Corrected {
+			t.Fatalf("NextNode = %q, Corrected = %v", result.NextNode, state.Verification.Corrected)
+		}
+		if len(state.Plan.Steps) != 2 || state.CurrentStepIndex != 1 || state.Plan.Steps[1].Index != 1 {
+			t.Errorf("plan = %+v, current step %d", state.Plan.Steps, state.CurrentStepIndex)
+		}
+		if !state.ShouldContinue || state.MaxIterations < 2 {
+			t.Errorf("ShouldContinue = %v, MaxIterations = %d", state.ShouldContinue, state.MaxIterations)
+		}
+
+		// After the corrective loop the answer is only verified
+		result, err = node.Execute(ctx, state)
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if result.NextNode != "" || !result.UpdatedState.Verification.Corrected {
+			t.Errorf("second pass NextNode = %q, Corrected = %v", result.NextNode, result.UpdatedState.Verification.Corrected)
+		}
+	})
+
+	t.Run("failed check keeps the answer", func(t *testing.T) {
+		node := NewVerifierNode(agent.NewVerifier(&promptLLM{response: "not json"}, nil))
+
+		result, err := node.Execute(ctx, newState())
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if result.UpdatedState.Verification.Error == "" || result.UpdatedState.FinalAnswer == "" {
+			t.Errorf("Verification = %+v", result.UpdatedState.Verification)
+		}
+	})
+}
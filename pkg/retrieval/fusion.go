@@ -0,0 +1,44 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package retrieval
+
+import (
+	"sort"
+
+	"deep-thinking-agent/pkg/vectorstore"
+)
+
+// DefaultRRFK is the standard Reciprocal Rank Fusion constant.
+const DefaultRRFK = 60
+
+// FuseRRF merges ranked result lists with Reciprocal Rank Fusion: each
+// document scores the sum of 1/(k+rank) over the lists it appears in, and
+// the fused documents carry that score. Documents are identified by ID and
+// keep their content from the first list they appear in; ties keep the order
+// of first appearance.
+func FuseRRF(k int, lists ...[]vectorstore.Document) []vectorstore.Document {
+	scores := make(map[string]float64)
+	var docs []vectorstore.Document
+
+	for _, list := range lists {
+		for i, doc := range list {
+			if _, exists := scores[doc.ID]; !exists {
+				docs = append(docs, doc)
+			}
+			scores[doc.ID] += 1.0 / float64(i+1+k)
+		}
+	}
+
+	sort.SliceStable(docs, func(i, j int) bool {
+		return scores[docs[i].ID] > scores[docs[j].ID]
+	})
+
+	for i := range docs {
+		docs[i].Score = float32(scores[docs[i].ID])
+	}
+
+	return docs
+}
@@ -7,7 +7,6 @@ package retrieval
 
 import (
 	"context"
-	"sort"
 
 	"deep-thinking-agent/pkg/vectorstore"
 )
@@ -25,19 +24,19 @@ func NewHybridRetriever(vectorRet *VectorRetriever, keywordRet *KeywordRetriever
 	return &HybridRetriever{
 		vectorRetriever:  vectorRet,
 		keywordRetriever: keywordRet,
-		rrfK:             60, // Standard RRF value
+		rrfK:             DefaultRRFK,
 	}
 }
 
 // Search performs hybrid search combining vector and keyword results.
-func (h *HybridRetriever) Search(ctx context.Context, query string, topK int, filters map[string]interface{}) ([]vectorstore.Document, error) {
+func (h *HybridRetriever) Search(ctx context.Context, query string, topK int, filter vectorstore.Filter) ([]vectorstore.Document, error) {
 	// Retrieve from both strategies
-	vectorResults, err := h.vectorRetriever.Search(ctx, query, topK*2, filters)
+	vectorResults, err := h.vectorRetriever.Search(ctx, query, topK*2, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	keywordResults, err := h.keywordRetriever.Search(ctx, query, topK*2, filters)
+	keywordResults, err := h.keywordRetriever.Search(ctx, query, topK*2, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -55,68 +54,7 @@ func (h *HybridRetriever) Search(ctx context.Context, query string, topK int, fi
 
 // fuseRRF applies Reciprocal Rank Fusion to merge ranked lists.
 func (h *HybridRetriever) fuseRRF(vectorResults, keywordResults []vectorstore.Document) []vectorstore.Document {
-	// Build rank maps
-	vectorRanks := make(map[string]int)
-	for i, doc := range vectorResults {
-		vectorRanks[doc.ID] = i + 1
-	}
-
-	keywordRanks := make(map[string]int)
-	for i, doc := range keywordResults {
-		keywordRanks[doc.ID] = i + 1
-	}
-
-	// Combine unique documents
-	docMap := make(map[string]vectorstore.Document)
-	for _, doc := range vectorResults {
-		docMap[doc.ID] = doc
-	}
-	for _, doc := range keywordResults {
-		if _, exists := docMap[doc.ID]; !exists {
-			docMap[doc.ID] = doc
-		}
-	}
-
-	// Calculate RRF scores
-	type rrfDoc struct {
-		doc   vectorstore.Document
-		score float64
-	}
-
-	rrfResults := make([]rrfDoc, 0, len(docMap))
-	for id, doc := range docMap {
-		score := 0.0
-
-		// Add vector rank contribution
-		if rank, exists := vectorRanks[id]; exists {
-			score += 1.0 / float64(rank+h.rrfK)
-		}
-
-		// Add keyword rank contribution
-		if rank, exists := keywordRanks[id]; exists {
-			score += 1.0 / float64(rank+h.rrfK)
-		}
-
-		rrfResults = append(rrfResults, rrfDoc{
-			doc:   doc,
-			score: score,
-		})
-	}
-
-	// Sort by RRF score
-	sort.Slice(rrfResults, func(i, j int) bool {
-		return rrfResults[i].score > rrfResults[j].score
-	})
-
-	// Convert back to documents
-	results := make([]vectorstore.Document, len(rrfResults))
-	for i, r := range rrfResults {
-		doc := r.doc
-		doc.Score = float32(r.score)
-		results[i] = doc
-	}
-
-	return results
+	return FuseRRF(h.rrfK, vectorResults, keywordResults)
 }
 
 // Name returns the retriever name.
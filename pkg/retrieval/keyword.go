@@ -34,7 +34,7 @@ func NewKeywordRetriever(store vectorstore.Store) *KeywordRetriever {
 // This implementation builds an in-memory inverted index from the corpus
 // for BM25 scoring. For large-scale production systems, integrate with
 // Elasticsearch or similar for better performance.
-func (k *KeywordRetriever) Search(ctx context.Context, query string, topK int, filters map[string]interface{}) ([]vectorstore.Document, error) {
+func (k *KeywordRetriever) Search(ctx context.Context, query string, topK int, filter vectorstore.Filter) ([]vectorstore.Document, error) {
 	// Tokenize query
 	queryTerms := k.tokenize(query)
 	if len(queryTerms) == 0 {
@@ -51,7 +51,7 @@ func (k *KeywordRetriever) Search(ctx context.Context, query string, topK int, f
 
 	// Use List() to fetch documents without requiring vector similarity
 	// This is more efficient than the previous dummy vector approach
-	allDocs, err := k.store.List(ctx, "", filters, fetchLimit, 0)
+	allDocs, err := k.store.List(ctx, "", filter, fetchLimit, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -8,6 +8,7 @@ package retrieval
 import (
 	"context"
 	"errors"
+	"reflect"
 	"testing"
 
 	"deep-thinking-agent/pkg/embedding"
@@ -436,6 +437,35 @@ func TestFuseRRF(t *testing.T) {
 	}
 }
 
+func TestFuseRRF_Lists(t *testing.T) {
+	query := []vectorstore.Document{{ID: "a", Score: 0.9}, {ID: "b", Score: 0.8}}
+	paraphrase := []vectorstore.Document{{ID: "c", Score: 0.9}, {ID: "b", Score: 0.7}}
+	hyde := []vectorstore.Document{{ID: "b", Score: 0.95}, {ID: "d", Score: 0.6}}
+
+	fused := FuseRRF(DefaultRRFK, query, paraphrase, hyde)
+
+	ids := make([]string, len(fused))
+	for i, doc := range fused {
+		ids[i] = doc.ID
+	}
+	// b appears in every list; a and c tie at rank 1 and keep first-seen order
+	if want := []string{"b", "a", "c", "d"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("order = %v, want %v", ids, want)
+	}
+
+	wantScore := float32(2.0/62 + 1.0/61)
+	if diff := fused[0].Score - wantScore; diff > 1e-6 || diff < -1e-6 {
+		t.Errorf("b score = %v, want %v", fused[0].Score, wantScore)
+	}
+	if query[0].Score != 0.9 {
+		t.Error("input documents should not be modified")
+	}
+
+	if fused := FuseRRF(DefaultRRFK); len(fused) != 0 {
+		t.Errorf("FuseRRF() with no lists = %v", fused)
+	}
+}
+
 // Schema Retriever Tests
 func TestNewSchemaRetriever(t *testing.T) {
 	store := &mockVectorStore{}
@@ -488,35 +518,47 @@ func TestBuildMetadataFilters(t *testing.T) {
 	tests := []struct {
 		name          string
 		schemaFilters *workflow.SchemaFilters
-		wantNil       bool
-		checkKeys     []string
+		want          vectorstore.Filter
 	}{
 		{
 			name:          "nil filters",
 			schemaFilters: nil,
-			wantNil:       true,
+			want:          nil,
 		},
 		{
 			name: "with document IDs",
 			schemaFilters: &workflow.SchemaFilters{
 				DocumentIDs: []string{"doc1", "doc2"},
 			},
-			wantNil:   false,
-			checkKeys: []string{"doc_id"},
+			want: vectorstore.In("doc_id", "doc1", "doc2"),
 		},
 		{
 			name: "with all filters",
 			schemaFilters: &workflow.SchemaFilters{
 				DocumentIDs:       []string{"doc1"},
 				SectionTypes:      []string{"risk"},
+				HierarchyPaths:    []string{"s1"},
 				SemanticTags:      []string{"tag1"},
 				MinRelevanceScore: 0.8,
 				CustomAttributes: map[string]interface{}{
 					"custom": "value",
+					"years":  []int{2024, 2025},
 				},
 			},
-			wantNil:   false,
-			checkKeys: []string{"doc_id", "section_type", "semantic_tags", "min_score", "custom"},
+			// The score threshold is applied to results, not as a metadata filter
+			want: vectorstore.And(
+				vectorstore.In("doc_id", "doc1"),
+				vectorstore.In("section_type", "risk"),
+				vectorstore.In("hierarchy_path", "s1"),
+				vectorstore.In("semantic_tags", "tag1"),
+				vectorstore.Eq("custom", "value"),
+				vectorstore.In("years", 2024, 2025),
+			),
+		},
+		{
+			name:          "score threshold only",
+			schemaFilters: &workflow.SchemaFilters{MinRelevanceScore: 0.5},
+			want:          nil,
 		},
 	}
 
@@ -524,23 +566,28 @@ func TestBuildMetadataFilters(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			filters := retriever.buildMetadataFilters(tt.schemaFilters)
 
-			if tt.wantNil {
-				if filters != nil {
-					t.Error("expected nil filters")
-				}
-				return
+			if !reflect.DeepEqual(filters, tt.want) {
+				t.Errorf("buildMetadataFilters() = %#v, want %#v", filters, tt.want)
 			}
+		})
+	}
+}
 
-			if filters == nil {
-				t.Fatal("filters should not be nil")
-			}
+func TestSchemaRetriever_MinRelevanceScore(t *testing.T) {
+	docs := []vectorstore.Document{
+		{ID: "doc1", Content: "content", Score: 0.9},
+		{ID: "doc2", Content: "content", Score: 0.7},
+		{ID: "doc3", Content: "content", Score: 0.4},
+	}
 
-			for _, key := range tt.checkKeys {
-				if _, exists := filters[key]; !exists {
-					t.Errorf("filter key %s not found", key)
-				}
-			}
-		})
+	retriever := NewSchemaRetriever(NewVectorRetriever(&mockVectorStore{searchResults: docs}, &mockEmbedder{}))
+
+	results, err := retriever.Search(context.Background(), "test", 10, &workflow.SchemaFilters{MinRelevanceScore: 0.6})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 || results[1].ID != "doc2" {
+		t.Errorf("results = %v, want doc1 and doc2", results)
 	}
 }
 
@@ -554,9 +601,7 @@ func TestSearchWithFilters(t *testing.T) {
 	vectorRet := NewVectorRetriever(store, embedder)
 	retriever := NewSchemaRetriever(vectorRet)
 
-	filters := map[string]interface{}{
-		"section_type": "risk_factors",
-	}
+	filters := vectorstore.Eq("section_type", "risk_factors")
 
 	results, err := retriever.SearchWithFilters(context.Background(), "test", 10, filters)
 	if err != nil {
@@ -7,6 +7,7 @@ package retrieval
 
 import (
 	"context"
+	"sort"
 
 	"deep-thinking-agent/pkg/vectorstore"
 	"deep-thinking-agent/pkg/workflow"
@@ -25,59 +26,99 @@ func NewSchemaRetriever(vectorRet *VectorRetriever) *SchemaRetriever {
 	}
 }
 
-// Search performs schema-filtered retrieval.
+// Search performs schema-filtered retrieval. Results scoring below the
+// filters' minimum relevance score are dropped.
 func (s *SchemaRetriever) Search(ctx context.Context, query string, topK int, schemaFilters *workflow.SchemaFilters) ([]vectorstore.Document, error) {
 	// Convert schema filters to metadata filters
-	filters := s.buildMetadataFilters(schemaFilters)
+	filter := s.buildMetadataFilters(schemaFilters)
 
 	// Use vector search with enhanced filters
-	return s.vectorRetriever.Search(ctx, query, topK, filters)
+	docs, err := s.vectorRetriever.Search(ctx, query, topK, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if schemaFilters == nil || schemaFilters.MinRelevanceScore <= 0 {
+		return docs, nil
+	}
+
+	// Results are sorted by score, so the threshold keeps a prefix
+	for i, doc := range docs {
+		if doc.Score < schemaFilters.MinRelevanceScore {
+			return docs[:i], nil
+		}
+	}
+	return docs, nil
 }
 
-// SearchWithFilters performs retrieval with explicit metadata filters.
-func (s *SchemaRetriever) SearchWithFilters(ctx context.Context, query string, topK int, filters map[string]interface{}) ([]vectorstore.Document, error) {
-	return s.vectorRetriever.Search(ctx, query, topK, filters)
+// SearchWithFilters performs retrieval with an explicit metadata filter.
+func (s *SchemaRetriever) SearchWithFilters(ctx context.Context, query string, topK int, filter vectorstore.Filter) ([]vectorstore.Document, error) {
+	return s.vectorRetriever.Search(ctx, query, topK, filter)
 }
 
 // buildMetadataFilters converts schema filters to vector store filters.
-func (s *SchemaRetriever) buildMetadataFilters(schemaFilters *workflow.SchemaFilters) map[string]interface{} {
+func (s *SchemaRetriever) buildMetadataFilters(schemaFilters *workflow.SchemaFilters) vectorstore.Filter {
+	return MetadataFilter(schemaFilters)
+}
+
+// MetadataFilter converts schema filters into a vector store filter over the
+// chunk metadata written at ingest time. Each populated dimension must match;
+// within a dimension any listed value matches. Custom attributes match by
+// equality, or any-of for slice values. MinRelevanceScore is a score
+// threshold rather than a metadata condition, so it is not included.
+// It returns nil when no dimension is set.
+func MetadataFilter(schemaFilters *workflow.SchemaFilters) vectorstore.Filter {
 	if schemaFilters == nil {
 		return nil
 	}
 
-	filters := make(map[string]interface{})
+	var filters []vectorstore.Filter
 
 	// Document IDs
 	if len(schemaFilters.DocumentIDs) > 0 {
-		filters["doc_id"] = schemaFilters.DocumentIDs
+		filters = append(filters, vectorstore.In("doc_id", schemaFilters.DocumentIDs...))
 	}
 
 	// Section types
 	if len(schemaFilters.SectionTypes) > 0 {
-		filters["section_type"] = schemaFilters.SectionTypes
+		filters = append(filters, vectorstore.In("section_type", schemaFilters.SectionTypes...))
 	}
 
 	// Hierarchy paths
 	if len(schemaFilters.HierarchyPaths) > 0 {
-		filters["hierarchy_path"] = schemaFilters.HierarchyPaths
+		filters = append(filters, vectorstore.In("hierarchy_path", schemaFilters.HierarchyPaths...))
 	}
 
 	// Semantic tags
 	if len(schemaFilters.SemanticTags) > 0 {
-		filters["semantic_tags"] = schemaFilters.SemanticTags
+		filters = append(filters, vectorstore.In("semantic_tags", schemaFilters.SemanticTags...))
 	}
 
-	// Minimum relevance score
-	if schemaFilters.MinRelevanceScore > 0 {
-		filters["min_score"] = schemaFilters.MinRelevanceScore
+	// Custom attributes, in key order so filters are deterministic
+	keys := make([]string, 0, len(schemaFilters.CustomAttributes))
+	for key := range schemaFilters.CustomAttributes {
+		keys = append(keys, key)
 	}
-
-	// Custom attributes
-	for key, value := range schemaFilters.CustomAttributes {
-		filters[key] = value
+	sort.Strings(keys)
+	for _, key := range keys {
+		filters = append(filters, attributeFilter(key, schemaFilters.CustomAttributes[key]))
 	}
 
-	return filters
+	return vectorstore.And(filters...)
+}
+
+// attributeFilter matches a custom attribute: any-of for slices, otherwise equality.
+func attributeFilter(key string, value interface{}) vectorstore.Filter {
+	switch values := value.(type) {
+	case []string:
+		return vectorstore.In(key, values...)
+	case []int:
+		return vectorstore.In(key, values...)
+	case []interface{}:
+		return vectorstore.In(key, values...)
+	default:
+		return vectorstore.Eq(key, value)
+	}
 }
 
 // Name returns the retriever name.
@@ -28,7 +28,7 @@ func NewVectorRetriever(store vectorstore.Store, embedder embedding.Embedder) *V
 }
 
 // Search performs semantic vector similarity search.
-func (v *VectorRetriever) Search(ctx context.Context, query string, topK int, filters map[string]interface{}) ([]vectorstore.Document, error) {
+func (v *VectorRetriever) Search(ctx context.Context, query string, topK int, filter vectorstore.Filter) ([]vectorstore.Document, error) {
 	// Generate query embedding
 	embedResp, err := v.embedder.Embed(ctx, &embedding.EmbedRequest{
 		Texts: []string{query},
@@ -45,7 +45,7 @@ func (v *VectorRetriever) Search(ctx context.Context, query string, topK int, fi
 	searchResp, err := v.store.Search(ctx, &vectorstore.SearchRequest{
 		Vector: embedResp.Vectors[0].Embedding,
 		TopK:   topK,
-		Filter: filters,
+		Filter: filter,
 	})
 
 	if err != nil {
@@ -125,16 +125,19 @@ func overlaps(start1, end1, start2, end2 int) bool {
 	return start1 < end2 && end1 > start2
 }
 
-// DocumentIndex manages document-level schema indexing.
+// DocumentIndex manages document-level schema indexing in memory.
+//
+// Deprecated: Use a SchemaStore, which persists schemas across restarts and
+// is safe for concurrent use.
 type DocumentIndex struct {
 	entries map[string]*IndexEntry
 }
 
 // IndexEntry contains schema and chunk references for a document.
 type IndexEntry struct {
-	DocID    string
-	Schema   *DocumentSchema
-	ChunkIDs []string
+	DocID    string          `json:"doc_id"`
+	Schema   *DocumentSchema `json:"schema"`
+	ChunkIDs []string        `json:"chunk_ids"`
 }
 
 // NewDocumentIndex creates a new document index.
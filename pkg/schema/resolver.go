@@ -8,6 +8,7 @@ package schema
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"deep-thinking-agent/pkg/llm"
@@ -70,8 +71,10 @@ func (r *Resolver) Resolve(ctx context.Context, docID, content, format string, e
 	// Check cache first
 	if r.cache != nil {
 		if cached := r.cache.Get(docID); cached != nil {
-			cached.ProcessingTimeMs = time.Since(startTime).Milliseconds()
-			return cached, nil
+			// Copy so concurrent callers do not share the timing field
+			result := *cached
+			result.ProcessingTimeMs = time.Since(startTime).Milliseconds()
+			return &result, nil
 		}
 	}
 
@@ -181,7 +184,9 @@ func contains(text, substr string) bool {
 }
 
 // SchemaCache provides caching for resolved schemas.
+// It is safe for concurrent use.
 type SchemaCache struct {
+	mu    sync.Mutex
 	cache map[string]*cachedResult
 	ttl   time.Duration
 }
@@ -201,6 +206,9 @@ func NewSchemaCache(ttl time.Duration) *SchemaCache {
 
 // Get retrieves a cached schema result.
 func (c *SchemaCache) Get(docID string) *ResolutionResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if cached, ok := c.cache[docID]; ok {
 		// Check if expired
 		if time.Since(cached.timestamp) > c.ttl {
@@ -214,6 +222,9 @@ func (c *SchemaCache) Get(docID string) *ResolutionResult {
 
 // Set stores a schema result in cache.
 func (c *SchemaCache) Set(docID string, result *ResolutionResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.cache[docID] = &cachedResult{
 		result:    result,
 		timestamp: time.Now(),
@@ -222,5 +233,8 @@ func (c *SchemaCache) Set(docID string, result *ResolutionResult) {
 
 // Clear removes all cached results.
 func (c *SchemaCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.cache = make(map[string]*cachedResult)
 }
@@ -0,0 +1,298 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package schema
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrSchemaNotFound is returned when no schema is stored for a document.
+var ErrSchemaNotFound = errors.New("schema not found")
+
+// schemaIndexFile names the file mapping schema file names to doc IDs.
+const schemaIndexFile = "index.json"
+
+// SchemaStore persists resolved document schemas together with the IDs of
+// the chunks built from them, so schemas outlive the process that derived them.
+type SchemaStore interface {
+	// Save stores the entry, replacing any previous entry for the document
+	Save(ctx context.Context, entry *IndexEntry) error
+
+	// Load returns the entry for a document, or ErrSchemaNotFound
+	Load(ctx context.Context, docID string) (*IndexEntry, error)
+
+	// Delete removes the entry for a document. Deleting a document with no
+	// stored schema is not an error.
+	Delete(ctx context.Context, docID string) error
+
+	// List returns the IDs of all documents with a stored schema, sorted
+	List(ctx context.Context) ([]string, error)
+}
+
+// FileSchemaStore stores schemas as JSON files in a directory, one file per
+// document. File names are derived from a hash of the doc ID, since doc IDs
+// are usually paths. An index file caches the doc ID of each schema file so
+// List does not have to decode every schema.
+type FileSchemaStore struct {
+	dir string
+}
+
+// NewFileSchemaStore creates a schema store in dir, creating the directory
+// if needed.
+func NewFileSchemaStore(dir string) (*FileSchemaStore, error) {
+	if dir == "" {
+		return nil, errors.New("schema directory is required")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create schema directory: %w", err)
+	}
+
+	return &FileSchemaStore{dir: dir}, nil
+}
+
+// Save writes the entry atomically via a temporary file and rename.
+func (s *FileSchemaStore) Save(ctx context.Context, entry *IndexEntry) error {
+	if entry == nil || entry.Schema == nil {
+		return errors.New("schema entry cannot be nil")
+	}
+	if entry.DocID == "" {
+		return errors.New("schema entry has no doc ID")
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode schema: %w", err)
+	}
+
+	// A unique temporary file keeps concurrent saves from clobbering each other
+	tmp, err := os.CreateTemp(s.dir, "schema-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write schema: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write schema: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write schema: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(entry.DocID)); err != nil {
+		return fmt.Errorf("failed to write schema: %w", err)
+	}
+
+	return nil
+}
+
+// Load reads the entry for a document.
+func (s *FileSchemaStore) Load(ctx context.Context, docID string) (*IndexEntry, error) {
+	entry, err := readSchemaEntry(s.path(docID))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrSchemaNotFound, docID)
+		}
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// Delete removes the entry for a document.
+func (s *FileSchemaStore) Delete(ctx context.Context, docID string) error {
+	if err := os.Remove(s.path(docID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete schema: %w", err)
+	}
+	return nil
+}
+
+// List returns the stored doc IDs. Doc IDs are read from the index file;
+// only schema files missing from it are decoded, after which the index is
+// rewritten. Since a file name is derived from its doc ID, index entries
+// never go stale and schemas saved by other processes are picked up.
+func (s *FileSchemaStore) List(ctx context.Context) ([]string, error) {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema directory: %w", err)
+	}
+
+	index := s.readIndex()
+	current := make(map[string]string, len(files))
+	changed := false
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || name == schemaIndexFile || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+
+		docID, ok := index[name]
+		if !ok {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			entry, err := readSchemaEntry(filepath.Join(s.dir, name))
+			if errors.Is(err, os.ErrNotExist) {
+				continue // Deleted since the directory was read
+			}
+			if err != nil {
+				return nil, err
+			}
+			docID = entry.DocID
+			changed = true
+		}
+		current[name] = docID
+	}
+
+	// The index is only a cache, so failing to update it is not an error
+	if changed || len(current) != len(index) {
+		_ = s.writeIndex(current)
+	}
+
+	ids := make([]string, 0, len(current))
+	for _, docID := range current {
+		ids = append(ids, docID)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// readIndex returns the cached file name to doc ID mapping. A missing or
+// unreadable index yields an empty mapping, which List rebuilds.
+func (s *FileSchemaStore) readIndex() map[string]string {
+	index := make(map[string]string)
+	data, err := os.ReadFile(filepath.Join(s.dir, schemaIndexFile))
+	if err != nil {
+		return index
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return make(map[string]string)
+	}
+	return index
+}
+
+// writeIndex replaces the index file atomically via a temporary file and rename.
+func (s *FileSchemaStore) writeIndex(index map[string]string) error {
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, "index-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(s.dir, schemaIndexFile))
+}
+
+// path returns the file holding a document's schema.
+func (s *FileSchemaStore) path(docID string) string {
+	sum := sha256.Sum256([]byte(docID))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+// readSchemaEntry decodes a stored schema file.
+func readSchemaEntry(path string) (*IndexEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+
+	var entry IndexEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to parse schema %s: %w", filepath.Base(path), err)
+	}
+
+	return &entry, nil
+}
+
+// MemorySchemaStore keeps schemas in memory. It is safe for concurrent use.
+type MemorySchemaStore struct {
+	mu      sync.RWMutex
+	entries map[string]*IndexEntry
+}
+
+// NewMemorySchemaStore creates an empty in-memory schema store.
+func NewMemorySchemaStore() *MemorySchemaStore {
+	return &MemorySchemaStore{
+		entries: make(map[string]*IndexEntry),
+	}
+}
+
+// Save stores the entry.
+func (s *MemorySchemaStore) Save(ctx context.Context, entry *IndexEntry) error {
+	if entry == nil || entry.Schema == nil {
+		return errors.New("schema entry cannot be nil")
+	}
+	if entry.DocID == "" {
+		return errors.New("schema entry has no doc ID")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *entry
+	stored.ChunkIDs = append([]string(nil), entry.ChunkIDs...)
+	s.entries[entry.DocID] = &stored
+	return nil
+}
+
+// Load returns the entry for a document.
+func (s *MemorySchemaStore) Load(ctx context.Context, docID string) (*IndexEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.entries[docID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrSchemaNotFound, docID)
+	}
+	loaded := *entry
+	return &loaded, nil
+}
+
+// Delete removes the entry for a document.
+func (s *MemorySchemaStore) Delete(ctx context.Context, docID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, docID)
+	return nil
+}
+
+// List returns the stored doc IDs.
+func (s *MemorySchemaStore) List(ctx context.Context) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := make([]string, 0, len(s.entries))
+	for id := range s.entries {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
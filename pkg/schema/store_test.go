@@ -0,0 +1,159 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package schema
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func testSchemaEntry(docID string) *IndexEntry {
+	return &IndexEntry{
+		DocID: docID,
+		Schema: &DocumentSchema{
+			DocID:  docID,
+			Format: "pdf",
+			Title:  "Annual Report",
+			Sections: []Section{
+				{ID: "s1", Title: "Risk Factors", Type: "risk_factors", Level: 1, EndPos: 100},
+			},
+			Hierarchy:  &HierarchyTree{Root: &HierarchyNode{ID: "root", Title: "Annual Report"}, MaxDepth: 1},
+			Confidence: 0.9,
+		},
+		ChunkIDs: []string{"chunk-0", "chunk-1"},
+	}
+}
+
+func TestSchemaStores(t *testing.T) {
+	stores := map[string]func(t *testing.T) SchemaStore{
+		"file": func(t *testing.T) SchemaStore {
+			store, err := NewFileSchemaStore(filepath.Join(t.TempDir(), "schemas"))
+			if err != nil {
+				t.Fatalf("NewFileSchemaStore() error = %v", err)
+			}
+			return store
+		},
+		"memory": func(t *testing.T) SchemaStore {
+			return NewMemorySchemaStore()
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+
+			for _, docID := range []string{"./reports/b.pdf", "a.md"} {
+				if err := store.Save(ctx, testSchemaEntry(docID)); err != nil {
+					t.Fatalf("Save(%s) error = %v", docID, err)
+				}
+			}
+
+			loaded, err := store.Load(ctx, "./reports/b.pdf")
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if !reflect.DeepEqual(loaded, testSchemaEntry("./reports/b.pdf")) {
+				t.Errorf("Load() = %+v, want the saved entry", loaded)
+			}
+
+			ids, err := store.List(ctx)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if want := []string{"./reports/b.pdf", "a.md"}; !reflect.DeepEqual(ids, want) {
+				t.Errorf("List() = %v, want %v", ids, want)
+			}
+
+			if err := store.Delete(ctx, "a.md"); err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+			if err := store.Delete(ctx, "a.md"); err != nil {
+				t.Errorf("Delete() of a missing schema error = %v", err)
+			}
+			if _, err := store.Load(ctx, "a.md"); !errors.Is(err, ErrSchemaNotFound) {
+				t.Errorf("Load() after Delete() error = %v, want ErrSchemaNotFound", err)
+			}
+
+			if err := store.Save(ctx, &IndexEntry{DocID: "empty.md"}); err == nil {
+				t.Error("Save() without a schema should fail")
+			}
+		})
+	}
+}
+
+func TestFileSchemaStore_Persists(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	store, err := NewFileSchemaStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save(ctx, testSchemaEntry("report.pdf")); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new store over the same directory sees the saved schema
+	reopened, err := NewFileSchemaStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, err := reopened.Load(ctx, "report.pdf")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if entry.Schema.Sections[0].Type != "risk_factors" || len(entry.ChunkIDs) != 2 {
+		t.Errorf("Load() = %+v", entry)
+	}
+}
+
+func TestFileSchemaStore_ListIndex(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	store, err := NewFileSchemaStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, docID := range []string{"a.md", "b.md"} {
+		if err := store.Save(ctx, testSchemaEntry(docID)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := store.List(ctx); err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	// Indexed schemas are not decoded again
+	if err := os.WriteFile(store.path("a.md"), []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Schemas saved and deleted since are picked up, also by other stores
+	reopened, err := NewFileSchemaStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reopened.Save(ctx, testSchemaEntry("c.md")); err != nil {
+		t.Fatal(err)
+	}
+	if err := reopened.Delete(ctx, "b.md"); err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if want := []string{"a.md", "c.md"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("List() = %v, want %v", ids, want)
+	}
+}
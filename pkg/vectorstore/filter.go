@@ -0,0 +1,142 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package vectorstore
+
+// Filter is a typed metadata filter for searches, listings and deletes.
+// Build filters with Eq, In, Range, Exists, Not, And and Or; a nil Filter
+// matches every document.
+//
+// Filters follow Qdrant payload semantics: a condition on a list-valued
+// field matches when any element of the list matches, and a condition on a
+// missing field never matches.
+type Filter interface {
+	isFilter()
+}
+
+// EqFilter matches documents whose field equals Value. Values should be
+// strings, integers, floats or bools.
+type EqFilter struct {
+	Key   string
+	Value interface{}
+}
+
+// InFilter matches documents whose field equals any of Values.
+type InFilter struct {
+	Key    string
+	Values []interface{}
+}
+
+// Bounds are the limits of a numeric range. Nil bounds are open.
+type Bounds struct {
+	Gt, Gte, Lt, Lte *float64
+}
+
+// RangeFilter matches documents whose numeric field lies within Bounds.
+type RangeFilter struct {
+	Key string
+	Bounds
+}
+
+// ExistsFilter matches documents that have a non-null, non-empty value for Key.
+type ExistsFilter struct {
+	Key string
+}
+
+// NotFilter matches documents that do not match Filter.
+type NotFilter struct {
+	Filter Filter
+}
+
+// AndFilter matches documents that match every one of Filters.
+type AndFilter struct {
+	Filters []Filter
+}
+
+// OrFilter matches documents that match at least one of Filters.
+type OrFilter struct {
+	Filters []Filter
+}
+
+func (*EqFilter) isFilter()     {}
+func (*InFilter) isFilter()     {}
+func (*RangeFilter) isFilter()  {}
+func (*ExistsFilter) isFilter() {}
+func (*NotFilter) isFilter()    {}
+func (*AndFilter) isFilter()    {}
+func (*OrFilter) isFilter()     {}
+
+// Eq returns a filter matching documents whose field equals value.
+func Eq(key string, value interface{}) Filter {
+	return &EqFilter{Key: key, Value: value}
+}
+
+// In returns a filter matching documents whose field equals any of values.
+func In[T any](key string, values ...T) Filter {
+	options := make([]interface{}, len(values))
+	for i, value := range values {
+		options[i] = value
+	}
+	return &InFilter{Key: key, Values: options}
+}
+
+// Range returns a filter matching documents whose numeric field lies within bounds.
+func Range(key string, bounds Bounds) Filter {
+	return &RangeFilter{Key: key, Bounds: bounds}
+}
+
+// Bound returns a pointer to v, for use in Bounds.
+func Bound(v float64) *float64 {
+	return &v
+}
+
+// Exists returns a filter matching documents that have a value for key.
+func Exists(key string) Filter {
+	return &ExistsFilter{Key: key}
+}
+
+// Not returns a filter matching documents that do not match filter.
+func Not(filter Filter) Filter {
+	return &NotFilter{Filter: filter}
+}
+
+// And returns a filter matching documents that match every non-nil filter.
+// It returns nil if no filters are given, and the filter itself if only one is.
+func And(filters ...Filter) Filter {
+	filters = nonNil(filters)
+	switch len(filters) {
+	case 0:
+		return nil
+	case 1:
+		return filters[0]
+	default:
+		return &AndFilter{Filters: filters}
+	}
+}
+
+// Or returns a filter matching documents that match any non-nil filter.
+// It returns nil if no filters are given, and the filter itself if only one is.
+func Or(filters ...Filter) Filter {
+	filters = nonNil(filters)
+	switch len(filters) {
+	case 0:
+		return nil
+	case 1:
+		return filters[0]
+	default:
+		return &OrFilter{Filters: filters}
+	}
+}
+
+// nonNil returns the non-nil filters.
+func nonNil(filters []Filter) []Filter {
+	result := make([]Filter, 0, len(filters))
+	for _, filter := range filters {
+		if filter != nil {
+			result = append(result, filter)
+		}
+	}
+	return result
+}
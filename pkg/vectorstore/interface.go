@@ -5,7 +5,14 @@
 
 package vectorstore
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+// ErrCollectionNotFound is returned, possibly wrapped, when a named
+// collection does not exist.
+var ErrCollectionNotFound = errors.New("collection not found")
 
 // Document represents a document with its embedding and metadata stored in the vector store.
 type Document struct {
@@ -33,8 +40,7 @@ type SearchRequest struct {
 	// TopK is the number of results to return
 	TopK int
 
-	// Filter allows filtering results by metadata
-	// Format depends on vector store implementation
+	// Filter restricts results by metadata; nil matches every document
 	Filter Filter
 
 	// MinScore filters results below this similarity threshold
@@ -50,11 +56,6 @@ type SearchResponse struct {
 	TotalResults int
 }
 
-// Filter represents metadata filters for search queries.
-// The exact structure depends on the vector store implementation,
-// but generally supports equality, range, and logical operations.
-type Filter map[string]interface{}
-
 // InsertRequest contains documents to insert into the vector store.
 type InsertRequest struct {
 	// Documents to insert
@@ -141,7 +142,8 @@ type Store interface {
 	// ListCollections returns information about all collections.
 	ListCollections(ctx context.Context) ([]CollectionInfo, error)
 
-	// GetCollection returns information about a specific collection.
+	// GetCollection returns information about a specific collection, or an
+	// error wrapping ErrCollectionNotFound if it does not exist.
 	GetCollection(ctx context.Context, name string) (*CollectionInfo, error)
 
 	// Close closes the connection to the vector store.
@@ -0,0 +1,157 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package memory
+
+import (
+	"fmt"
+	"reflect"
+
+	"deep-thinking-agent/pkg/vectorstore"
+)
+
+// matchesFilter reports whether metadata satisfies filter, following Qdrant
+// payload semantics: a condition on a list-valued field matches when any
+// element of the list matches, and a condition on a missing field never
+// matches. A nil filter matches everything.
+func matchesFilter(metadata map[string]interface{}, filter vectorstore.Filter) bool {
+	switch f := filter.(type) {
+	case nil:
+		return true
+	case *vectorstore.EqFilter:
+		value, ok := metadata[f.Key]
+		return ok && anyElement(value, func(v interface{}) bool { return equalValues(v, f.Value) })
+	case *vectorstore.InFilter:
+		value, ok := metadata[f.Key]
+		return ok && anyElement(value, func(v interface{}) bool {
+			for _, option := range f.Values {
+				if equalValues(v, option) {
+					return true
+				}
+			}
+			return false
+		})
+	case *vectorstore.RangeFilter:
+		value, ok := metadata[f.Key]
+		return ok && anyElement(value, func(v interface{}) bool { return inRange(v, f.Bounds) })
+	case *vectorstore.ExistsFilter:
+		return exists(metadata[f.Key])
+	case *vectorstore.NotFilter:
+		return !matchesFilter(metadata, f.Filter)
+	case *vectorstore.AndFilter:
+		for _, sub := range f.Filters {
+			if !matchesFilter(metadata, sub) {
+				return false
+			}
+		}
+		return true
+	case *vectorstore.OrFilter:
+		for _, sub := range f.Filters {
+			if matchesFilter(metadata, sub) {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+// inRange checks a numeric value against range bounds.
+func inRange(value interface{}, bounds vectorstore.Bounds) bool {
+	v, ok := toFloat(value)
+	if !ok {
+		return false
+	}
+
+	return (bounds.Gt == nil || v > *bounds.Gt) &&
+		(bounds.Gte == nil || v >= *bounds.Gte) &&
+		(bounds.Lt == nil || v < *bounds.Lt) &&
+		(bounds.Lte == nil || v <= *bounds.Lte)
+}
+
+// exists reports whether a field value is present, non-nil and not an empty list.
+func exists(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+	if elements, ok := toSlice(value); ok {
+		return len(elements) > 0
+	}
+	return true
+}
+
+// anyElement applies match to value, or to each element if value is a list.
+func anyElement(value interface{}, match func(interface{}) bool) bool {
+	if elements, ok := toSlice(value); ok {
+		for _, element := range elements {
+			if match(element) {
+				return true
+			}
+		}
+		return false
+	}
+	return match(value)
+}
+
+// equalValues compares two metadata values, treating all numeric types as equal
+// when they hold the same number (JSON snapshots decode integers as float64).
+func equalValues(a, b interface{}) bool {
+	if af, ok := toFloat(a); ok {
+		bf, ok := toFloat(b)
+		return ok && af == bf
+	}
+	if as, ok := a.(string); ok {
+		bs, ok := b.(string)
+		return ok && as == bs
+	}
+	if ab, ok := a.(bool); ok {
+		bb, ok := b.(bool)
+		return ok && ab == bb
+	}
+	return fmt.Sprintf("%v", a) == fmt.Sprintf("%v", b)
+}
+
+// toFloat converts any numeric value to float64.
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// toSlice converts any slice or array (other than []byte) to []interface{}.
+func toSlice(value interface{}) ([]interface{}, bool) {
+	if value == nil {
+		return nil, false
+	}
+	if v, ok := value.([]interface{}); ok {
+		return v, true
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	if rv.Type().Elem().Kind() == reflect.Uint8 {
+		return nil, false
+	}
+
+	elements := make([]interface{}, rv.Len())
+	for i := range elements {
+		elements[i] = rv.Index(i).Interface()
+	}
+	return elements, true
+}
@@ -0,0 +1,550 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"deep-thinking-agent/pkg/vectorstore"
+
+	"github.com/google/uuid"
+)
+
+// Store implements the vectorstore.Store interface in process memory.
+// Search is brute-force cosine similarity, which is fast enough for local
+// development, tests, and small corpora. Contents can be snapshotted to a
+// JSON file with Save and restored with Load.
+type Store struct {
+	mu          sync.RWMutex
+	collections map[string]*collection
+	config      *vectorstore.Config
+}
+
+// collection holds documents in insertion order so List pagination is stable.
+type collection struct {
+	Dimension int                    `json:"dimension"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	Documents []vectorstore.Document `json:"documents"`
+
+	index map[string]int // document ID -> position in Documents
+}
+
+// snapshot is the on-disk format written by Save.
+type snapshot struct {
+	Version     int                    `json:"version"`
+	Collections map[string]*collection `json:"collections"`
+}
+
+const snapshotVersion = 1
+
+// NewStore creates a new in-memory vector store.
+// If config.Address is set, it is used as a snapshot file: existing contents
+// are loaded now and written back on Close.
+func NewStore(config *vectorstore.Config) (*Store, error) {
+	// Apply default config if not provided
+	if config == nil {
+		config = &vectorstore.Config{
+			Type:              "memory",
+			DefaultCollection: "documents",
+		}
+	}
+	if config.DefaultCollection == "" {
+		config.DefaultCollection = "documents"
+	}
+
+	store := &Store{
+		collections: make(map[string]*collection),
+		config:      config,
+	}
+
+	if config.Address != "" {
+		if err := store.Load(config.Address); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	return store, nil
+}
+
+// Insert adds documents to the vector store, replacing documents with the same ID.
+func (s *Store) Insert(ctx context.Context, req *vectorstore.InsertRequest) (*vectorstore.InsertResponse, error) {
+	if req == nil {
+		return nil, errors.New("insert request cannot be nil")
+	}
+	if len(req.Documents) == 0 {
+		return nil, errors.New("no documents to insert")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	collectionName := s.collectionName(req.CollectionName)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Ensure collection exists, create if needed
+	col, ok := s.collections[collectionName]
+	if !ok {
+		if len(req.Documents[0].Embedding) == 0 {
+			return nil, fmt.Errorf("failed to ensure collection: cannot determine vector dimension: no documents with embeddings")
+		}
+		col = newCollection(len(req.Documents[0].Embedding), nil)
+		s.collections[collectionName] = col
+	}
+
+	insertedIDs := make([]string, 0, len(req.Documents))
+	insertErrors := []vectorstore.InsertError{}
+
+	for _, doc := range req.Documents {
+		// Generate ID if not provided
+		if doc.ID == "" {
+			doc.ID = uuid.New().String()
+		}
+
+		if len(doc.Embedding) != col.Dimension {
+			insertErrors = append(insertErrors, vectorstore.InsertError{
+				DocumentID: doc.ID,
+				Error:      fmt.Errorf("vector dimension %d does not match collection dimension %d", len(doc.Embedding), col.Dimension),
+			})
+			continue
+		}
+
+		col.put(copyDocument(doc))
+		insertedIDs = append(insertedIDs, doc.ID)
+	}
+
+	return &vectorstore.InsertResponse{
+		InsertedIDs: insertedIDs,
+		Errors:      insertErrors,
+	}, nil
+}
+
+// Search performs a brute-force cosine similarity search.
+func (s *Store) Search(ctx context.Context, req *vectorstore.SearchRequest) (*vectorstore.SearchResponse, error) {
+	if req == nil {
+		return nil, errors.New("search request cannot be nil")
+	}
+	if len(req.Vector) == 0 {
+		return nil, errors.New("search vector cannot be empty")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	collectionName := s.config.DefaultCollection
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	col, err := s.getCollection(collectionName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+	if len(req.Vector) != col.Dimension {
+		return nil, fmt.Errorf("failed to search: query dimension %d does not match collection dimension %d", len(req.Vector), col.Dimension)
+	}
+
+	documents := make([]vectorstore.Document, 0)
+	for _, doc := range col.Documents {
+		if !matchesFilter(doc.Metadata, req.Filter) {
+			continue
+		}
+
+		score := cosineSimilarity(req.Vector, doc.Embedding)
+		if score < req.MinScore {
+			continue
+		}
+
+		// Search results omit the embedding, matching Qdrant
+		result := copyDocument(doc)
+		result.Embedding = nil
+		result.Score = score
+		documents = append(documents, result)
+	}
+
+	// Stable sort keeps insertion order for ties
+	sort.SliceStable(documents, func(i, j int) bool {
+		return documents[i].Score > documents[j].Score
+	})
+
+	total := len(documents)
+	if req.TopK > 0 && len(documents) > req.TopK {
+		documents = documents[:req.TopK]
+	}
+
+	return &vectorstore.SearchResponse{
+		Documents:    documents,
+		TotalResults: total,
+	}, nil
+}
+
+// Delete removes documents by ID or by metadata filter.
+func (s *Store) Delete(ctx context.Context, req *vectorstore.DeleteRequest) (*vectorstore.DeleteResponse, error) {
+	if req == nil {
+		return nil, errors.New("delete request cannot be nil")
+	}
+	if len(req.IDs) == 0 && req.Filter == nil {
+		return nil, errors.New("either IDs or Filter must be provided")
+	}
+
+	collectionName := s.collectionName(req.CollectionName)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	col, err := s.getCollection(collectionName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete documents: %w", err)
+	}
+
+	var remove func(doc vectorstore.Document) bool
+	if len(req.IDs) > 0 {
+		ids := make(map[string]bool, len(req.IDs))
+		for _, id := range req.IDs {
+			ids[id] = true
+		}
+		remove = func(doc vectorstore.Document) bool { return ids[doc.ID] }
+	} else {
+		remove = func(doc vectorstore.Document) bool { return matchesFilter(doc.Metadata, req.Filter) }
+	}
+
+	deleted := col.removeWhere(remove)
+
+	return &vectorstore.DeleteResponse{
+		DeletedCount: deleted,
+	}, nil
+}
+
+// Get retrieves specific documents by ID. Unknown IDs are skipped.
+func (s *Store) Get(ctx context.Context, collectionName string, ids []string) ([]vectorstore.Document, error) {
+	collectionName = s.collectionName(collectionName)
+	if len(ids) == 0 {
+		return []vectorstore.Document{}, nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	col, err := s.getCollection(collectionName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get documents: %w", err)
+	}
+
+	documents := make([]vectorstore.Document, 0, len(ids))
+	for _, id := range ids {
+		if pos, ok := col.index[id]; ok {
+			documents = append(documents, copyDocument(col.Documents[pos]))
+		}
+	}
+
+	return documents, nil
+}
+
+// List retrieves documents in insertion order with optional filtering and pagination.
+func (s *Store) List(ctx context.Context, collectionName string, filter vectorstore.Filter, limit int, offset int) ([]vectorstore.Document, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	collectionName = s.collectionName(collectionName)
+	if limit <= 0 {
+		limit = 100 // Default limit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	col, err := s.getCollection(collectionName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+
+	documents := make([]vectorstore.Document, 0, limit)
+	skipped := 0
+	for _, doc := range col.Documents {
+		if !matchesFilter(doc.Metadata, filter) {
+			continue
+		}
+		if skipped < offset {
+			skipped++
+			continue
+		}
+		documents = append(documents, copyDocument(doc))
+		if len(documents) == limit {
+			break
+		}
+	}
+
+	return documents, nil
+}
+
+// CreateCollection creates a new collection with specified dimensions.
+func (s *Store) CreateCollection(ctx context.Context, name string, dimension int, metadata map[string]interface{}) error {
+	if name == "" {
+		return errors.New("collection name is required")
+	}
+	if dimension <= 0 {
+		return errors.New("dimension must be positive")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.collections[name]; ok {
+		return fmt.Errorf("failed to create collection: collection %s already exists", name)
+	}
+
+	s.collections[name] = newCollection(dimension, metadata)
+	return nil
+}
+
+// DeleteCollection removes an entire collection.
+func (s *Store) DeleteCollection(ctx context.Context, name string) error {
+	if name == "" {
+		return errors.New("collection name is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.getCollection(name); err != nil {
+		return fmt.Errorf("failed to delete collection: %w", err)
+	}
+
+	delete(s.collections, name)
+	return nil
+}
+
+// ListCollections returns information about all collections, sorted by name.
+func (s *Store) ListCollections(ctx context.Context) ([]vectorstore.CollectionInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	collections := make([]vectorstore.CollectionInfo, 0, len(s.collections))
+	for name, col := range s.collections {
+		collections = append(collections, col.info(name))
+	}
+
+	sort.Slice(collections, func(i, j int) bool {
+		return collections[i].Name < collections[j].Name
+	})
+
+	return collections, nil
+}
+
+// GetCollection returns information about a specific collection.
+func (s *Store) GetCollection(ctx context.Context, name string) (*vectorstore.CollectionInfo, error) {
+	if name == "" {
+		return nil, errors.New("collection name is required")
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	col, err := s.getCollection(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection: %w", err)
+	}
+
+	info := col.info(name)
+	return &info, nil
+}
+
+// Save writes all collections to a JSON snapshot file.
+// The file is written atomically via a temporary file and rename.
+func (s *Store) Save(path string) error {
+	if path == "" {
+		return errors.New("snapshot path is required")
+	}
+
+	s.mu.RLock()
+	data, err := json.Marshal(snapshot{
+		Version:     snapshotVersion,
+		Collections: s.collections,
+	})
+	s.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create snapshot directory: %w", err)
+		}
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// Load replaces the store contents with a snapshot written by Save.
+// Numeric metadata values are restored as float64.
+func (s *Store) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+	if snap.Version != snapshotVersion {
+		return fmt.Errorf("unsupported snapshot version: %d", snap.Version)
+	}
+
+	collections := make(map[string]*collection, len(snap.Collections))
+	for name, col := range snap.Collections {
+		if col == nil {
+			continue
+		}
+		col.reindex()
+		collections[name] = col
+	}
+
+	s.mu.Lock()
+	s.collections = collections
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Close writes the snapshot file if one is configured.
+func (s *Store) Close() error {
+	if s.config.Address != "" {
+		return s.Save(s.config.Address)
+	}
+	return nil
+}
+
+// Name returns the vector store implementation name.
+func (s *Store) Name() string {
+	return "memory"
+}
+
+// collectionName returns name, or the default collection if name is empty.
+func (s *Store) collectionName(name string) string {
+	if name == "" {
+		return s.config.DefaultCollection
+	}
+	return name
+}
+
+// getCollection returns the named collection. Callers must hold s.mu.
+func (s *Store) getCollection(name string) (*collection, error) {
+	col, ok := s.collections[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", vectorstore.ErrCollectionNotFound, name)
+	}
+	return col, nil
+}
+
+func newCollection(dimension int, metadata map[string]interface{}) *collection {
+	return &collection{
+		Dimension: dimension,
+		Metadata:  metadata,
+		Documents: []vectorstore.Document{},
+		index:     make(map[string]int),
+	}
+}
+
+// put inserts a document or replaces an existing one with the same ID in place.
+func (c *collection) put(doc vectorstore.Document) {
+	if pos, ok := c.index[doc.ID]; ok {
+		c.Documents[pos] = doc
+		return
+	}
+	c.index[doc.ID] = len(c.Documents)
+	c.Documents = append(c.Documents, doc)
+}
+
+// removeWhere deletes matching documents and returns how many were removed.
+func (c *collection) removeWhere(match func(vectorstore.Document) bool) int {
+	kept := c.Documents[:0]
+	for _, doc := range c.Documents {
+		if !match(doc) {
+			kept = append(kept, doc)
+		}
+	}
+
+	deleted := len(c.Documents) - len(kept)
+	c.Documents = kept
+	c.reindex()
+	return deleted
+}
+
+// reindex rebuilds the ID index from Documents.
+func (c *collection) reindex() {
+	c.index = make(map[string]int, len(c.Documents))
+	for i, doc := range c.Documents {
+		c.index[doc.ID] = i
+	}
+}
+
+func (c *collection) info(name string) vectorstore.CollectionInfo {
+	metadata := make(map[string]interface{}, len(c.Metadata))
+	for k, v := range c.Metadata {
+		metadata[k] = v
+	}
+
+	return vectorstore.CollectionInfo{
+		Name:            name,
+		VectorDimension: c.Dimension,
+		DocumentCount:   len(c.Documents),
+		Metadata:        metadata,
+	}
+}
+
+// copyDocument returns a copy that does not share the embedding or metadata map.
+func copyDocument(doc vectorstore.Document) vectorstore.Document {
+	if doc.Embedding != nil {
+		doc.Embedding = append([]float32(nil), doc.Embedding...)
+	}
+
+	metadata := make(map[string]interface{}, len(doc.Metadata))
+	for k, v := range doc.Metadata {
+		metadata[k] = v
+	}
+	doc.Metadata = metadata
+
+	return doc
+}
+
+// cosineSimilarity returns the cosine of the angle between a and b,
+// or 0 if either vector has zero magnitude.
+func cosineSimilarity(a, b []float32) float32 {
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return float32(dot / (math.Sqrt(normA) * math.Sqrt(normB)))
+}
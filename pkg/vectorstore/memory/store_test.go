@@ -0,0 +1,287 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package memory
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"deep-thinking-agent/pkg/vectorstore"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	store, err := NewStore(nil)
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+
+	docs := []vectorstore.Document{
+		{ID: "a", Content: "alpha", Embedding: []float32{1, 0, 0}, Metadata: map[string]interface{}{"doc_id": "d1", "page": 1, "tags": []string{"intro", "risk"}}},
+		{ID: "b", Content: "beta", Embedding: []float32{0.9, 0.1, 0}, Metadata: map[string]interface{}{"doc_id": "d1", "page": 5, "tags": []string{"finance"}}},
+		{ID: "c", Content: "gamma", Embedding: []float32{0, 1, 0}, Metadata: map[string]interface{}{"doc_id": "d2", "page": 3}},
+	}
+
+	resp, err := store.Insert(context.Background(), &vectorstore.InsertRequest{Documents: docs})
+	if err != nil {
+		t.Fatalf("Insert() error = %v", err)
+	}
+	if len(resp.InsertedIDs) != 3 {
+		t.Fatalf("Insert() inserted %d documents, want 3", len(resp.InsertedIDs))
+	}
+
+	return store
+}
+
+func TestStore_Search(t *testing.T) {
+	store := newTestStore(t)
+
+	resp, err := store.Search(context.Background(), &vectorstore.SearchRequest{
+		Vector: []float32{1, 0, 0},
+		TopK:   2,
+	})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	if len(resp.Documents) != 2 {
+		t.Fatalf("Search() returned %d documents, want 2", len(resp.Documents))
+	}
+	if resp.TotalResults != 3 {
+		t.Errorf("Search() TotalResults = %d, want 3", resp.TotalResults)
+	}
+	if resp.Documents[0].ID != "a" || resp.Documents[1].ID != "b" {
+		t.Errorf("Search() order = %s, %s, want a, b", resp.Documents[0].ID, resp.Documents[1].ID)
+	}
+	if resp.Documents[0].Score < 0.999 {
+		t.Errorf("Search() top score = %f, want ~1", resp.Documents[0].Score)
+	}
+
+	resp, err = store.Search(context.Background(), &vectorstore.SearchRequest{
+		Vector:   []float32{1, 0, 0},
+		TopK:     10,
+		MinScore: 0.5,
+	})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(resp.Documents) != 2 {
+		t.Errorf("Search() with MinScore returned %d documents, want 2", len(resp.Documents))
+	}
+}
+
+func TestStore_Cancelled(t *testing.T) {
+	store := newTestStore(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := store.Search(ctx, &vectorstore.SearchRequest{Vector: []float32{1, 0, 0}}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Search() error = %v, want context canceled", err)
+	}
+	if _, err := store.List(ctx, "", nil, 10, 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("List() error = %v, want context canceled", err)
+	}
+}
+
+func TestStore_SearchFilter(t *testing.T) {
+	store := newTestStore(t)
+
+	tests := []struct {
+		name   string
+		filter vectorstore.Filter
+		want   []string
+	}{
+		{name: "equality", filter: vectorstore.Eq("doc_id", "d1"), want: []string{"a", "b"}},
+		{name: "any-of", filter: vectorstore.In("doc_id", "d2", "d3"), want: []string{"c"}},
+		{name: "list field", filter: vectorstore.Eq("tags", "risk"), want: []string{"a"}},
+		{name: "list field any-of", filter: vectorstore.In("tags", "finance", "risk"), want: []string{"a", "b"}},
+		{name: "range", filter: vectorstore.Range("page", vectorstore.Bounds{Gte: vectorstore.Bound(3)}), want: []string{"b", "c"}},
+		{name: "combined", filter: vectorstore.And(vectorstore.Eq("doc_id", "d1"), vectorstore.Range("page", vectorstore.Bounds{Lt: vectorstore.Bound(2.5)})), want: []string{"a"}},
+		{name: "or", filter: vectorstore.Or(vectorstore.Eq("doc_id", "d2"), vectorstore.Eq("tags", "finance")), want: []string{"b", "c"}},
+		{name: "not", filter: vectorstore.Not(vectorstore.Eq("doc_id", "d1")), want: []string{"c"}},
+		{name: "exists", filter: vectorstore.Exists("tags"), want: []string{"a", "b"}},
+		{name: "missing field", filter: vectorstore.Eq("section", "intro"), want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := store.Search(context.Background(), &vectorstore.SearchRequest{
+				Vector: []float32{1, 1, 0},
+				TopK:   10,
+				Filter: tt.filter,
+			})
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+
+			got := make(map[string]bool)
+			for _, doc := range resp.Documents {
+				got[doc.ID] = true
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Search() returned %v, want %v", got, tt.want)
+			}
+			for _, id := range tt.want {
+				if !got[id] {
+					t.Errorf("Search() missing document %s", id)
+				}
+			}
+		})
+	}
+}
+
+func TestStore_InsertUpsert(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	resp, err := store.Insert(ctx, &vectorstore.InsertRequest{Documents: []vectorstore.Document{
+		{ID: "a", Content: "alpha v2", Embedding: []float32{1, 0, 0}},
+		{ID: "bad", Content: "wrong size", Embedding: []float32{1, 0}},
+	}})
+	if err != nil {
+		t.Fatalf("Insert() error = %v", err)
+	}
+	if len(resp.Errors) != 1 || resp.Errors[0].DocumentID != "bad" {
+		t.Errorf("Insert() errors = %v, want dimension error for bad", resp.Errors)
+	}
+
+	docs, err := store.Get(ctx, "", []string{"a", "missing"})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if len(docs) != 1 || docs[0].Content != "alpha v2" {
+		t.Errorf("Get() = %v, want replaced document a", docs)
+	}
+
+	info, err := store.GetCollection(ctx, "documents")
+	if err != nil {
+		t.Fatalf("GetCollection() error = %v", err)
+	}
+	if info.DocumentCount != 3 || info.VectorDimension != 3 {
+		t.Errorf("GetCollection() = %+v, want 3 documents of dimension 3", info)
+	}
+
+	if _, err := store.GetCollection(ctx, "missing"); !errors.Is(err, vectorstore.ErrCollectionNotFound) {
+		t.Errorf("GetCollection() of a missing collection error = %v, want ErrCollectionNotFound", err)
+	}
+}
+
+func TestStore_List(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	docs, err := store.List(ctx, "", nil, 2, 1)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(docs) != 2 || docs[0].ID != "b" || docs[1].ID != "c" {
+		t.Errorf("List() = %v, want b, c", docs)
+	}
+
+	docs, err = store.List(ctx, "", vectorstore.Eq("doc_id", "d1"), 10, 1)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(docs) != 1 || docs[0].ID != "b" {
+		t.Errorf("List() with filter = %v, want b", docs)
+	}
+}
+
+func TestStore_Delete(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	if _, err := store.Delete(ctx, &vectorstore.DeleteRequest{}); err == nil {
+		t.Error("Delete() expected error without IDs or filter")
+	}
+
+	resp, err := store.Delete(ctx, &vectorstore.DeleteRequest{IDs: []string{"a", "missing"}})
+	if err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if resp.DeletedCount != 1 {
+		t.Errorf("Delete() by ID DeletedCount = %d, want 1", resp.DeletedCount)
+	}
+
+	resp, err = store.Delete(ctx, &vectorstore.DeleteRequest{Filter: vectorstore.Eq("doc_id", "d2")})
+	if err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if resp.DeletedCount != 1 {
+		t.Errorf("Delete() by filter DeletedCount = %d, want 1", resp.DeletedCount)
+	}
+
+	docs, _ := store.List(ctx, "", nil, 0, 0)
+	if len(docs) != 1 || docs[0].ID != "b" {
+		t.Errorf("List() after delete = %v, want b", docs)
+	}
+}
+
+func TestStore_Collections(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	if err := store.CreateCollection(ctx, "archive", 4, nil); err != nil {
+		t.Fatalf("CreateCollection() error = %v", err)
+	}
+	if err := store.CreateCollection(ctx, "archive", 4, nil); err == nil {
+		t.Error("CreateCollection() expected error for existing collection")
+	}
+
+	collections, err := store.ListCollections(ctx)
+	if err != nil {
+		t.Fatalf("ListCollections() error = %v", err)
+	}
+	if len(collections) != 2 || collections[0].Name != "archive" || collections[1].Name != "documents" {
+		t.Errorf("ListCollections() = %v, want archive, documents", collections)
+	}
+
+	if err := store.DeleteCollection(ctx, "archive"); err != nil {
+		t.Fatalf("DeleteCollection() error = %v", err)
+	}
+	if err := store.DeleteCollection(ctx, "archive"); err == nil {
+		t.Error("DeleteCollection() expected error for missing collection")
+	}
+}
+
+func TestStore_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	ctx := context.Background()
+
+	store, err := NewStore(&vectorstore.Config{Address: path})
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+	if _, err := store.Insert(ctx, &vectorstore.InsertRequest{Documents: []vectorstore.Document{
+		{ID: "a", Content: "alpha", Embedding: []float32{1, 0}, Metadata: map[string]interface{}{"page": 2, "tags": []string{"x"}}},
+	}}); err != nil {
+		t.Fatalf("Insert() error = %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	restored, err := NewStore(&vectorstore.Config{Address: path})
+	if err != nil {
+		t.Fatalf("NewStore() restore error = %v", err)
+	}
+
+	resp, err := restored.Search(ctx, &vectorstore.SearchRequest{
+		Vector: []float32{1, 0},
+		TopK:   1,
+		Filter: vectorstore.And(vectorstore.Eq("page", 2), vectorstore.Eq("tags", "x")),
+	})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(resp.Documents) != 1 || resp.Documents[0].Content != "alpha" {
+		t.Errorf("Search() after Load = %v, want document a", resp.Documents)
+	}
+}
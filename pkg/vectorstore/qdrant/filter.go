@@ -0,0 +1,167 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package qdrant
+
+import (
+	"fmt"
+
+	"deep-thinking-agent/pkg/vectorstore"
+
+	pb "github.com/qdrant/go-client/qdrant"
+)
+
+// convertToQdrantFilter translates a typed filter into a Qdrant filter.
+// And, Or and Not map to must, should and must_not clauses, nesting as needed.
+func convertToQdrantFilter(filter vectorstore.Filter) (*pb.Filter, error) {
+	switch f := filter.(type) {
+	case *vectorstore.AndFilter:
+		conditions, err := convertConditions(f.Filters)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.Filter{Must: conditions}, nil
+	case *vectorstore.OrFilter:
+		conditions, err := convertConditions(f.Filters)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.Filter{Should: conditions}, nil
+	case *vectorstore.NotFilter:
+		condition, err := convertCondition(f.Filter)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.Filter{MustNot: []*pb.Condition{condition}}, nil
+	default:
+		condition, err := convertCondition(filter)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.Filter{Must: []*pb.Condition{condition}}, nil
+	}
+}
+
+// convertConditions translates each filter into a condition.
+func convertConditions(filters []vectorstore.Filter) ([]*pb.Condition, error) {
+	conditions := make([]*pb.Condition, 0, len(filters))
+	for _, filter := range filters {
+		condition, err := convertCondition(filter)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
+
+// convertCondition translates a filter into a single condition.
+func convertCondition(filter vectorstore.Filter) (*pb.Condition, error) {
+	switch f := filter.(type) {
+	case *vectorstore.EqFilter:
+		return matchCondition(f.Key, f.Value)
+	case *vectorstore.InFilter:
+		return matchAnyCondition(f.Key, f.Values)
+	case *vectorstore.RangeFilter:
+		return pb.NewRange(f.Key, &pb.Range{Gt: f.Gt, Gte: f.Gte, Lt: f.Lt, Lte: f.Lte}), nil
+	case *vectorstore.ExistsFilter:
+		// is_empty matches missing, null and empty list values
+		return pb.NewFilterAsCondition(&pb.Filter{
+			MustNot: []*pb.Condition{pb.NewIsEmpty(f.Key)},
+		}), nil
+	case *vectorstore.AndFilter, *vectorstore.OrFilter, *vectorstore.NotFilter:
+		nested, err := convertToQdrantFilter(filter)
+		if err != nil {
+			return nil, err
+		}
+		return pb.NewFilterAsCondition(nested), nil
+	case nil:
+		return nil, fmt.Errorf("filter condition cannot be nil")
+	default:
+		return nil, fmt.Errorf("unsupported filter type %T", filter)
+	}
+}
+
+// matchCondition matches a field against a single value. Qdrant has no exact
+// match for floats, so they become a closed range on the value.
+func matchCondition(key string, value interface{}) (*pb.Condition, error) {
+	if s, ok := value.(string); ok {
+		return pb.NewMatchKeyword(key, s), nil
+	}
+	if b, ok := value.(bool); ok {
+		return pb.NewMatchBool(key, b), nil
+	}
+	if i, ok := toInt(value); ok {
+		return pb.NewMatchInt(key, i), nil
+	}
+	if f, ok := toFloat(value); ok {
+		return pb.NewRange(key, &pb.Range{Gte: &f, Lte: &f}), nil
+	}
+	return nil, fmt.Errorf("unsupported filter value %v (%T) for %q", value, value, key)
+}
+
+// matchAnyCondition matches a field against any of values, using a single
+// keywords or integers match when all values share that type.
+func matchAnyCondition(key string, values []interface{}) (*pb.Condition, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("filter on %q has no values", key)
+	}
+
+	keywords := make([]string, 0, len(values))
+	ints := make([]int64, 0, len(values))
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			keywords = append(keywords, s)
+		} else if i, ok := toInt(value); ok {
+			ints = append(ints, i)
+		}
+	}
+
+	switch len(values) {
+	case len(keywords):
+		return pb.NewMatchKeywords(key, keywords...), nil
+	case len(ints):
+		return pb.NewMatchInts(key, ints...), nil
+	}
+
+	// Mixed types match each value separately
+	conditions := make([]*pb.Condition, 0, len(values))
+	for _, value := range values {
+		condition, err := matchCondition(key, value)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+	return pb.NewFilterAsCondition(&pb.Filter{Should: conditions}), nil
+}
+
+// toInt converts integer values to int64.
+func toInt(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint32:
+		return int64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// toFloat converts float values to float64.
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
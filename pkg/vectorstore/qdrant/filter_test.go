@@ -0,0 +1,102 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package qdrant
+
+import (
+	"testing"
+
+	"deep-thinking-agent/pkg/vectorstore"
+
+	pb "github.com/qdrant/go-client/qdrant"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestConvertToQdrantFilter(t *testing.T) {
+	three := 3.0
+	half := 0.5
+
+	tests := []struct {
+		name   string
+		filter vectorstore.Filter
+		want   *pb.Filter
+	}{
+		{
+			name:   "keyword",
+			filter: vectorstore.Eq("doc_id", "d1"),
+			want:   &pb.Filter{Must: []*pb.Condition{pb.NewMatchKeyword("doc_id", "d1")}},
+		},
+		{
+			name:   "integer",
+			filter: vectorstore.Eq("chunk_index", 4),
+			want:   &pb.Filter{Must: []*pb.Condition{pb.NewMatchInt("chunk_index", 4)}},
+		},
+		{
+			name:   "float",
+			filter: vectorstore.Eq("weight", 0.5),
+			want:   &pb.Filter{Must: []*pb.Condition{pb.NewRange("weight", &pb.Range{Gte: &half, Lte: &half})}},
+		},
+		{
+			name:   "any keyword",
+			filter: vectorstore.In("section_type", "risk_factors", "results"),
+			want:   &pb.Filter{Must: []*pb.Condition{pb.NewMatchKeywords("section_type", "risk_factors", "results")}},
+		},
+		{
+			name:   "any mixed",
+			filter: vectorstore.In[interface{}]("year", "2024", 2025),
+			want: &pb.Filter{Must: []*pb.Condition{pb.NewFilterAsCondition(&pb.Filter{Should: []*pb.Condition{
+				pb.NewMatchKeyword("year", "2024"),
+				pb.NewMatchInt("year", 2025),
+			}})}},
+		},
+		{
+			name:   "range",
+			filter: vectorstore.Range("page", vectorstore.Bounds{Gte: vectorstore.Bound(3)}),
+			want:   &pb.Filter{Must: []*pb.Condition{pb.NewRange("page", &pb.Range{Gte: &three})}},
+		},
+		{
+			name: "nested",
+			filter: vectorstore.And(
+				vectorstore.Exists("semantic_tags"),
+				vectorstore.Or(vectorstore.Eq("doc_id", "d1"), vectorstore.Not(vectorstore.Eq("draft", true))),
+			),
+			want: &pb.Filter{Must: []*pb.Condition{
+				pb.NewFilterAsCondition(&pb.Filter{MustNot: []*pb.Condition{pb.NewIsEmpty("semantic_tags")}}),
+				pb.NewFilterAsCondition(&pb.Filter{Should: []*pb.Condition{
+					pb.NewMatchKeyword("doc_id", "d1"),
+					pb.NewFilterAsCondition(&pb.Filter{MustNot: []*pb.Condition{pb.NewMatchBool("draft", true)}}),
+				}}),
+			}},
+		},
+		{
+			name:   "top-level not",
+			filter: vectorstore.Not(vectorstore.Eq("doc_id", "d1")),
+			want:   &pb.Filter{MustNot: []*pb.Condition{pb.NewMatchKeyword("doc_id", "d1")}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := convertToQdrantFilter(tt.filter)
+			if err != nil {
+				t.Fatalf("convertToQdrantFilter() error = %v", err)
+			}
+			if !proto.Equal(got, tt.want) {
+				t.Errorf("convertToQdrantFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertToQdrantFilter_Errors(t *testing.T) {
+	for name, filter := range map[string]vectorstore.Filter{
+		"unsupported value": vectorstore.Eq("meta", map[string]string{"a": "b"}),
+		"empty any-of":      vectorstore.In[string]("doc_id"),
+	} {
+		if _, err := convertToQdrantFilter(filter); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -9,13 +9,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 
 	"deep-thinking-agent/pkg/vectorstore"
 
 	"github.com/google/uuid"
 	pb "github.com/qdrant/go-client/qdrant"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
 // Store implements the vectorstore.Store interface for Qdrant.
@@ -24,6 +27,12 @@ type Store struct {
 	collections pb.CollectionsClient
 	conn        *grpc.ClientConn
 	config      *vectorstore.Config
+
+	// known caches the names of collections known to exist, so inserts
+	// skip the lookup. ensureMu serializes creating collections so
+	// concurrent inserts into a new collection don't race to create it.
+	known    sync.Map
+	ensureMu sync.Mutex
 }
 
 // NewStore creates a new Qdrant vector store instance.
@@ -157,8 +166,12 @@ func (s *Store) Search(ctx context.Context, req *vectorstore.SearchRequest) (*ve
 	}
 
 	// Add filter if provided
-	if req.Filter != nil && len(req.Filter) > 0 {
-		searchReq.Filter = convertToQdrantFilter(req.Filter)
+	if req.Filter != nil {
+		filter, err := convertToQdrantFilter(req.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search: %w", err)
+		}
+		searchReq.Filter = filter
 	}
 
 	// Execute search
@@ -226,9 +239,13 @@ func (s *Store) Delete(ctx context.Context, req *vectorstore.DeleteRequest) (*ve
 		}
 	} else if req.Filter != nil {
 		// Delete by filter
+		filter, err := convertToQdrantFilter(req.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete documents: %w", err)
+		}
 		pointsSelector = &pb.PointsSelector{
 			PointsSelectorOneOf: &pb.PointsSelector_Filter{
-				Filter: convertToQdrantFilter(req.Filter),
+				Filter: filter,
 			},
 		}
 	} else {
@@ -304,6 +321,8 @@ func (s *Store) Get(ctx context.Context, collectionName string, ids []string) ([
 }
 
 // List retrieves documents from a collection with optional filtering and pagination.
+// Qdrant paginates by point ID, so a numeric offset is applied by scrolling
+// past that many points without fetching their payloads or vectors.
 func (s *Store) List(ctx context.Context, collectionName string, filter vectorstore.Filter, limit int, offset int) ([]vectorstore.Document, error) {
 	if collectionName == "" {
 		collectionName = s.config.DefaultCollection
@@ -312,21 +331,48 @@ func (s *Store) List(ctx context.Context, collectionName string, filter vectorst
 		limit = 100 // Default limit
 	}
 
+	var qdrantFilter *pb.Filter
+	if filter != nil {
+		converted, err := convertToQdrantFilter(filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list documents: %w", err)
+		}
+		qdrantFilter = converted
+	}
+
+	// Skip offset points by ID
+	var pageOffset *pb.PointId
+	for skipped := 0; skipped < offset; {
+		skipLimit := uint32(offset - skipped)
+		resp, err := s.client.Scroll(ctx, &pb.ScrollPoints{
+			CollectionName: collectionName,
+			Filter:         qdrantFilter,
+			Offset:         pageOffset,
+			Limit:          &skipLimit,
+			WithPayload:    &pb.WithPayloadSelector{SelectorOptions: &pb.WithPayloadSelector_Enable{Enable: false}},
+			WithVectors:    &pb.WithVectorsSelector{SelectorOptions: &pb.WithVectorsSelector_Enable{Enable: false}},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list documents: %w", err)
+		}
+		if resp.NextPageOffset == nil {
+			return []vectorstore.Document{}, nil // Fewer than offset points
+		}
+		skipped += len(resp.Result)
+		pageOffset = resp.NextPageOffset
+	}
+
 	// Build scroll request
 	limitVal := uint32(limit)
 	scrollReq := &pb.ScrollPoints{
 		CollectionName: collectionName,
+		Filter:         qdrantFilter,
 		Limit:          &limitVal,
-		Offset:         nil, // Qdrant uses ID-based pagination, not numeric offset
+		Offset:         pageOffset,
 		WithPayload:    &pb.WithPayloadSelector{SelectorOptions: &pb.WithPayloadSelector_Enable{Enable: true}},
 		WithVectors:    &pb.WithVectorsSelector{SelectorOptions: &pb.WithVectorsSelector_Enable{Enable: true}},
 	}
 
-	// Add filter if provided
-	if filter != nil {
-		scrollReq.Filter = convertToQdrantFilter(filter)
-	}
-
 	// Execute scroll (list) operation
 	resp, err := s.client.Scroll(ctx, scrollReq)
 	if err != nil {
@@ -366,11 +412,16 @@ func (s *Store) List(ctx context.Context, collectionName string, filter vectorst
 }
 
 // ensureCollection checks if collection exists and creates it if needed.
+// Creation by another client between the check and the create is treated
+// as success.
 func (s *Store) ensureCollection(ctx context.Context, name string, docs []vectorstore.Document) error {
+	if _, ok := s.known.Load(name); ok {
+		return nil
+	}
+
 	// Check if collection exists
-	_, err := s.collections.Get(ctx, &pb.GetCollectionInfoRequest{CollectionName: name})
-	if err == nil {
-		return nil // Collection exists
+	if s.collectionExists(ctx, name) {
+		return nil
 	}
 
 	// Collection doesn't exist, create it
@@ -378,8 +429,37 @@ func (s *Store) ensureCollection(ctx context.Context, name string, docs []vector
 		return fmt.Errorf("cannot determine vector dimension: no documents with embeddings")
 	}
 
+	s.ensureMu.Lock()
+	defer s.ensureMu.Unlock()
+
+	// Another insert may have created it while we waited for the lock
+	if _, ok := s.known.Load(name); ok {
+		return nil
+	}
+
 	dimension := len(docs[0].Embedding)
-	return s.CreateCollection(ctx, name, dimension, nil)
+	err := s.CreateCollection(ctx, name, dimension, nil)
+	if err == nil || status.Code(err) == codes.AlreadyExists {
+		s.known.Store(name, struct{}{})
+		return nil
+	}
+
+	// Qdrant does not always report a lost race as AlreadyExists; if the
+	// collection is there now, someone else created it.
+	if s.collectionExists(ctx, name) {
+		return nil
+	}
+	return err
+}
+
+// collectionExists looks the collection up on the server, caching it as
+// known if it exists.
+func (s *Store) collectionExists(ctx context.Context, name string) bool {
+	if _, err := s.collections.Get(ctx, &pb.GetCollectionInfoRequest{CollectionName: name}); err != nil {
+		return false
+	}
+	s.known.Store(name, struct{}{})
+	return true
 }
 
 // CreateCollection creates a new collection/index with specified dimensions.
@@ -416,6 +496,7 @@ func (s *Store) DeleteCollection(ctx context.Context, name string) error {
 		return errors.New("collection name is required")
 	}
 
+	s.known.Delete(name)
 	_, err := s.collections.Delete(ctx, &pb.DeleteCollection{
 		CollectionName: name,
 	})
@@ -455,6 +536,9 @@ func (s *Store) GetCollection(ctx context.Context, name string) (*vectorstore.Co
 		CollectionName: name,
 	})
 
+	if status.Code(err) == codes.NotFound {
+		return nil, fmt.Errorf("failed to get collection: %w: %s", vectorstore.ErrCollectionNotFound, name)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get collection: %w", err)
 	}
@@ -496,10 +580,25 @@ func convertToQdrantValue(v interface{}) *pb.Value {
 		return &pb.Value{Kind: &pb.Value_IntegerValue{IntegerValue: int64(val)}}
 	case int64:
 		return &pb.Value{Kind: &pb.Value_IntegerValue{IntegerValue: val}}
+	case float32:
+		return &pb.Value{Kind: &pb.Value_DoubleValue{DoubleValue: float64(val)}}
 	case float64:
 		return &pb.Value{Kind: &pb.Value_DoubleValue{DoubleValue: val}}
 	case bool:
 		return &pb.Value{Kind: &pb.Value_BoolValue{BoolValue: val}}
+	case []string:
+		// Lists are stored as lists so filters can match their elements
+		values := make([]*pb.Value, len(val))
+		for i, item := range val {
+			values[i] = convertToQdrantValue(item)
+		}
+		return &pb.Value{Kind: &pb.Value_ListValue{ListValue: &pb.ListValue{Values: values}}}
+	case []interface{}:
+		values := make([]*pb.Value, len(val))
+		for i, item := range val {
+			values[i] = convertToQdrantValue(item)
+		}
+		return &pb.Value{Kind: &pb.Value_ListValue{ListValue: &pb.ListValue{Values: values}}}
 	default:
 		// Default to string representation
 		return &pb.Value{Kind: &pb.Value_StringValue{StringValue: fmt.Sprintf("%v", val)}}
@@ -520,6 +619,12 @@ func convertFromQdrantValue(v *pb.Value) interface{} {
 		return kind.DoubleValue
 	case *pb.Value_BoolValue:
 		return kind.BoolValue
+	case *pb.Value_ListValue:
+		values := make([]interface{}, len(kind.ListValue.GetValues()))
+		for i, item := range kind.ListValue.GetValues() {
+			values[i] = convertFromQdrantValue(item)
+		}
+		return values
 	default:
 		return nil
 	}
@@ -534,28 +639,3 @@ func convertToQdrantIDs(ids []string) []*pb.PointId {
 	}
 	return result
 }
-
-func convertToQdrantFilter(filter vectorstore.Filter) *pb.Filter {
-	// Basic filter conversion - can be extended for more complex filters
-	conditions := make([]*pb.Condition, 0, len(filter))
-
-	for key, value := range filter {
-		condition := &pb.Condition{
-			ConditionOneOf: &pb.Condition_Field{
-				Field: &pb.FieldCondition{
-					Key: key,
-					Match: &pb.Match{
-						MatchValue: &pb.Match_Keyword{
-							Keyword: fmt.Sprintf("%v", value),
-						},
-					},
-				},
-			},
-		}
-		conditions = append(conditions, condition)
-	}
-
-	return &pb.Filter{
-		Must: conditions,
-	}
-}
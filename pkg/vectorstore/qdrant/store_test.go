@@ -0,0 +1,98 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package qdrant
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"deep-thinking-agent/pkg/vectorstore"
+
+	pb "github.com/qdrant/go-client/qdrant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeCollections is a collections service that counts lookups and creates.
+type fakeCollections struct {
+	pb.CollectionsClient
+
+	mu      sync.Mutex
+	exists  map[string]bool
+	gets    int
+	creates int
+}
+
+func (f *fakeCollections) Get(ctx context.Context, in *pb.GetCollectionInfoRequest, opts ...grpc.CallOption) (*pb.GetCollectionInfoResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.gets++
+	if !f.exists[in.CollectionName] {
+		return nil, status.Error(codes.NotFound, "collection not found")
+	}
+	return &pb.GetCollectionInfoResponse{}, nil
+}
+
+func (f *fakeCollections) Create(ctx context.Context, in *pb.CreateCollection, opts ...grpc.CallOption) (*pb.CollectionOperationResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.creates++
+	if f.exists[in.CollectionName] {
+		return nil, status.Error(codes.AlreadyExists, "collection already exists")
+	}
+	f.exists[in.CollectionName] = true
+	return &pb.CollectionOperationResponse{Result: true}, nil
+}
+
+func TestStore_EnsureCollection(t *testing.T) {
+	ctx := context.Background()
+	collections := &fakeCollections{exists: map[string]bool{}}
+	store := &Store{collections: collections}
+	docs := []vectorstore.Document{{Embedding: []float32{1, 0, 0}}}
+
+	// Concurrent inserts into a new collection create it once
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- store.ensureCollection(ctx, "documents", docs)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("ensureCollection() error = %v", err)
+		}
+	}
+	if collections.creates != 1 {
+		t.Errorf("created the collection %d times, want 1", collections.creates)
+	}
+
+	// Known collections are not looked up again
+	gets := collections.gets
+	if err := store.ensureCollection(ctx, "documents", docs); err != nil {
+		t.Fatalf("ensureCollection() error = %v", err)
+	}
+	if collections.gets != gets {
+		t.Errorf("looked up a known collection %d more times", collections.gets-gets)
+	}
+
+	// Collections created elsewhere are found without creating them
+	collections.exists["shared"] = true
+	if err := store.ensureCollection(ctx, "shared", nil); err != nil {
+		t.Errorf("ensureCollection() of an existing collection error = %v", err)
+	}
+	if collections.creates != 1 {
+		t.Errorf("created an existing collection")
+	}
+}
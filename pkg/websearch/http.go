@@ -0,0 +1,298 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package websearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HTTPSearcher implements the Searcher interface for JSON search APIs.
+// The request shape and the location of results in the response are
+// configurable, so most hosted search APIs can be used without new code.
+type HTTPSearcher struct {
+	client *http.Client
+	config Config
+}
+
+// Config contains configuration for an HTTP JSON search provider.
+type Config struct {
+	// Provider is a preset name ("serper", "brave") or "http" for a custom API
+	Provider string
+
+	// Endpoint is the search API URL
+	Endpoint string
+
+	// Method is "GET" (query string parameters) or "POST" (JSON body)
+	Method string
+
+	// APIKey for authentication (if required)
+	APIKey string
+
+	// APIKeyHeader is the header carrying the API key.
+	// If empty, the key is sent as "Authorization: Bearer <key>".
+	APIKeyHeader string
+
+	// QueryParam is the parameter name for the query (default "q")
+	QueryParam string
+
+	// CountParam is the parameter name for the result count (omitted if empty)
+	CountParam string
+
+	// ResultsPath is the dot-separated path to the result array in the response
+	ResultsPath string
+
+	// TitleField, URLField and SnippetField name the fields of each result
+	TitleField   string
+	URLField     string
+	SnippetField string
+
+	// MaxResults is used when Search is called with maxResults <= 0
+	MaxResults int
+
+	// TimeoutSeconds for each request
+	TimeoutSeconds int
+}
+
+// PresetConfig returns the configuration for a known search provider.
+func PresetConfig(provider string) (*Config, error) {
+	switch provider {
+	case "serper":
+		return &Config{
+			Provider:     "serper",
+			Endpoint:     "https://google.serper.dev/search",
+			Method:       http.MethodPost,
+			APIKeyHeader: "X-API-KEY",
+			QueryParam:   "q",
+			CountParam:   "num",
+			ResultsPath:  "organic",
+			TitleField:   "title",
+			URLField:     "link",
+			SnippetField: "snippet",
+		}, nil
+	case "brave":
+		return &Config{
+			Provider:     "brave",
+			Endpoint:     "https://api.search.brave.com/res/v1/web/search",
+			Method:       http.MethodGet,
+			APIKeyHeader: "X-Subscription-Token",
+			QueryParam:   "q",
+			CountParam:   "count",
+			ResultsPath:  "web.results",
+			TitleField:   "title",
+			URLField:     "url",
+			SnippetField: "description",
+		}, nil
+	case "http":
+		return &Config{Provider: "http"}, nil
+	default:
+		return nil, fmt.Errorf("unsupported web search provider: %s", provider)
+	}
+}
+
+// NewHTTPSearcher creates a new HTTP JSON search provider.
+// Unset fields fall back to defaults: GET, "q", "title", "url", "snippet".
+func NewHTTPSearcher(config *Config) (*HTTPSearcher, error) {
+	if config == nil {
+		return nil, errors.New("web search config is required")
+	}
+	if config.Endpoint == "" {
+		return nil, errors.New("web search endpoint is required")
+	}
+
+	cfg := *config
+	if cfg.Provider == "" {
+		cfg.Provider = "http"
+	}
+	if cfg.Method == "" {
+		cfg.Method = http.MethodGet
+	}
+	cfg.Method = strings.ToUpper(cfg.Method)
+	if cfg.Method != http.MethodGet && cfg.Method != http.MethodPost {
+		return nil, fmt.Errorf("unsupported HTTP method: %s", cfg.Method)
+	}
+	if cfg.QueryParam == "" {
+		cfg.QueryParam = "q"
+	}
+	if cfg.TitleField == "" {
+		cfg.TitleField = "title"
+	}
+	if cfg.URLField == "" {
+		cfg.URLField = "url"
+	}
+	if cfg.SnippetField == "" {
+		cfg.SnippetField = "snippet"
+	}
+	if cfg.MaxResults <= 0 {
+		cfg.MaxResults = 5
+	}
+	if cfg.TimeoutSeconds <= 0 {
+		cfg.TimeoutSeconds = 15
+	}
+
+	return &HTTPSearcher{
+		client: &http.Client{Timeout: time.Duration(cfg.TimeoutSeconds) * time.Second},
+		config: cfg,
+	}, nil
+}
+
+// Search queries the configured API and returns normalized results.
+func (s *HTTPSearcher) Search(ctx context.Context, query string, maxResults int) ([]Result, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, errors.New("query cannot be empty")
+	}
+	if maxResults <= 0 {
+		maxResults = s.config.MaxResults
+	}
+
+	req, err := s.buildRequest(ctx, query, maxResults)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("web search request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("web search returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	results, err := s.parseResults(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) > maxResults {
+		results = results[:maxResults]
+	}
+
+	return results, nil
+}
+
+// Name returns the provider name.
+func (s *HTTPSearcher) Name() string {
+	return s.config.Provider
+}
+
+// buildRequest creates the HTTP request for a query.
+func (s *HTTPSearcher) buildRequest(ctx context.Context, query string, maxResults int) (*http.Request, error) {
+	var req *http.Request
+
+	if s.config.Method == http.MethodPost {
+		payload := map[string]interface{}{s.config.QueryParam: query}
+		if s.config.CountParam != "" {
+			payload[s.config.CountParam] = maxResults
+		}
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, s.config.Endpoint, bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+	} else {
+		endpoint, err := url.Parse(s.config.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+		params := endpoint.Query()
+		params.Set(s.config.QueryParam, query)
+		if s.config.CountParam != "" {
+			params.Set(s.config.CountParam, strconv.Itoa(maxResults))
+		}
+		endpoint.RawQuery = params.Encode()
+
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if s.config.APIKey != "" {
+		if s.config.APIKeyHeader != "" {
+			req.Header.Set(s.config.APIKeyHeader, s.config.APIKey)
+		} else {
+			req.Header.Set("Authorization", "Bearer "+s.config.APIKey)
+		}
+	}
+
+	return req, nil
+}
+
+// parseResults extracts results from the response body using the configured
+// results path and field names. Results without a URL or text are skipped.
+func (s *HTTPSearcher) parseResults(body []byte) ([]Result, error) {
+	var payload interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	node := payload
+	if s.config.ResultsPath != "" {
+		for _, key := range strings.Split(s.config.ResultsPath, ".") {
+			obj, ok := node.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("results path %q not found in response", s.config.ResultsPath)
+			}
+			node, ok = obj[key]
+			if !ok {
+				// Providers omit the array when there are no hits
+				return []Result{}, nil
+			}
+		}
+	}
+
+	items, ok := node.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("results path %q is not an array", s.config.ResultsPath)
+	}
+
+	results := make([]Result, 0, len(items))
+	for _, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		result := Result{
+			Title:   stringField(obj, s.config.TitleField),
+			URL:     stringField(obj, s.config.URLField),
+			Snippet: stringField(obj, s.config.SnippetField),
+		}
+		if result.URL == "" || (result.Title == "" && result.Snippet == "") {
+			continue
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// stringField returns obj[key] if it is a string, otherwise "".
+func stringField(obj map[string]interface{}, key string) string {
+	value, _ := obj[key].(string)
+	return strings.TrimSpace(value)
+}
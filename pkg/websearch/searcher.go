@@ -0,0 +1,82 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package websearch
+
+import (
+	"context"
+	"strings"
+
+	"deep-thinking-agent/pkg/vectorstore"
+)
+
+// Result represents a single web search hit.
+type Result struct {
+	// Title is the page title
+	Title string
+
+	// URL is the source location of the result
+	URL string
+
+	// Snippet is the provider's excerpt of the page content
+	Snippet string
+}
+
+// Searcher defines the interface for web search providers.
+// This abstraction allows swapping providers (Serper, Brave, self-hosted, etc.)
+type Searcher interface {
+	// Search returns up to maxResults results for the query, best first.
+	Search(ctx context.Context, query string, maxResults int) ([]Result, error)
+
+	// Name returns the provider name.
+	Name() string
+}
+
+// Metadata keys set on documents produced from web results.
+const (
+	MetadataSource    = "source"
+	MetadataSourceURL = "source_url"
+	MetadataTitle     = "title"
+	MetadataRank      = "rank"
+
+	// SourceWeb is the MetadataSource value for web results
+	SourceWeb = "web"
+)
+
+// ToDocuments normalizes web results into vector store documents so they can
+// flow through reranking and distillation like retrieved chunks. The source
+// URL is kept in metadata for citation. Scores decrease with provider rank.
+func ToDocuments(results []Result) []vectorstore.Document {
+	docs := make([]vectorstore.Document, 0, len(results))
+
+	for i, result := range results {
+		content := strings.TrimSpace(result.Snippet)
+		if result.Title != "" {
+			content = strings.TrimSpace(result.Title + "\n" + content)
+		}
+		if content == "" {
+			continue
+		}
+
+		id := result.URL
+		if id == "" {
+			id = content
+		}
+
+		docs = append(docs, vectorstore.Document{
+			ID:      "web:" + id,
+			Content: content,
+			Metadata: map[string]interface{}{
+				MetadataSource:    SourceWeb,
+				MetadataSourceURL: result.URL,
+				MetadataTitle:     result.Title,
+				MetadataRank:      i + 1,
+			},
+			Score: 1.0 / float32(i+1),
+		})
+	}
+
+	return docs
+}
@@ -0,0 +1,252 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package websearch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPresetConfig(t *testing.T) {
+	tests := []struct {
+		provider    string
+		wantErr     bool
+		resultsPath string
+		urlField    string
+	}{
+		{provider: "serper", resultsPath: "organic", urlField: "link"},
+		{provider: "brave", resultsPath: "web.results", urlField: "url"},
+		{provider: "http"},
+		{provider: "unknown", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			config, err := PresetConfig(tt.provider)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("PresetConfig() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PresetConfig() error = %v", err)
+			}
+			if config.ResultsPath != tt.resultsPath {
+				t.Errorf("ResultsPath = %v, want %v", config.ResultsPath, tt.resultsPath)
+			}
+			if config.URLField != tt.urlField {
+				t.Errorf("URLField = %v, want %v", config.URLField, tt.urlField)
+			}
+		})
+	}
+}
+
+func TestNewHTTPSearcher(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *Config
+		wantErr bool
+	}{
+		{name: "nil config", config: nil, wantErr: true},
+		{name: "missing endpoint", config: &Config{}, wantErr: true},
+		{name: "unsupported method", config: &Config{Endpoint: "http://x", Method: "PUT"}, wantErr: true},
+		{name: "defaults", config: &Config{Endpoint: "http://x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searcher, err := NewHTTPSearcher(tt.config)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("NewHTTPSearcher() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewHTTPSearcher() error = %v", err)
+			}
+			if searcher.Name() != "http" {
+				t.Errorf("Name() = %v, want http", searcher.Name())
+			}
+			if searcher.config.Method != http.MethodGet || searcher.config.QueryParam != "q" {
+				t.Errorf("defaults not applied: %+v", searcher.config)
+			}
+		})
+	}
+}
+
+func TestHTTPSearcher_SearchGET(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("method = %v, want GET", r.Method)
+		}
+		if r.URL.Query().Get("q") != "golang" {
+			t.Errorf("query = %v, want golang", r.URL.Query().Get("q"))
+		}
+		if r.URL.Query().Get("count") != "2" {
+			t.Errorf("count = %v, want 2", r.URL.Query().Get("count"))
+		}
+		if r.Header.Get("X-Subscription-Token") != "secret" {
+			t.Errorf("missing API key header")
+		}
+		w.Write([]byte(`{"web": {"results": [
+			{"title": "Go", "url": "https://go.dev", "description": "The Go language"},
+			{"title": "No URL", "description": "skipped"},
+			{"title": "Tour", "url": "https://go.dev/tour", "description": "A tour of Go"},
+			{"title": "Extra", "url": "https://example.com", "description": "trimmed"}
+		]}}`))
+	}))
+	defer server.Close()
+
+	config, _ := PresetConfig("brave")
+	config.Endpoint = server.URL
+	config.APIKey = "secret"
+
+	searcher, err := NewHTTPSearcher(config)
+	if err != nil {
+		t.Fatalf("NewHTTPSearcher() error = %v", err)
+	}
+
+	results, err := searcher.Search(context.Background(), "golang", 2)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].URL != "https://go.dev" || results[0].Snippet != "The Go language" {
+		t.Errorf("unexpected first result: %+v", results[0])
+	}
+	if results[1].URL != "https://go.dev/tour" {
+		t.Errorf("unexpected second result: %+v", results[1])
+	}
+}
+
+func TestHTTPSearcher_SearchPOST(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %v, want POST", r.Method)
+		}
+		var payload map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("failed to decode body: %v", err)
+		}
+		if payload["q"] != "rag" {
+			t.Errorf("q = %v, want rag", payload["q"])
+		}
+		if payload["num"] != float64(5) {
+			t.Errorf("num = %v, want 5", payload["num"])
+		}
+		w.Write([]byte(`{"organic": [{"title": "RAG", "link": "https://example.com/rag", "snippet": "Retrieval"}]}`))
+	}))
+	defer server.Close()
+
+	config, _ := PresetConfig("serper")
+	config.Endpoint = server.URL
+
+	searcher, err := NewHTTPSearcher(config)
+	if err != nil {
+		t.Fatalf("NewHTTPSearcher() error = %v", err)
+	}
+
+	// maxResults <= 0 uses the configured default
+	results, err := searcher.Search(context.Background(), "rag", 0)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(results) != 1 || results[0].URL != "https://example.com/rag" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+}
+
+func TestHTTPSearcher_SearchErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		resultsPath string
+		wantErr     bool
+		wantCount   int
+	}{
+		{name: "server error", status: http.StatusInternalServerError, body: `oops`, wantErr: true},
+		{name: "invalid JSON", status: http.StatusOK, body: `{invalid`, wantErr: true},
+		{name: "path not an array", status: http.StatusOK, body: `{"results": "x"}`, resultsPath: "results", wantErr: true},
+		{name: "missing results is empty", status: http.StatusOK, body: `{}`, resultsPath: "results", wantCount: 0},
+		{name: "top-level array", status: http.StatusOK, body: `[{"title": "a", "url": "https://a"}]`, wantCount: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			searcher, err := NewHTTPSearcher(&Config{Endpoint: server.URL, ResultsPath: tt.resultsPath})
+			if err != nil {
+				t.Fatalf("NewHTTPSearcher() error = %v", err)
+			}
+
+			results, err := searcher.Search(context.Background(), "query", 5)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Search() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if len(results) != tt.wantCount {
+				t.Errorf("expected %d results, got %d", tt.wantCount, len(results))
+			}
+		})
+	}
+
+	t.Run("empty query", func(t *testing.T) {
+		searcher, _ := NewHTTPSearcher(&Config{Endpoint: "http://unused"})
+		if _, err := searcher.Search(context.Background(), "  ", 5); err == nil {
+			t.Error("Search() expected error for empty query")
+		}
+	})
+}
+
+func TestToDocuments(t *testing.T) {
+	results := []Result{
+		{Title: "First", URL: "https://a.example", Snippet: "alpha"},
+		{Title: "", URL: "https://b.example", Snippet: "beta"},
+		{Title: "", URL: "https://c.example", Snippet: ""},
+	}
+
+	docs := ToDocuments(results)
+	if len(docs) != 2 {
+		t.Fatalf("expected 2 documents, got %d", len(docs))
+	}
+
+	if docs[0].ID != "web:https://a.example" {
+		t.Errorf("ID = %v", docs[0].ID)
+	}
+	if docs[0].Content != "First\nalpha" {
+		t.Errorf("Content = %q", docs[0].Content)
+	}
+	if docs[0].Metadata[MetadataSourceURL] != "https://a.example" {
+		t.Errorf("source_url = %v", docs[0].Metadata[MetadataSourceURL])
+	}
+	if docs[0].Metadata[MetadataSource] != SourceWeb {
+		t.Errorf("source = %v", docs[0].Metadata[MetadataSource])
+	}
+	if docs[1].Content != "beta" {
+		t.Errorf("Content = %q", docs[1].Content)
+	}
+	if docs[0].Score <= docs[1].Score {
+		t.Errorf("scores should decrease with rank: %v, %v", docs[0].Score, docs[1].Score)
+	}
+}
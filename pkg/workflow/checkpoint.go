@@ -0,0 +1,134 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrCheckpointNotFound is returned when no checkpoint exists for a run.
+var ErrCheckpointNotFound = errors.New("checkpoint not found")
+
+// Checkpoint records the progress of a workflow run after a completed node.
+type Checkpoint struct {
+	// RunID identifies the run this checkpoint belongs to
+	RunID string `json:"run_id"`
+
+	// NextNode is the node to execute when the run is resumed.
+	// It is empty once the run has finished.
+	NextNode string `json:"next_node"`
+
+	// LastNode is the most recently completed node
+	LastNode string `json:"last_node"`
+
+	// Iterations is the number of loop iterations executed so far
+	Iterations int `json:"iterations"`
+
+	// State is the workflow state after LastNode completed
+	State *State `json:"state"`
+
+	// SavedAt is when the checkpoint was written
+	SavedAt time.Time `json:"saved_at"`
+}
+
+// Completed returns true if the checkpointed run has finished.
+func (c *Checkpoint) Completed() bool {
+	return c.NextNode == ""
+}
+
+// CheckpointStore persists workflow checkpoints so runs can be resumed.
+// Each run keeps only its latest checkpoint.
+type CheckpointStore interface {
+	// Save stores the checkpoint, replacing any previous one for the run
+	Save(ctx context.Context, checkpoint *Checkpoint) error
+
+	// Load returns the latest checkpoint for a run, or ErrCheckpointNotFound
+	Load(ctx context.Context, runID string) (*Checkpoint, error)
+}
+
+// FileCheckpointStore stores checkpoints as JSON files in a directory,
+// one file per run.
+type FileCheckpointStore struct {
+	dir string
+}
+
+// NewFileCheckpointStore creates a checkpoint store in dir, creating the
+// directory if needed.
+func NewFileCheckpointStore(dir string) (*FileCheckpointStore, error) {
+	if dir == "" {
+		return nil, errors.New("checkpoint directory is required")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+
+	return &FileCheckpointStore{dir: dir}, nil
+}
+
+// Save writes the checkpoint atomically via a temporary file and rename.
+func (s *FileCheckpointStore) Save(ctx context.Context, checkpoint *Checkpoint) error {
+	if checkpoint == nil {
+		return errors.New("checkpoint cannot be nil")
+	}
+
+	path, err := s.path(checkpoint.RunID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// Load reads the checkpoint for a run.
+func (s *FileCheckpointStore) Load(ctx context.Context, runID string) (*Checkpoint, error) {
+	path, err := s.path(runID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrCheckpointNotFound, runID)
+		}
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint: %w", err)
+	}
+
+	return &checkpoint, nil
+}
+
+// path returns the checkpoint file for a run, rejecting IDs that could
+// escape the store directory.
+func (s *FileCheckpointStore) path(runID string) (string, error) {
+	if runID == "" || runID == "." || runID == ".." || filepath.Base(runID) != runID {
+		return "", fmt.Errorf("invalid run ID: %q", runID)
+	}
+	return filepath.Join(s.dir, runID+".json"), nil
+}
@@ -8,35 +8,65 @@ package workflow
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // Executor runs the workflow graph with state management.
 type Executor struct {
-	graph   *Graph
-	timeout time.Duration
+	graph            *Graph
+	timeout          time.Duration
+	maxParallelSteps int
+	observer         Observer
+	observerMu       sync.Mutex // serializes observer calls from parallel steps
+	checkpoints      CheckpointStore
 }
 
 // ExecutorConfig contains configuration for the executor.
 type ExecutorConfig struct {
 	Timeout time.Duration
+
+	// MaxParallelSteps bounds how many independent plan steps run at once.
+	// Values of 0 or 1 execute steps sequentially. Parallel execution requires
+	// the graph to declare its per-step pipeline with SetStepNodes.
+	MaxParallelSteps int
+
+	// Observer, if set, receives execution events as nodes run
+	Observer Observer
+
+	// Checkpoints, if set, stores the state after every completed node so
+	// failed or interrupted runs can be continued with Resume
+	Checkpoints CheckpointStore
 }
 
 // NewExecutor creates a new workflow executor.
 func NewExecutor(graph *Graph, config *ExecutorConfig) *Executor {
 	if config == nil {
 		config = &ExecutorConfig{
-			Timeout: 5 * time.Minute,
+			Timeout:          5 * time.Minute,
+			MaxParallelSteps: 1,
 		}
 	}
 
 	return &Executor{
-		graph:   graph,
-		timeout: config.Timeout,
+		graph:            graph,
+		timeout:          config.Timeout,
+		maxParallelSteps: config.MaxParallelSteps,
+		observer:         config.Observer,
+		checkpoints:      config.Checkpoints,
 	}
 }
 
+// SetObserver replaces the observer that receives execution events.
+// Pass nil to disable events. It must not be called while Execute is running.
+func (e *Executor) SetObserver(observer Observer) {
+	e.observer = observer
+}
+
 // Execute runs the workflow graph starting from the initial state.
+// On failure the state reached so far is returned along with the error.
 func (e *Executor) Execute(ctx context.Context, initialState *State) (*State, error) {
 	if e.graph == nil {
 		return nil, fmt.Errorf("graph is nil")
@@ -46,6 +76,54 @@ func (e *Executor) Execute(ctx context.Context, initialState *State) (*State, er
 		return nil, fmt.Errorf("initial state is nil")
 	}
 
+	// Get starting node
+	startNodeName := e.graph.GetStartNode()
+	if startNodeName == "" {
+		return nil, fmt.Errorf("no start node defined")
+	}
+
+	if e.checkpoints != nil && initialState.RunID == "" {
+		initialState.RunID = uuid.New().String()
+	}
+
+	return e.run(ctx, &Checkpoint{
+		RunID:    initialState.RunID,
+		NextNode: startNodeName,
+		State:    initialState,
+	})
+}
+
+// Resume continues a checkpointed run from the node after the last one that
+// completed. Resuming a run that already finished returns its final state.
+func (e *Executor) Resume(ctx context.Context, runID string) (*State, error) {
+	if e.graph == nil {
+		return nil, fmt.Errorf("graph is nil")
+	}
+
+	if e.checkpoints == nil {
+		return nil, fmt.Errorf("no checkpoint store configured")
+	}
+
+	checkpoint, err := e.checkpoints.Load(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+	if checkpoint.State == nil {
+		return nil, fmt.Errorf("checkpoint for run %s has no state", runID)
+	}
+	checkpoint.State.RunID = runID
+
+	if checkpoint.Completed() {
+		return checkpoint.State, nil
+	}
+
+	return e.run(ctx, checkpoint)
+}
+
+// run executes the graph from a checkpoint's next node. The main loop runs
+// until routing ends the workflow, then the finish nodes run once in order.
+// A finish node that returns a NextNode sends the run back into the loop.
+func (e *Executor) run(ctx context.Context, from *Checkpoint) (*State, error) {
 	// Apply timeout
 	if e.timeout > 0 {
 		var cancel context.CancelFunc
@@ -53,92 +131,312 @@ func (e *Executor) Execute(ctx context.Context, initialState *State) (*State, er
 		defer cancel()
 	}
 
-	// Get starting node
-	currentNodeName := e.graph.GetStartNode()
-	if currentNodeName == "" {
-		return nil, fmt.Errorf("no start node defined")
-	}
+	state := from.State
+	currentNodeName := from.NextNode
+	lastNodeName := from.LastNode
+	iterationCount := from.Iterations
+	finishNodeName := e.graph.GetFinishNode()
+	var stepStart time.Time
 
-	state := initialState
-	iterationCount := 0
+	for currentNodeName != "" {
+		// Run the finish nodes, from the current one if a run resumed
+		// part-way through them
+		if e.graph.finishPosition(currentNodeName) >= 0 {
+			var err error
+			state, currentNodeName, err = e.runFinish(ctx, state, currentNodeName, iterationCount)
+			if err != nil {
+				return state, err
+			}
+			continue
+		}
 
-	// Execute nodes in sequence
-	for {
 		// Check context cancellation
 		select {
 		case <-ctx.Done():
-			return nil, fmt.Errorf("execution timeout or cancelled: %w", ctx.Err())
+			return state, fmt.Errorf("execution timeout or cancelled: %w", ctx.Err())
 		default:
 		}
 
 		// Safety check for infinite loops
 		iterationCount++
 		if iterationCount > 100 {
-			return nil, fmt.Errorf("exceeded maximum iteration count (100)")
+			return state, fmt.Errorf("exceeded maximum iteration count (100)")
 		}
 
-		// Get current node
-		node, err := e.graph.GetNode(currentNodeName)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get node %s: %w", currentNodeName, err)
-		}
+		var result *NodeResult
+		if e.runsStepsInParallel(currentNodeName, state) {
+			// Run all ready plan steps through the step pipeline, then route
+			// onward as if the last step node had just executed
+			stepNodes := e.graph.GetStepNodes()
+			var err error
+			result, err = e.executeStepBatch(ctx, state)
+			if err != nil {
+				return state, err
+			}
+			currentNodeName = stepNodes[len(stepNodes)-1]
+		} else {
+			// Get current node
+			node, err := e.graph.GetNode(currentNodeName)
+			if err != nil {
+				return state, fmt.Errorf("failed to get node %s: %w", currentNodeName, err)
+			}
 
-		// Execute node
-		result, err := node.Execute(state)
-		if err != nil {
-			return nil, fmt.Errorf("node %s execution failed: %w", currentNodeName, err)
+			// A new plan step starts at the first node of the step pipeline
+			if stepNodes := e.graph.GetStepNodes(); len(stepNodes) > 0 && stepNodes[0] == currentNodeName {
+				stepStart = time.Now()
+			}
+
+			// Execute node
+			result, err = e.runNode(ctx, node, state, stepStart)
+			if err != nil {
+				return state, fmt.Errorf("node %s execution failed: %w", currentNodeName, err)
+			}
 		}
+		lastNodeName = currentNodeName
 
 		if result == nil {
-			return nil, fmt.Errorf("node %s returned nil result", currentNodeName)
+			return state, fmt.Errorf("node %s returned nil result", currentNodeName)
 		}
 
 		// Update state
-		state = result.UpdatedState
-		if state == nil {
-			return nil, fmt.Errorf("node %s returned nil state", currentNodeName)
+		if result.UpdatedState == nil {
+			return state, fmt.Errorf("node %s returned nil state", currentNodeName)
 		}
+		state = result.UpdatedState
 
 		// Check for errors in state
 		if state.Error != nil {
 			return state, fmt.Errorf("workflow error: %w", state.Error)
 		}
 
-		// Determine next node
-		if result.NextNode != "" {
-			// Explicit next node specified
-			currentNodeName = result.NextNode
-		} else {
-			// Use default routing from graph
-			nextNodes := e.graph.GetNextNodes(currentNodeName)
+		// Determine next node; once the loop is done, only the finish nodes
+		// (if any) remain
+		nextNodeName, err := e.nextNode(currentNodeName, result, state)
+		if err != nil {
+			return state, err
+		}
+		if nextNodeName == End {
+			nextNodeName = finishNodeName
+		}
+		currentNodeName = nextNodeName
+
+		if err := e.saveCheckpoint(ctx, state, currentNodeName, lastNodeName, iterationCount); err != nil {
+			return state, err
+		}
+	}
+
+	return state, nil
+}
+
+// runFinish runs the finish nodes in order, starting at nodeName. It returns
+// the node to continue the main loop at if a finish node asks for one, or ""
+// when the run is complete.
+func (e *Executor) runFinish(ctx context.Context, state *State, nodeName string, iterations int) (*State, string, error) {
+	finishNodes := e.graph.GetFinishNodes()
+
+	for i := e.graph.finishPosition(nodeName); i < len(finishNodes); i++ {
+		name := finishNodes[i]
 
-			if len(nextNodes) == 0 {
-				// No more nodes, workflow complete
-				break
+		select {
+		case <-ctx.Done():
+			return state, "", fmt.Errorf("execution timeout or cancelled: %w", ctx.Err())
+		default:
+		}
+
+		node, err := e.graph.GetNode(name)
+		if err != nil {
+			return state, "", fmt.Errorf("failed to get node %s: %w", name, err)
+		}
+
+		result, err := e.runNode(ctx, node, state, time.Time{})
+		if err != nil {
+			return state, "", fmt.Errorf("node %s execution failed: %w", name, err)
+		}
+		if result == nil || result.UpdatedState == nil {
+			return state, "", fmt.Errorf("node %s returned nil state", name)
+		}
+
+		state = result.UpdatedState
+		if state.Error != nil {
+			return state, "", fmt.Errorf("workflow error: %w", state.Error)
+		}
+
+		next := ""
+		if result.NextNode != "" && result.NextNode != End {
+			// Back into the main loop
+			if _, err := e.graph.GetNode(result.NextNode); err != nil {
+				return state, "", fmt.Errorf("node %s routed to unknown node %s", name, result.NextNode)
 			}
+			if e.graph.finishPosition(result.NextNode) >= 0 {
+				return state, "", fmt.Errorf("finish node %s cannot route to finish node %s", name, result.NextNode)
+			}
+			next = result.NextNode
+		} else if i+1 < len(finishNodes) {
+			next = finishNodes[i+1]
+		}
 
-			if len(nextNodes) == 1 {
-				// Single next node
-				currentNodeName = nextNodes[0]
-			} else {
-				// Multiple possible next nodes - use routing logic
-				currentNodeName = e.routeNext(state, nextNodes)
+		if err := e.saveCheckpoint(ctx, state, next, name, iterations); err != nil {
+			return state, "", err
+		}
+		if result.NextNode != "" && result.NextNode != End {
+			return state, next, nil
+		}
+	}
+
+	return state, "", nil
+}
+
+// nextNode determines which node follows nodeName: the node the result
+// names, or else the graph's routing. It returns End when the main loop
+// should stop: the routing ends it, the state says not to continue, the
+// iteration limit is reached, or the next node would start a plan step when
+// every step has completed.
+func (e *Executor) nextNode(nodeName string, result *NodeResult, state *State) (string, error) {
+	next := result.NextNode
+	if next == "" {
+		var err error
+		next, err = e.graph.route(nodeName, state)
+		if err != nil {
+			return "", err
+		}
+	} else if next != End {
+		if _, err := e.graph.GetNode(next); err != nil {
+			return "", fmt.Errorf("node %s routed to unknown node %s", nodeName, next)
+		}
+	}
+
+	// Routing straight to a finish node starts the finish pipeline there
+	if e.graph.finishPosition(next) >= 0 {
+		return next, nil
+	}
+
+	switch {
+	case next == End, !state.ShouldContinue, state.HasReachedMaxIterations():
+		return End, nil
+	case e.graph.startsStep(next) && state.IsComplete():
+		return End, nil
+	}
+
+	return next, nil
+}
+
+// saveCheckpoint records the run's progress if a checkpoint store is set.
+func (e *Executor) saveCheckpoint(ctx context.Context, state *State, nextNode, lastNode string, iterations int) error {
+	if e.checkpoints == nil {
+		return nil
+	}
+
+	err := e.checkpoints.Save(ctx, &Checkpoint{
+		RunID:      state.RunID,
+		NextNode:   nextNode,
+		LastNode:   lastNode,
+		Iterations: iterations,
+		State:      state,
+		SavedAt:    time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	return nil
+}
+
+// runsStepsInParallel reports whether execution at nodeName should run a
+// batch of plan steps concurrently instead of a single node.
+func (e *Executor) runsStepsInParallel(nodeName string, state *State) bool {
+	stepNodes := e.graph.GetStepNodes()
+	if e.maxParallelSteps <= 1 || len(stepNodes) == 0 || stepNodes[0] != nodeName {
+		return false
+	}
+	return state.Plan != nil && !state.IsComplete()
+}
+
+// executeStepBatch runs every ready plan step through the step pipeline,
+// at most maxParallelSteps at a time. Each step runs on its own fork of the
+// state; completed steps are merged back into PastSteps in plan order so the
+// result is deterministic regardless of completion order.
+func (e *Executor) executeStepBatch(ctx context.Context, state *State) (*NodeResult, error) {
+	ready := state.ReadySteps()
+
+	// Never exceed the iteration budget
+	remaining := state.MaxIterations - len(state.PastSteps)
+	if remaining < 1 {
+		remaining = 1
+	}
+	if len(ready) > remaining {
+		ready = ready[:remaining]
+	}
+
+	forks := make([]*State, len(ready))
+	errs := make([]error, len(ready))
+	baseHistory := len(state.PastSteps)
+
+	sem := make(chan struct{}, e.maxParallelSteps)
+	var wg sync.WaitGroup
+
+	for i, position := range ready {
+		wg.Add(1)
+		go func(i, position int) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = fmt.Errorf("execution timeout or cancelled: %w", ctx.Err())
+				return
 			}
+
+			forks[i], errs[i] = e.executeStep(ctx, state.forkForStep(position))
+		}(i, position)
+	}
+	wg.Wait()
+
+	// Report the first failure in plan order
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		// Check if policy says to finish
-		if currentNodeName == "finish" || !state.ShouldContinue {
-			break
+	for _, fork := range forks {
+		state.PastSteps = append(state.PastSteps, fork.PastSteps[baseHistory:]...)
+		if fork.Error != nil && state.Error == nil {
+			state.Error = fork.Error
 		}
+	}
 
-		// Check if plan is complete
-		if currentNodeName == "rewriter" && state.IsComplete() {
-			// All steps done, exit loop
-			break
+	// Keep the last step's working results on the main state
+	last := forks[len(forks)-1]
+	state.RetrievedDocs = last.RetrievedDocs
+	state.RerankedDocs = last.RerankedDocs
+	state.SynthesizedContext = last.SynthesizedContext
+	state.Retrieval = nil
+	state.advanceToNextPending()
+
+	return &NodeResult{UpdatedState: state}, nil
+}
+
+// executeStep runs a single plan step through the step pipeline and records
+// its execution time on the resulting past step.
+func (e *Executor) executeStep(ctx context.Context, state *State) (*State, error) {
+	start := time.Now()
+
+	for _, nodeName := range e.graph.GetStepNodes() {
+		node, err := e.graph.GetNode(nodeName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get node %s: %w", nodeName, err)
 		}
 
-		// Check max iterations safety
-		if state.HasReachedMaxIterations() {
+		result, err := e.runNode(ctx, node, state, start)
+		if err != nil {
+			return nil, fmt.Errorf("node %s execution failed: %w", nodeName, err)
+		}
+		if result == nil || result.UpdatedState == nil {
+			return nil, fmt.Errorf("node %s returned nil state", nodeName)
+		}
+
+		state = result.UpdatedState
+		if state.Error != nil {
 			break
 		}
 	}
@@ -146,19 +444,81 @@ func (e *Executor) Execute(ctx context.Context, initialState *State) (*State, er
 	return state, nil
 }
 
-// routeNext determines the next node based on state and available options.
-func (e *Executor) routeNext(state *State, options []string) string {
-	// For policy node, check ShouldContinue
-	if !state.ShouldContinue {
-		return "finish"
+// runNode executes a single node and reports its execution events.
+// Past steps the node adds without an execution time are stamped with the
+// time elapsed since stepStart, unless stepStart is zero.
+func (e *Executor) runNode(ctx context.Context, node Node, state *State, stepStart time.Time) (*NodeResult, error) {
+	name := node.Name()
+	before := snapshotState(state)
+
+	started := progressEvent(EventNodeStarted, name, state)
+	e.emit(ctx, started)
+
+	result, err := node.Execute(ctx, state)
+
+	// Nodes usually update the state in place; prefer the returned state
+	updated := state
+	if result != nil && result.UpdatedState != nil {
+		updated = result.UpdatedState
 	}
 
-	// Default: return first option
-	if len(options) > 0 {
-		return options[0]
+	if err == nil && !stepStart.IsZero() {
+		elapsed := time.Since(stepStart).Milliseconds()
+		for i := before.pastSteps; i < len(updated.PastSteps); i++ {
+			if updated.PastSteps[i].ExecutionTimeMs == 0 {
+				updated.PastSteps[i].ExecutionTimeMs = elapsed
+			}
+		}
+	}
+
+	if e.observer == nil && runObserverFrom(ctx) == nil {
+		return result, err
+	}
+
+	// Report progress for the step the node worked on, even if it advanced
+	// the state to the next one
+	finished := started
+	finished.Type = EventNodeFinished
+	finished.Time = time.Now()
+	finished.Duration = finished.Time.Sub(started.Time)
+	finished.Err = err
+	finished.Delta = deltaSince(before, updated)
+	if updated.Retrieval != nil && updated.Retrieval.StepIndex == started.StepPosition {
+		finished.Strategy = updated.Retrieval.Strategy
+	}
+	e.emit(ctx, finished)
+
+	if err != nil {
+		return result, err
 	}
 
-	return "finish"
+	for i := before.pastSteps; i < len(updated.PastSteps); i++ {
+		e.emit(ctx, stepEvent(name, updated, updated.PastSteps[i]))
+	}
+
+	if updated.Decision != nil && updated.Decision != before.decision {
+		event := progressEvent(EventPolicyDecision, name, updated)
+		event.Decision = updated.Decision
+		e.emit(ctx, event)
+	}
+
+	return result, err
+}
+
+// emit delivers an event to the executor's observer and to the observer
+// attached to ctx, if either is set.
+func (e *Executor) emit(ctx context.Context, event Event) {
+	if e.observer != nil {
+		e.observerMu.Lock()
+		e.observer.OnEvent(event)
+		e.observerMu.Unlock()
+	}
+
+	if run := runObserverFrom(ctx); run != nil {
+		run.mu.Lock()
+		run.observer.OnEvent(event)
+		run.mu.Unlock()
+	}
 }
 
 // ExecuteStep runs a single step of the workflow (for debugging/testing).
@@ -168,7 +528,7 @@ func (e *Executor) ExecuteStep(ctx context.Context, state *State, nodeName strin
 		return nil, err
 	}
 
-	result, err := node.Execute(state)
+	result, err := e.runNode(ctx, node, state, time.Time{})
 	if err != nil {
 		return nil, err
 	}
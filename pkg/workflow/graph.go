@@ -5,20 +5,39 @@
 
 package workflow
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // Graph represents the workflow execution graph.
 // It defines nodes and their connections for the deep thinking loop.
 type Graph struct {
-	nodes map[string]Node
-	edges map[string][]string // node name -> list of possible next nodes
-	start string              // starting node name
+	nodes  map[string]Node
+	edges  map[string][]string         // node name -> list of possible next nodes
+	routes map[string]*conditionalEdge // node name -> conditional routing
+	start  string                      // starting node name
+	finish []string                    // nodes run in order when the loop exits
+	steps  []string                    // per-step pipeline, in execution order
+}
+
+// End is the routing target that ends the main loop, after which the finish
+// nodes run. Nodes return it as NodeResult.NextNode, and conditional edges
+// return it from their route function.
+const End = "__end__"
+
+// conditionalEdge picks the next node from the state.
+type conditionalEdge struct {
+	route   func(*State) string
+	targets []string
 }
 
 // Node represents a single node in the workflow graph.
 type Node interface {
-	// Execute runs this node with the given state and returns updated state
-	Execute(state *State) (*NodeResult, error)
+	// Execute runs this node with the given state and returns updated state.
+	// Implementations should pass ctx to any LLM, embedding or vector store
+	// calls so that cancellation and the executor's timeout stop them.
+	Execute(ctx context.Context, state *State) (*NodeResult, error)
 
 	// Name returns the node's unique identifier
 	Name() string
@@ -27,8 +46,9 @@ type Node interface {
 // NewGraph creates a new workflow graph.
 func NewGraph() *Graph {
 	return &Graph{
-		nodes: make(map[string]Node),
-		edges: make(map[string][]string),
+		nodes:  make(map[string]Node),
+		edges:  make(map[string][]string),
+		routes: make(map[string]*conditionalEdge),
 	}
 }
 
@@ -42,6 +62,9 @@ func (g *Graph) AddNode(node Node) error {
 	if name == "" {
 		return fmt.Errorf("node name is empty")
 	}
+	if name == End {
+		return fmt.Errorf("node name %s is reserved", End)
+	}
 
 	if _, exists := g.nodes[name]; exists {
 		return fmt.Errorf("node %s already exists", name)
@@ -59,11 +82,46 @@ func (g *Graph) AddEdge(from, to string) error {
 	if _, exists := g.nodes[to]; !exists {
 		return fmt.Errorf("to node %s does not exist", to)
 	}
+	if _, exists := g.routes[from]; exists {
+		return fmt.Errorf("node %s already has a conditional edge", from)
+	}
 
 	g.edges[from] = append(g.edges[from], to)
 	return nil
 }
 
+// AddConditionalEdge routes from a node with a function of the state, which
+// returns one of targets, or End to finish the main loop. Every target must
+// exist, and a node has either a conditional edge or plain edges.
+func (g *Graph) AddConditionalEdge(from string, route func(*State) string, targets ...string) error {
+	if _, exists := g.nodes[from]; !exists {
+		return fmt.Errorf("from node %s does not exist", from)
+	}
+	if route == nil {
+		return fmt.Errorf("route for node %s is nil", from)
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("conditional edge from %s has no targets", from)
+	}
+	for _, to := range targets {
+		if _, exists := g.nodes[to]; !exists {
+			return fmt.Errorf("to node %s does not exist", to)
+		}
+	}
+	if _, exists := g.routes[from]; exists {
+		return fmt.Errorf("node %s already has a conditional edge", from)
+	}
+	if len(g.edges[from]) > 0 {
+		return fmt.Errorf("node %s already has edges", from)
+	}
+
+	g.routes[from] = &conditionalEdge{
+		route:   route,
+		targets: append([]string(nil), targets...),
+	}
+	return nil
+}
+
 // SetStart sets the starting node for execution.
 func (g *Graph) SetStart(nodeName string) error {
 	if _, exists := g.nodes[nodeName]; !exists {
@@ -74,6 +132,34 @@ func (g *Graph) SetStart(nodeName string) error {
 	return nil
 }
 
+// SetFinish sets the nodes that run in order after the main loop exits.
+// This is typically used to produce and check the final answer. A finish
+// node may send the run back into the main loop by returning a NextNode.
+func (g *Graph) SetFinish(nodeNames ...string) error {
+	for _, name := range nodeNames {
+		if _, exists := g.nodes[name]; !exists {
+			return fmt.Errorf("finish node %s does not exist", name)
+		}
+	}
+
+	g.finish = append([]string(nil), nodeNames...)
+	return nil
+}
+
+// SetStepNodes declares the nodes that process a single plan step, in order.
+// The executor uses this pipeline to run independent plan steps concurrently,
+// and ends the main loop instead of starting a step once the plan is complete.
+func (g *Graph) SetStepNodes(nodeNames ...string) error {
+	for _, name := range nodeNames {
+		if _, exists := g.nodes[name]; !exists {
+			return fmt.Errorf("step node %s does not exist", name)
+		}
+	}
+
+	g.steps = append([]string(nil), nodeNames...)
+	return nil
+}
+
 // GetNode retrieves a node by name.
 func (g *Graph) GetNode(name string) (Node, error) {
 	node, exists := g.nodes[name]
@@ -83,19 +169,82 @@ func (g *Graph) GetNode(name string) (Node, error) {
 	return node, nil
 }
 
-// GetNextNodes returns the possible next nodes from a given node.
+// GetNextNodes returns the possible next nodes from a given node, including
+// the targets of its conditional edge.
 func (g *Graph) GetNextNodes(nodeName string) []string {
+	if conditional, exists := g.routes[nodeName]; exists {
+		return conditional.targets
+	}
 	return g.edges[nodeName]
 }
 
+// route picks the node that follows nodeName when the node did not choose
+// one. A conditional edge decides from the state; otherwise the first plain
+// edge is taken. It returns End if the node has no outgoing edges.
+func (g *Graph) route(nodeName string, state *State) (string, error) {
+	conditional, exists := g.routes[nodeName]
+	if !exists {
+		if next := g.edges[nodeName]; len(next) > 0 {
+			return next[0], nil
+		}
+		return End, nil
+	}
+
+	next := conditional.route(state)
+	if next == End {
+		return End, nil
+	}
+	for _, target := range conditional.targets {
+		if target == next {
+			return next, nil
+		}
+	}
+	return "", fmt.Errorf("route from %s returned %q, which is not one of its targets", nodeName, next)
+}
+
 // GetStartNode returns the starting node name.
 func (g *Graph) GetStartNode() string {
 	return g.start
 }
 
+// GetFinishNode returns the first finish node name, or empty if none is set.
+func (g *Graph) GetFinishNode() string {
+	if len(g.finish) == 0 {
+		return ""
+	}
+	return g.finish[0]
+}
+
+// GetFinishNodes returns the finish nodes in execution order.
+func (g *Graph) GetFinishNodes() []string {
+	return g.finish
+}
+
+// finishPosition returns the position of nodeName among the finish nodes,
+// or -1 if it is not one.
+func (g *Graph) finishPosition(nodeName string) int {
+	for i, name := range g.finish {
+		if name == nodeName {
+			return i
+		}
+	}
+	return -1
+}
+
+// GetStepNodes returns the per-step pipeline, or nil if none is set.
+func (g *Graph) GetStepNodes() []string {
+	return g.steps
+}
+
+// startsStep reports whether nodeName is the first node of the step pipeline.
+func (g *Graph) startsStep(nodeName string) bool {
+	return len(g.steps) > 0 && g.steps[0] == nodeName
+}
+
 // BuildDeepThinkingGraph constructs the standard deep thinking workflow graph.
 // Flow: Plan → Rewrite → Supervise → Retrieve → Rerank → Distill → Reflect → Policy
-// Policy decides: continue (loop back) or finish
+// Policy decides: continue (loop back), replan (back to Plan) or finish, after which Synthesize runs
+// once, followed by Verify if a "verifier" node is provided
 func BuildDeepThinkingGraph(nodes map[string]Node) (*Graph, error) {
 	graph := NewGraph()
 
@@ -109,6 +258,7 @@ func BuildDeepThinkingGraph(nodes map[string]Node) (*Graph, error) {
 		"distiller",
 		"reflector",
 		"policy",
+		"synthesizer",
 	}
 
 	// Add all nodes
@@ -148,16 +298,46 @@ func BuildDeepThinkingGraph(nodes map[string]Node) (*Graph, error) {
 		return nil, err
 	}
 
-	// Policy decides: continue back to rewriter, or finish
-	if err := graph.AddEdge("policy", "rewriter"); err != nil {
+	// Policy decides: continue back to rewriter, finish with the synthesizer,
+	// or have the planner revise the remaining steps first
+	if err := graph.AddConditionalEdge("policy", routePolicy, "rewriter", "synthesizer", "planner"); err != nil {
 		return nil, err
 	}
-	// Policy can also go to "finish" (handled by executor)
 
 	// Set start node
 	if err := graph.SetStart("planner"); err != nil {
 		return nil, err
 	}
 
+	// Synthesizer produces the final answer once the loop exits; the optional
+	// verifier then checks it against the evidence
+	finish := []string{"synthesizer"}
+	if verifier, exists := nodes["verifier"]; exists {
+		if err := graph.AddNode(verifier); err != nil {
+			return nil, fmt.Errorf("failed to add node verifier: %w", err)
+		}
+		finish = append(finish, "verifier")
+	}
+	if err := graph.SetFinish(finish...); err != nil {
+		return nil, err
+	}
+
+	// Nodes run for each plan step; independent steps may run concurrently
+	if err := graph.SetStepNodes("rewriter", "supervisor", "retriever", "reranker", "distiller", "reflector"); err != nil {
+		return nil, err
+	}
+
 	return graph, nil
 }
+
+// routePolicy routes the policy's decision in the deep thinking graph.
+func routePolicy(state *State) string {
+	switch {
+	case !state.ShouldContinue:
+		return "synthesizer"
+	case state.Decision != nil && state.Decision.Replan:
+		return "planner"
+	default:
+		return "rewriter"
+	}
+}
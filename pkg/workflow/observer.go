@@ -0,0 +1,196 @@
+// Copyright 2025 Gerry Miller <gerry@gerrymiller.com>
+//
+// Licensed under the MIT License.
+// See LICENSE file in the project root for full license information.
+
+package workflow
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// EventType identifies the kind of execution event.
+type EventType string
+
+const (
+	// EventNodeStarted is emitted before a node executes
+	EventNodeStarted EventType = "node_started"
+
+	// EventNodeFinished is emitted after a node executes, successfully or not
+	EventNodeFinished EventType = "node_finished"
+
+	// EventStepCompleted is emitted when a plan step is added to PastSteps
+	EventStepCompleted EventType = "step_completed"
+
+	// EventPolicyDecision is emitted when a node records a new PolicyDecision
+	EventPolicyDecision EventType = "policy_decision"
+)
+
+// Event describes something that happened during workflow execution.
+// Fields that do not apply to an event type are left at their zero values.
+type Event struct {
+	Type EventType
+	Time time.Time
+
+	// Node is the node that produced the event
+	Node string
+
+	// StepPosition is the 0-based position of the plan step being worked on,
+	// or -1 if no plan step applies (e.g. during planning)
+	StepPosition int
+
+	// TotalSteps is the number of steps in the plan, or 0 before planning
+	TotalSteps int
+
+	// SubQuestion is the question of the plan step being worked on
+	SubQuestion string
+
+	// Strategy is the retrieval strategy selected for the step, if any
+	Strategy RetrievalStrategy
+
+	// Duration is how long the node (EventNodeFinished) or step
+	// (EventStepCompleted) took
+	Duration time.Duration
+
+	// Err is set on EventNodeFinished if the node failed
+	Err error
+
+	// Delta summarizes the node's changes to the state (EventNodeFinished)
+	Delta *StateDelta
+
+	// PastStep is the completed step (EventStepCompleted)
+	PastStep *PastStep
+
+	// Decision is the new policy decision (EventPolicyDecision)
+	Decision *PolicyDecision
+}
+
+// StateDelta summarizes how a single node changed the workflow state.
+type StateDelta struct {
+	// PlanCreated is true if the node produced the plan
+	PlanCreated bool
+
+	// PastStepsAdded is the number of steps the node added to PastSteps
+	PastStepsAdded int
+
+	// RetrievedDocs and RerankedDocs are the changes in document counts
+	RetrievedDocs int
+	RerankedDocs  int
+
+	// AnswerProduced is true if the node set FinalAnswer
+	AnswerProduced bool
+
+	// ShouldContinue is the state's continue flag after the node ran
+	ShouldContinue bool
+}
+
+// Observer receives execution events from an Executor.
+// The executor never calls OnEvent concurrently, but events from plan steps
+// running in parallel may interleave. OnEvent should return quickly since
+// execution waits for it.
+type Observer interface {
+	OnEvent(event Event)
+}
+
+// ObserverFunc adapts an ordinary function to the Observer interface.
+type ObserverFunc func(event Event)
+
+// OnEvent calls f(event).
+func (f ObserverFunc) OnEvent(event Event) {
+	f(event)
+}
+
+// runObserver is an observer attached to a context. It has its own lock so
+// that concurrent runs sharing an executor do not wait on each other.
+type runObserver struct {
+	mu       sync.Mutex
+	observer Observer
+}
+
+type runObserverKey struct{}
+
+// WithObserver returns a context whose runs deliver execution events to
+// observer, in addition to the executor's own observer. Use it to observe a
+// single run of an executor that is shared between concurrent runs.
+func WithObserver(ctx context.Context, observer Observer) context.Context {
+	return context.WithValue(ctx, runObserverKey{}, &runObserver{observer: observer})
+}
+
+// runObserverFrom returns the observer attached to ctx, or nil.
+func runObserverFrom(ctx context.Context) *runObserver {
+	observer, _ := ctx.Value(runObserverKey{}).(*runObserver)
+	return observer
+}
+
+// stateSnapshot captures the state values needed to compute a StateDelta,
+// since nodes may modify the state in place.
+type stateSnapshot struct {
+	hasPlan       bool
+	pastSteps     int
+	retrievedDocs int
+	rerankedDocs  int
+	hasAnswer     bool
+	decision      *PolicyDecision
+}
+
+func snapshotState(state *State) stateSnapshot {
+	return stateSnapshot{
+		hasPlan:       state.Plan != nil,
+		pastSteps:     len(state.PastSteps),
+		retrievedDocs: len(state.RetrievedDocs),
+		rerankedDocs:  len(state.RerankedDocs),
+		hasAnswer:     state.FinalAnswer != "",
+		decision:      state.Decision,
+	}
+}
+
+// deltaSince computes the changes from before to the current state.
+func deltaSince(before stateSnapshot, state *State) *StateDelta {
+	return &StateDelta{
+		PlanCreated:    !before.hasPlan && state.Plan != nil,
+		PastStepsAdded: len(state.PastSteps) - before.pastSteps,
+		RetrievedDocs:  len(state.RetrievedDocs) - before.retrievedDocs,
+		RerankedDocs:   len(state.RerankedDocs) - before.rerankedDocs,
+		AnswerProduced: !before.hasAnswer && state.FinalAnswer != "",
+		ShouldContinue: state.ShouldContinue,
+	}
+}
+
+// progressEvent returns an event of the given type populated with the
+// state's current step progress.
+func progressEvent(eventType EventType, node string, state *State) Event {
+	event := Event{
+		Type:         eventType,
+		Time:         time.Now(),
+		Node:         node,
+		StepPosition: -1,
+	}
+
+	if state.Plan == nil {
+		return event
+	}
+	event.TotalSteps = len(state.Plan.Steps)
+
+	if step := state.CurrentStep(); step != nil {
+		event.StepPosition = state.CurrentStepIndex
+		event.SubQuestion = step.SubQuestion
+	}
+	if state.Retrieval != nil && state.Retrieval.StepIndex == state.CurrentStepIndex {
+		event.Strategy = state.Retrieval.Strategy
+	}
+
+	return event
+}
+
+// stepEvent returns an EventStepCompleted event for a past step.
+func stepEvent(node string, state *State, past PastStep) Event {
+	event := progressEvent(EventStepCompleted, node, state)
+	event.StepPosition = state.positionOf(past.Step.Index)
+	event.SubQuestion = past.Step.SubQuestion
+	event.Strategy = past.Strategy
+	event.Duration = time.Duration(past.ExecutionTimeMs) * time.Millisecond
+	event.PastStep = &past
+	return event
+}
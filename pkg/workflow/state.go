@@ -6,6 +6,9 @@
 package workflow
 
 import (
+	"encoding/json"
+	"errors"
+
 	"deep-thinking-agent/pkg/schema"
 	"deep-thinking-agent/pkg/vectorstore"
 )
@@ -13,7 +16,12 @@ import (
 // State represents the complete state of the deep thinking RAG workflow.
 // This state is passed between nodes in the execution graph and accumulates
 // information as the workflow progresses through its steps.
+// State can be serialized to JSON; Error is stored as its message.
 type State struct {
+	// RunID identifies the run for checkpointing; the executor assigns one
+	// if it is empty and a checkpoint store is configured
+	RunID string
+
 	// Original query context
 	OriginalQuestion string
 	Plan             *Plan
@@ -23,6 +31,9 @@ type State struct {
 	PastSteps        []PastStep
 	MaxIterations    int // Safety limit to prevent infinite loops
 
+	// Retrieval context for the current step (query, strategy, filters)
+	Retrieval *RetrievalContext
+
 	// Retrieval results (current step)
 	RetrievedDocs []vectorstore.Document
 	RerankedDocs  []vectorstore.Document
@@ -30,14 +41,51 @@ type State struct {
 	// Synthesis
 	SynthesizedContext string
 	FinalAnswer        string
+	Citations          []Citation // Sources cited by [n] markers in FinalAnswer
+
+	// Verification checks FinalAnswer's claims against the retrieved evidence
+	Verification *VerificationReport
 
 	// Schema context (enables schema-aware retrieval)
 	RelevantSchemas map[string]*schema.DocumentSchema // DocID -> Schema
 	ActiveFilters   *SchemaFilters
 
 	// Workflow control
-	ShouldContinue bool  // Policy agent sets this
-	Error          error // Any error encountered during workflow
+	ShouldContinue bool            // Policy agent sets this
+	Decision       *PolicyDecision // Most recent policy decision, if any
+	Error          error           // Any error encountered during workflow
+}
+
+// MarshalJSON encodes the state, storing Error as a string.
+func (s *State) MarshalJSON() ([]byte, error) {
+	type alias State
+	encoded := struct {
+		*alias
+		Error string `json:",omitempty"`
+	}{alias: (*alias)(s)}
+	if s.Error != nil {
+		encoded.Error = s.Error.Error()
+	}
+	return json.Marshal(encoded)
+}
+
+// UnmarshalJSON decodes a state written by MarshalJSON.
+// Error is restored as a plain error carrying the original message.
+func (s *State) UnmarshalJSON(data []byte) error {
+	type alias State
+	decoded := struct {
+		*alias
+		Error string `json:",omitempty"`
+	}{alias: (*alias)(s)}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	s.Error = nil
+	if decoded.Error != "" {
+		s.Error = errors.New(decoded.Error)
+	}
+	return nil
 }
 
 // Plan represents the decomposed query execution plan.
@@ -49,6 +97,94 @@ type Plan struct {
 
 	// Reasoning explains why this plan was chosen
 	Reasoning string
+
+	// Revision counts how many times the plan has been revised during the run
+	Revision int
+}
+
+// Citation links a [n] marker in the final answer to the source chunk it cites.
+type Citation struct {
+	// Marker is the n of the [n] marker in the answer
+	Marker int
+
+	// ChunkID is the vector store ID of the cited chunk
+	ChunkID string
+
+	// DocID identifies the source document
+	DocID string
+
+	// SectionID and SectionTitle identify the document section, if known
+	SectionID    string
+	SectionTitle string
+
+	// StartPos and EndPos are the chunk's character span in the source
+	// document; both are zero when the span is unknown
+	StartPos int
+	EndPos   int
+
+	// Score is the chunk's relevance score at retrieval
+	Score float32
+
+	// SourceURL is set for web search results
+	SourceURL string
+}
+
+// ClaimStatus is the verdict on a claim checked against the evidence.
+type ClaimStatus string
+
+const (
+	// ClaimSupported means the evidence states or directly implies the claim
+	ClaimSupported ClaimStatus = "supported"
+
+	// ClaimUnsupported means the evidence neither supports nor contradicts the claim
+	ClaimUnsupported ClaimStatus = "unsupported"
+
+	// ClaimContradicted means the evidence conflicts with the claim
+	ClaimContradicted ClaimStatus = "contradicted"
+)
+
+// ClaimVerification is the verdict on a single atomic claim of the answer.
+type ClaimVerification struct {
+	// Claim is the atomic statement taken from the answer
+	Claim string
+
+	// Status is the verdict on the claim
+	Status ClaimStatus
+
+	// Evidence lists the 1-based numbers of the evidence excerpts used
+	Evidence []int
+
+	// Explanation briefly justifies the verdict
+	Explanation string
+}
+
+// VerificationReport records how well the final answer is supported by the
+// retrieved evidence.
+type VerificationReport struct {
+	// Claims are the verdicts for each claim, in answer order
+	Claims []ClaimVerification
+
+	// Corrected is set once a corrective retrieval loop has run for
+	// unsupported or contradicted claims
+	Corrected bool
+
+	// Error describes why verification could not be completed, if it failed
+	Error string
+}
+
+// Failed returns the claims that are unsupported or contradicted.
+func (r *VerificationReport) Failed() []ClaimVerification {
+	if r == nil {
+		return nil
+	}
+
+	var failed []ClaimVerification
+	for _, claim := range r.Claims {
+		if claim.Status != ClaimSupported {
+			failed = append(failed, claim)
+		}
+	}
+	return failed
 }
 
 // PlanStep represents a single step in the execution plan.
@@ -83,6 +219,9 @@ type PastStep struct {
 	// Documents retrieved during this step
 	RetrievedDocs []vectorstore.Document
 
+	// Strategy is the retrieval strategy used for this step
+	Strategy RetrievalStrategy
+
 	// Summary of findings from this step
 	Summary string
 
@@ -146,14 +285,45 @@ const (
 
 	// StrategySchemaFiltered uses schema metadata for targeted retrieval
 	StrategySchemaFiltered RetrievalStrategy = "schema_filtered"
+
+	// StrategyWebSearch queries an external web search provider
+	StrategyWebSearch RetrievalStrategy = "web_search"
 )
 
+// QueryVariantKind describes how a query variant was derived.
+type QueryVariantKind string
+
+const (
+	// VariantParaphrase restates the query in different words
+	VariantParaphrase QueryVariantKind = "paraphrase"
+
+	// VariantKeywords reduces the query to its key terms
+	VariantKeywords QueryVariantKind = "keywords"
+
+	// VariantHyDE is a hypothetical answer passage, searched by embedding
+	VariantHyDE QueryVariantKind = "hyde"
+)
+
+// QueryVariant is an alternative form of a step's query. Retrieval searches
+// each variant alongside the query and fuses the results.
+type QueryVariant struct {
+	Kind QueryVariantKind
+	Text string
+}
+
 // RetrievalContext provides context for retrieval operations.
 // This is used by retrieval nodes to understand what to search for and how.
 type RetrievalContext struct {
+	// StepIndex is the plan step this context belongs to
+	StepIndex int
+
 	// Query is the search query (may be rewritten from original)
 	Query string
 
+	// Variants are additional forms of Query to search, if the rewriter
+	// expands queries
+	Variants []QueryVariant
+
 	// Strategy indicates which retrieval approach to use
 	Strategy RetrievalStrategy
 
@@ -163,9 +333,18 @@ type RetrievalContext struct {
 	// SchemaFilters constrains the search using schema metadata
 	SchemaFilters *SchemaFilters
 
+	// FiltersFromHint is true when SchemaFilters were narrowed from the
+	// step's schema hint. Retrieval falls back to State.ActiveFilters if the
+	// narrowed search finds nothing.
+	FiltersFromHint bool
+
 	// RerankerTopN is the number of results to keep after reranking
 	RerankerTopN int
 
+	// RerankError describes why relevance scoring failed, if it did; the
+	// reranked documents are then the top results by retrieval score
+	RerankError string
+
 	// IncludeHistory indicates if past step findings should influence retrieval
 	IncludeHistory bool
 }
@@ -184,6 +363,10 @@ type PolicyDecision struct {
 	// SuggestedAction provides guidance if continuing
 	// Example: "focus on external sources", "need more specific data"
 	SuggestedAction string
+
+	// Replan asks the planner to revise the remaining steps before the
+	// workflow continues, because findings so far revealed a gap
+	Replan bool
 }
 
 // NewState creates a new workflow state initialized with defaults.
@@ -216,6 +399,16 @@ func (s *State) CurrentStep() *PlanStep {
 	return &s.Plan.Steps[s.CurrentStepIndex]
 }
 
+// SetPlan replaces the plan and points CurrentStepIndex at its first step
+// that has not completed. The per-step retrieval context is reset, since
+// step positions may have changed.
+func (s *State) SetPlan(plan *Plan) {
+	s.Plan = plan
+	s.Retrieval = nil
+	s.CurrentStepIndex = 0
+	s.advanceToNextPending()
+}
+
 // IsComplete returns true if all plan steps have been executed.
 func (s *State) IsComplete() bool {
 	if s.Plan == nil {
@@ -224,19 +417,145 @@ func (s *State) IsComplete() bool {
 	return s.CurrentStepIndex >= len(s.Plan.Steps)
 }
 
+// RemainingSteps returns the plan steps that have not completed, in plan
+// order. Steps that ran out of order (in parallel, or before a revision
+// moved them) are excluded wherever they sit in the plan.
+func (s *State) RemainingSteps() []PlanStep {
+	if s.Plan == nil {
+		return nil
+	}
+
+	completed := s.completedSteps()
+	var remaining []PlanStep
+	for _, step := range s.Plan.Steps {
+		if !completed[step.Index] {
+			remaining = append(remaining, step)
+		}
+	}
+	return remaining
+}
+
+// ReadySteps returns the positions of pending plan steps whose dependencies
+// have all completed, in plan order. Dependencies refer to PlanStep.Index;
+// references to unknown steps are ignored. If pending steps remain but none
+// are ready (a dependency cycle), the first pending step is returned so the
+// workflow can still make progress.
+func (s *State) ReadySteps() []int {
+	if s.Plan == nil {
+		return nil
+	}
+
+	known := make(map[int]bool, len(s.Plan.Steps))
+	for _, step := range s.Plan.Steps {
+		known[step.Index] = true
+	}
+
+	completed := s.completedSteps()
+
+	ready := []int{}
+	firstPending := -1
+	for pos, step := range s.Plan.Steps {
+		if completed[step.Index] {
+			continue
+		}
+		if firstPending == -1 {
+			firstPending = pos
+		}
+
+		satisfied := true
+		for _, dep := range step.Dependencies {
+			if dep != step.Index && known[dep] && !completed[dep] {
+				satisfied = false
+				break
+			}
+		}
+		if satisfied {
+			ready = append(ready, pos)
+		}
+	}
+
+	if len(ready) == 0 && firstPending != -1 {
+		ready = append(ready, firstPending)
+	}
+
+	return ready
+}
+
+// positionOf returns the plan position of the step with the given index,
+// or -1 if the plan has no such step.
+func (s *State) positionOf(index int) int {
+	if s.Plan == nil {
+		return -1
+	}
+	for pos, step := range s.Plan.Steps {
+		if step.Index == index {
+			return pos
+		}
+	}
+	return -1
+}
+
+// completedSteps returns the set of plan step indices recorded in PastSteps.
+func (s *State) completedSteps() map[int]bool {
+	completed := make(map[int]bool, len(s.PastSteps))
+	for _, past := range s.PastSteps {
+		completed[past.Step.Index] = true
+	}
+	return completed
+}
+
+// forkForStep returns a copy of the state positioned at the given plan step.
+// The copy shares read-only data (plan, schemas) but has its own history slice
+// and per-step results, so it can be executed concurrently with other forks.
+func (s *State) forkForStep(position int) *State {
+	fork := *s
+	fork.CurrentStepIndex = position
+	fork.PastSteps = append([]PastStep(nil), s.PastSteps...)
+	fork.Retrieval = nil
+	fork.RetrievedDocs = nil
+	fork.RerankedDocs = nil
+	fork.SynthesizedContext = ""
+	return &fork
+}
+
+// advanceToNextPending points CurrentStepIndex at the first pending plan step,
+// or past the end of the plan if every step has completed.
+func (s *State) advanceToNextPending() {
+	if s.Plan == nil {
+		return
+	}
+
+	completed := s.completedSteps()
+	for pos, step := range s.Plan.Steps {
+		if !completed[step.Index] {
+			s.CurrentStepIndex = pos
+			return
+		}
+	}
+	s.CurrentStepIndex = len(s.Plan.Steps)
+}
+
 // HasReachedMaxIterations returns true if the safety limit has been hit.
 func (s *State) HasReachedMaxIterations() bool {
 	return len(s.PastSteps) >= s.MaxIterations
 }
 
-// GetRetrievalContext builds retrieval context from current state.
+// GetRetrievalContext returns the retrieval context for the current step.
+// The context is created on first access for each step and stored in State,
+// so changes made by one node (rewritten query, selected strategy) are seen
+// by the nodes that follow.
 func (s *State) GetRetrievalContext() *RetrievalContext {
 	currentStep := s.CurrentStep()
 	if currentStep == nil {
 		return nil
 	}
 
-	return &RetrievalContext{
+	if s.Retrieval != nil && s.Retrieval.StepIndex == s.CurrentStepIndex {
+		return s.Retrieval
+	}
+
+	s.Retrieval = &RetrievalContext{
+		StepIndex:      s.CurrentStepIndex,
 		Query:          currentStep.SubQuestion,
 		Strategy:       StrategyHybrid, // Default to hybrid
 		TopK:           10,
@@ -244,4 +563,5 @@ func (s *State) GetRetrievalContext() *RetrievalContext {
 		SchemaFilters:  s.ActiveFilters,
 		IncludeHistory: len(s.PastSteps) > 0,
 	}
+	return s.Retrieval
 }
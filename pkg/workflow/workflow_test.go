@@ -8,6 +8,9 @@ package workflow_test
 import (
 	"context"
 	"errors"
+	"path/filepath"
+	"reflect"
+	"sync"
 	"testing"
 	"time"
 
@@ -27,13 +30,27 @@ func (m *mockNode) Name() string {
 	return m.name
 }
 
-func (m *mockNode) Execute(state *workflow.State) (*workflow.NodeResult, error) {
+func (m *mockNode) Execute(ctx context.Context, state *workflow.State) (*workflow.NodeResult, error) {
 	if m.executeFunc != nil {
 		return m.executeFunc(state)
 	}
 	return &workflow.NodeResult{UpdatedState: state}, nil
 }
 
+// ctxNode is a mock node whose behavior depends on the execution context.
+type ctxNode struct {
+	name        string
+	executeFunc func(ctx context.Context, state *workflow.State) (*workflow.NodeResult, error)
+}
+
+func (m *ctxNode) Name() string {
+	return m.name
+}
+
+func (m *ctxNode) Execute(ctx context.Context, state *workflow.State) (*workflow.NodeResult, error) {
+	return m.executeFunc(ctx, state)
+}
+
 // ============================================================================
 // Graph Tests
 // ============================================================================
@@ -184,6 +201,58 @@ func TestGraph_SetStart(t *testing.T) {
 	})
 }
 
+func TestGraph_SetFinish(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		graph := workflow.NewGraph()
+		graph.AddNode(&mockNode{name: "end"})
+		err := graph.SetFinish("end")
+		if err != nil {
+			t.Errorf("SetFinish() error = %v", err)
+		}
+		if graph.GetFinishNode() != "end" {
+			t.Errorf("GetFinishNode() = %v, want end", graph.GetFinishNode())
+		}
+	})
+
+	t.Run("nonexistent node", func(t *testing.T) {
+		graph := workflow.NewGraph()
+		err := graph.SetFinish("nonexistent")
+		if err == nil {
+			t.Error("SetFinish should error on nonexistent node")
+		}
+		if err != nil && err.Error() != "finish node nonexistent does not exist" {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestGraph_SetStepNodes(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		graph := workflow.NewGraph()
+		graph.AddNode(&mockNode{name: "a"})
+		graph.AddNode(&mockNode{name: "b"})
+		if err := graph.SetStepNodes("a", "b"); err != nil {
+			t.Fatalf("SetStepNodes() error = %v", err)
+		}
+		steps := graph.GetStepNodes()
+		if len(steps) != 2 || steps[0] != "a" || steps[1] != "b" {
+			t.Errorf("GetStepNodes() = %v, want [a b]", steps)
+		}
+	})
+
+	t.Run("nonexistent node", func(t *testing.T) {
+		graph := workflow.NewGraph()
+		graph.AddNode(&mockNode{name: "a"})
+		err := graph.SetStepNodes("a", "missing")
+		if err == nil || err.Error() != "step node missing does not exist" {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if len(graph.GetStepNodes()) != 0 {
+			t.Error("step nodes should not be set on error")
+		}
+	})
+}
+
 func TestGraph_GetNode(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		graph := workflow.NewGraph()
@@ -210,6 +279,53 @@ func TestGraph_GetNode(t *testing.T) {
 	})
 }
 
+func TestGraph_AddConditionalEdge(t *testing.T) {
+	route := func(*workflow.State) string { return "b" }
+
+	newGraph := func() *workflow.Graph {
+		graph := workflow.NewGraph()
+		graph.AddNode(&mockNode{name: "a"})
+		graph.AddNode(&mockNode{name: "b"})
+		graph.AddNode(&mockNode{name: "c"})
+		return graph
+	}
+
+	graph := newGraph()
+	if err := graph.AddConditionalEdge("a", route, "b", "c"); err != nil {
+		t.Fatalf("AddConditionalEdge() error = %v", err)
+	}
+	if next := graph.GetNextNodes("a"); !reflect.DeepEqual(next, []string{"b", "c"}) {
+		t.Errorf("GetNextNodes() = %v, want [b c]", next)
+	}
+	if err := graph.AddEdge("a", "b"); err == nil {
+		t.Error("AddEdge() should fail on a node with a conditional edge")
+	}
+	if err := graph.AddConditionalEdge("a", route, "b"); err == nil {
+		t.Error("AddConditionalEdge() should fail on a second conditional edge")
+	}
+
+	graph = newGraph()
+	graph.AddEdge("a", "b")
+	if err := graph.AddConditionalEdge("a", route, "c"); err == nil {
+		t.Error("AddConditionalEdge() should fail on a node with plain edges")
+	}
+
+	for name, add := range map[string]func(*workflow.Graph) error{
+		"unknown from":   func(g *workflow.Graph) error { return g.AddConditionalEdge("x", route, "b") },
+		"unknown target": func(g *workflow.Graph) error { return g.AddConditionalEdge("a", route, "b", "x") },
+		"no targets":     func(g *workflow.Graph) error { return g.AddConditionalEdge("a", route) },
+		"nil route":      func(g *workflow.Graph) error { return g.AddConditionalEdge("a", nil, "b") },
+	} {
+		if err := add(newGraph()); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	if err := newGraph().AddNode(&mockNode{name: workflow.End}); err == nil {
+		t.Error("AddNode() should reject the reserved End name")
+	}
+}
+
 func TestGraph_GetNextNodes(t *testing.T) {
 	graph := workflow.NewGraph()
 	graph.AddNode(&mockNode{name: "node1"})
@@ -406,6 +522,61 @@ func TestExecutor_Execute(t *testing.T) {
 		}
 	})
 
+	t.Run("timeout cancels in-flight node", func(t *testing.T) {
+		graph := workflow.NewGraph()
+		graph.AddNode(&ctxNode{
+			name: "slow",
+			executeFunc: func(ctx context.Context, state *workflow.State) (*workflow.NodeResult, error) {
+				select {
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				case <-time.After(5 * time.Second):
+					return &workflow.NodeResult{UpdatedState: state}, nil
+				}
+			},
+		})
+		graph.SetStart("slow")
+
+		start := time.Now()
+		executor := workflow.NewExecutor(graph, &workflow.ExecutorConfig{Timeout: 50 * time.Millisecond})
+		_, err := executor.Execute(ctx, workflow.NewState("test question"))
+		elapsed := time.Since(start)
+
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Execute() error = %v, want deadline exceeded", err)
+		}
+		if elapsed > time.Second {
+			t.Errorf("node was not cancelled, execution took %v", elapsed)
+		}
+	})
+
+	t.Run("caller cancellation reaches parallel steps", func(t *testing.T) {
+		graph := workflow.NewGraph()
+		graph.AddNode(&ctxNode{
+			name: "work",
+			executeFunc: func(ctx context.Context, state *workflow.State) (*workflow.NodeResult, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+		})
+		graph.SetStart("work")
+		graph.SetStepNodes("work")
+
+		state := workflow.NewState("test question")
+		state.Plan = &workflow.Plan{Steps: []workflow.PlanStep{
+			{Index: 0, SubQuestion: "a"},
+			{Index: 1, SubQuestion: "b"},
+		}}
+
+		cancelCtx, cancel := context.WithCancel(ctx)
+		time.AfterFunc(20*time.Millisecond, cancel)
+
+		executor := workflow.NewExecutor(graph, &workflow.ExecutorConfig{MaxParallelSteps: 2})
+		if _, err := executor.Execute(cancelCtx, state); !errors.Is(err, context.Canceled) {
+			t.Errorf("Execute() error = %v, want context canceled", err)
+		}
+	})
+
 	t.Run("multi-node workflow", func(t *testing.T) {
 		graph := workflow.NewGraph()
 
@@ -599,8 +770,8 @@ func TestExecutor_Execute(t *testing.T) {
 			name: "node1",
 			executeFunc: func(state *workflow.State) (*workflow.NodeResult, error) {
 				callCount++
-				// Explicitly go to "finish"
-				return &workflow.NodeResult{UpdatedState: state, NextNode: "finish"}, nil
+				// Explicitly end the loop
+				return &workflow.NodeResult{UpdatedState: state, NextNode: workflow.End}, nil
 			},
 		}
 
@@ -654,98 +825,895 @@ func TestExecutor_Execute(t *testing.T) {
 	})
 }
 
-func TestExecutor_ExecuteStep(t *testing.T) {
+func TestExecutor_ConditionalRouting(t *testing.T) {
 	ctx := context.Background()
 
-	t.Run("success", func(t *testing.T) {
+	t.Run("custom node names", func(t *testing.T) {
 		graph := workflow.NewGraph()
-		executed := false
-		node := &mockNode{
-			name: "test",
+		executionOrder := []string{}
+		record := func(name string, update func(*workflow.State)) *mockNode {
+			return &mockNode{
+				name: name,
+				executeFunc: func(state *workflow.State) (*workflow.NodeResult, error) {
+					executionOrder = append(executionOrder, name)
+					if update != nil {
+						update(state)
+					}
+					return &workflow.NodeResult{UpdatedState: state}, nil
+				},
+			}
+		}
+
+		graph.AddNode(record("search", func(state *workflow.State) {
+			state.AddPastStep(workflow.PastStep{Summary: "found"})
+		}))
+		graph.AddNode(record("judge", nil))
+		graph.AddNode(record("answer", nil))
+		graph.AddEdge("search", "judge")
+		err := graph.AddConditionalEdge("judge", func(state *workflow.State) string {
+			if len(state.PastSteps) < 2 {
+				return "search"
+			}
+			return "answer"
+		}, "search", "answer")
+		if err != nil {
+			t.Fatal(err)
+		}
+		graph.SetStart("search")
+		graph.SetFinish("answer")
+
+		if _, err := workflow.NewExecutor(graph, nil).Execute(ctx, workflow.NewState("test")); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+
+		want := []string{"search", "judge", "search", "judge", "answer"}
+		if !reflect.DeepEqual(executionOrder, want) {
+			t.Errorf("execution order = %v, want %v", executionOrder, want)
+		}
+	})
+
+	t.Run("route must return a target", func(t *testing.T) {
+		graph := workflow.NewGraph()
+		graph.AddNode(&mockNode{name: "a"})
+		graph.AddNode(&mockNode{name: "b"})
+		graph.AddNode(&mockNode{name: "c"})
+		graph.AddConditionalEdge("a", func(*workflow.State) string { return "c" }, "b")
+		graph.SetStart("a")
+
+		if _, err := workflow.NewExecutor(graph, nil).Execute(ctx, workflow.NewState("test")); err == nil {
+			t.Error("Execute() should fail when a route returns a node that is not a target")
+		}
+	})
+
+	t.Run("unknown next node", func(t *testing.T) {
+		graph := workflow.NewGraph()
+		graph.AddNode(&mockNode{
+			name: "a",
 			executeFunc: func(state *workflow.State) (*workflow.NodeResult, error) {
-				executed = true
-				state.FinalAnswer = "done"
+				return &workflow.NodeResult{UpdatedState: state, NextNode: "finish"}, nil
+			},
+		})
+		graph.SetStart("a")
+
+		if _, err := workflow.NewExecutor(graph, nil).Execute(ctx, workflow.NewState("test")); err == nil {
+			t.Error("Execute() should fail when a node routes to an unknown node")
+		}
+	})
+
+	t.Run("completed plan ends the loop at the step pipeline", func(t *testing.T) {
+		graph := workflow.NewGraph()
+		steps := 0
+		graph.AddNode(&mockNode{
+			name: "plan",
+			executeFunc: func(state *workflow.State) (*workflow.NodeResult, error) {
+				state.Plan = &workflow.Plan{Steps: []workflow.PlanStep{{Index: 0}}}
+				return &workflow.NodeResult{UpdatedState: state}, nil
+			},
+		})
+		graph.AddNode(&mockNode{
+			name: "step",
+			executeFunc: func(state *workflow.State) (*workflow.NodeResult, error) {
+				steps++
+				state.AddPastStep(workflow.PastStep{Step: *state.CurrentStep()})
+				state.IncrementStep()
 				return &workflow.NodeResult{UpdatedState: state}, nil
 			},
+		})
+		graph.AddEdge("plan", "step")
+		graph.AddEdge("step", "step")
+		graph.SetStart("plan")
+		graph.SetStepNodes("step")
+
+		if _, err := workflow.NewExecutor(graph, nil).Execute(ctx, workflow.NewState("test")); err != nil {
+			t.Fatalf("Execute() error = %v", err)
 		}
-		graph.AddNode(node)
+		if steps != 1 {
+			t.Errorf("step ran %d times, want 1", steps)
+		}
+	})
+}
+
+func TestExecutor_FinishNode(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("runs once after policy finishes", func(t *testing.T) {
+		graph := workflow.NewGraph()
+		executionOrder := []string{}
+
+		policy := &mockNode{
+			name: "policy",
+			executeFunc: func(state *workflow.State) (*workflow.NodeResult, error) {
+				executionOrder = append(executionOrder, "policy")
+				state.ShouldContinue = false
+				return &workflow.NodeResult{UpdatedState: state, NextNode: workflow.End}, nil
+			},
+		}
+		synthesizer := &mockNode{
+			name: "synthesizer",
+			executeFunc: func(state *workflow.State) (*workflow.NodeResult, error) {
+				executionOrder = append(executionOrder, "synthesizer")
+				state.FinalAnswer = "answer"
+				return &workflow.NodeResult{UpdatedState: state}, nil
+			},
+		}
+
+		graph.AddNode(policy)
+		graph.AddNode(synthesizer)
+		graph.AddEdge("policy", "synthesizer")
+		graph.SetStart("policy")
+		graph.SetFinish("synthesizer")
 
 		executor := workflow.NewExecutor(graph, nil)
-		state := workflow.NewState("test question")
-		result, err := executor.ExecuteStep(ctx, state, "test")
+		result, err := executor.Execute(ctx, workflow.NewState("test"))
 		if err != nil {
-			t.Errorf("ExecuteStep() error = %v", err)
+			t.Fatalf("Execute() error = %v", err)
 		}
-		if !executed {
-			t.Error("node was not executed")
+		if result.FinalAnswer != "answer" {
+			t.Errorf("FinalAnswer = %v, want answer", result.FinalAnswer)
 		}
-		if result.FinalAnswer != "done" {
-			t.Errorf("FinalAnswer = %v, want done", result.FinalAnswer)
+		if len(executionOrder) != 2 || executionOrder[1] != "synthesizer" {
+			t.Errorf("expected [policy, synthesizer], got %v", executionOrder)
 		}
 	})
 
-	t.Run("nonexistent node", func(t *testing.T) {
+	t.Run("runs after max iterations", func(t *testing.T) {
 		graph := workflow.NewGraph()
+		finished := false
+
+		loop := &mockNode{
+			name: "loop",
+			executeFunc: func(state *workflow.State) (*workflow.NodeResult, error) {
+				state.AddPastStep(workflow.PastStep{Summary: "step"})
+				return &workflow.NodeResult{UpdatedState: state, NextNode: "loop"}, nil
+			},
+		}
+		finish := &mockNode{
+			name: "finish_node",
+			executeFunc: func(state *workflow.State) (*workflow.NodeResult, error) {
+				finished = true
+				return &workflow.NodeResult{UpdatedState: state}, nil
+			},
+		}
+
+		graph.AddNode(loop)
+		graph.AddNode(finish)
+		graph.SetStart("loop")
+		graph.SetFinish("finish_node")
+
 		executor := workflow.NewExecutor(graph, nil)
-		state := workflow.NewState("test question")
-		_, err := executor.ExecuteStep(ctx, state, "nonexistent")
-		if err == nil {
-			t.Error("ExecuteStep should error on nonexistent node")
+		state := workflow.NewState("test")
+		state.MaxIterations = 2
+		if _, err := executor.Execute(ctx, state); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if !finished {
+			t.Error("finish node was not executed")
 		}
 	})
 
-	t.Run("node execution error", func(t *testing.T) {
+	t.Run("not repeated when loop ends on it", func(t *testing.T) {
 		graph := workflow.NewGraph()
-		node := &mockNode{
-			name: "error_node",
+		callCount := 0
+
+		start := &mockNode{name: "start"}
+		finish := &mockNode{
+			name: "end",
 			executeFunc: func(state *workflow.State) (*workflow.NodeResult, error) {
-				return nil, errors.New("execution failed")
+				callCount++
+				return &workflow.NodeResult{UpdatedState: state}, nil
 			},
 		}
-		graph.AddNode(node)
+
+		graph.AddNode(start)
+		graph.AddNode(finish)
+		graph.AddEdge("start", "end")
+		graph.SetStart("start")
+		graph.SetFinish("end")
 
 		executor := workflow.NewExecutor(graph, nil)
-		state := workflow.NewState("test question")
-		_, err := executor.ExecuteStep(ctx, state, "error_node")
-		if err == nil {
-			t.Error("ExecuteStep should propagate node execution error")
+		if _, err := executor.Execute(ctx, workflow.NewState("test")); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if callCount != 1 {
+			t.Errorf("expected finish node to run once, got %d", callCount)
+		}
+	})
+
+	t.Run("pipeline can return to the loop", func(t *testing.T) {
+		graph := workflow.NewGraph()
+		executionOrder := []string{}
+
+		record := func(name string, next func() string) *mockNode {
+			return &mockNode{
+				name: name,
+				executeFunc: func(state *workflow.State) (*workflow.NodeResult, error) {
+					executionOrder = append(executionOrder, name)
+					result := &workflow.NodeResult{UpdatedState: state}
+					if next != nil {
+						result.NextNode = next()
+					}
+					return result, nil
+				},
+			}
+		}
+		checks := 0
+		graph.AddNode(record("research", func() string { return workflow.End }))
+		graph.AddNode(record("synthesizer", nil))
+		graph.AddNode(record("verifier", func() string {
+			// Send the run back once
+			checks++
+			if checks == 1 {
+				return "research"
+			}
+			return ""
+		}))
+		graph.SetStart("research")
+		if err := graph.SetFinish("synthesizer", "verifier"); err != nil {
+			t.Fatal(err)
+		}
+
+		executor := workflow.NewExecutor(graph, nil)
+		if _, err := executor.Execute(ctx, workflow.NewState("test")); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+
+		want := []string{"research", "synthesizer", "verifier", "research", "synthesizer", "verifier"}
+		if !reflect.DeepEqual(executionOrder, want) {
+			t.Errorf("execution order = %v, want %v", executionOrder, want)
+		}
+	})
+
+	t.Run("finish node cannot route to a finish node", func(t *testing.T) {
+		graph := workflow.NewGraph()
+		graph.AddNode(&mockNode{name: "start"})
+		graph.AddNode(&mockNode{
+			name: "end",
+			executeFunc: func(state *workflow.State) (*workflow.NodeResult, error) {
+				return &workflow.NodeResult{UpdatedState: state, NextNode: "end"}, nil
+			},
+		})
+		graph.SetStart("start")
+		graph.SetFinish("end")
+
+		executor := workflow.NewExecutor(graph, nil)
+		if _, err := executor.Execute(ctx, workflow.NewState("test")); err == nil {
+			t.Error("Execute should reject a finish node routing to a finish node")
+		}
+	})
+
+	t.Run("finish node error", func(t *testing.T) {
+		graph := workflow.NewGraph()
+		graph.AddNode(&mockNode{name: "start"})
+		graph.AddNode(&mockNode{
+			name: "end",
+			executeFunc: func(state *workflow.State) (*workflow.NodeResult, error) {
+				return nil, errors.New("synthesis failed")
+			},
+		})
+		graph.SetStart("start")
+		graph.SetFinish("end")
+
+		executor := workflow.NewExecutor(graph, nil)
+		if _, err := executor.Execute(ctx, workflow.NewState("test")); err == nil {
+			t.Error("Execute should propagate finish node error")
 		}
 	})
 }
 
-// ============================================================================
-// State Tests
-// ============================================================================
+// stepPipeline builds a two-node step pipeline ("work" then "record") with a
+// policy node that loops until the plan is complete. The work node calls
+// onWork with the sub-question of the step being executed.
+func stepPipeline(t *testing.T, onWork func(subQuestion string)) *workflow.Graph {
+	t.Helper()
+	graph := workflow.NewGraph()
 
-func TestNewState(t *testing.T) {
-	state := workflow.NewState("test question")
-	if state == nil {
-		t.Fatal("NewState returned nil")
-	}
-	if state.OriginalQuestion != "test question" {
-		t.Errorf("OriginalQuestion = %v, want 'test question'", state.OriginalQuestion)
-	}
-	if state.CurrentStepIndex != 0 {
-		t.Error("CurrentStepIndex should be 0")
-	}
-	if !state.ShouldContinue {
-		t.Error("ShouldContinue should be true")
-	}
-	if state.MaxIterations != 10 {
-		t.Errorf("MaxIterations = %v, want 10", state.MaxIterations)
+	graph.AddNode(&mockNode{
+		name: "work",
+		executeFunc: func(state *workflow.State) (*workflow.NodeResult, error) {
+			onWork(state.CurrentStep().SubQuestion)
+			state.SynthesizedContext = state.CurrentStep().SubQuestion
+			return &workflow.NodeResult{UpdatedState: state}, nil
+		},
+	})
+	graph.AddNode(&mockNode{
+		name: "record",
+		executeFunc: func(state *workflow.State) (*workflow.NodeResult, error) {
+			state.AddPastStep(workflow.PastStep{
+				Step:    *state.CurrentStep(),
+				Summary: state.SynthesizedContext,
+			})
+			state.IncrementStep()
+			return &workflow.NodeResult{UpdatedState: state}, nil
+		},
+	})
+	graph.AddNode(&mockNode{
+		name: "policy",
+		executeFunc: func(state *workflow.State) (*workflow.NodeResult, error) {
+			state.ShouldContinue = !state.IsComplete()
+			return &workflow.NodeResult{UpdatedState: state}, nil
+		},
+	})
+
+	graph.AddEdge("work", "record")
+	graph.AddEdge("record", "policy")
+	graph.AddEdge("policy", "work")
+	graph.SetStart("work")
+	if err := graph.SetStepNodes("work", "record"); err != nil {
+		t.Fatalf("SetStepNodes() error = %v", err)
 	}
+	return graph
 }
 
-func TestState_CurrentStep(t *testing.T) {
-	state := workflow.NewState("test")
+func TestExecutor_ParallelSteps(t *testing.T) {
+	ctx := context.Background()
 
-	// No plan
-	if state.CurrentStep() != nil {
-		t.Error("CurrentStep should return nil when no plan")
-	}
+	t.Run("independent steps run concurrently and merge in plan order", func(t *testing.T) {
+		var mu sync.Mutex
+		running, maxRunning := 0, 0
 
-	// Plan with steps
-	state.Plan = &workflow.Plan{
+		graph := stepPipeline(t, func(string) {
+			mu.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mu.Unlock()
+
+			time.Sleep(20 * time.Millisecond)
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+		})
+
+		state := workflow.NewState("test")
+		state.Plan = &workflow.Plan{Steps: []workflow.PlanStep{
+			{Index: 0, SubQuestion: "a"},
+			{Index: 1, SubQuestion: "b"},
+			{Index: 2, SubQuestion: "c"},
+		}}
+
+		executor := workflow.NewExecutor(graph, &workflow.ExecutorConfig{MaxParallelSteps: 2})
+		result, err := executor.Execute(ctx, state)
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+
+		if maxRunning != 2 {
+			t.Errorf("max concurrent steps = %d, want 2", maxRunning)
+		}
+		if len(result.PastSteps) != 3 {
+			t.Fatalf("expected 3 past steps, got %d", len(result.PastSteps))
+		}
+		for i, want := range []string{"a", "b", "c"} {
+			if result.PastSteps[i].Summary != want {
+				t.Errorf("PastSteps[%d].Summary = %v, want %v", i, result.PastSteps[i].Summary, want)
+			}
+		}
+		if !result.IsComplete() {
+			t.Error("plan should be complete")
+		}
+	})
+
+	t.Run("dependent steps wait for their dependencies", func(t *testing.T) {
+		var mu sync.Mutex
+		order := []string{}
+
+		graph := stepPipeline(t, func(subQuestion string) {
+			mu.Lock()
+			order = append(order, subQuestion)
+			mu.Unlock()
+		})
+
+		state := workflow.NewState("test")
+		state.Plan = &workflow.Plan{Steps: []workflow.PlanStep{
+			{Index: 0, SubQuestion: "a"},
+			{Index: 1, SubQuestion: "b", Dependencies: []int{0}},
+			{Index: 2, SubQuestion: "c", Dependencies: []int{1}},
+		}}
+
+		executor := workflow.NewExecutor(graph, &workflow.ExecutorConfig{MaxParallelSteps: 3})
+		result, err := executor.Execute(ctx, state)
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+
+		if len(order) != 3 || order[0] != "a" || order[1] != "b" || order[2] != "c" {
+			t.Errorf("execution order = %v, want [a b c]", order)
+		}
+		if len(result.PastSteps) != 3 {
+			t.Errorf("expected 3 past steps, got %d", len(result.PastSteps))
+		}
+	})
+
+	t.Run("batch respects max iterations", func(t *testing.T) {
+		graph := stepPipeline(t, func(string) {})
+
+		state := workflow.NewState("test")
+		state.MaxIterations = 2
+		state.Plan = &workflow.Plan{Steps: []workflow.PlanStep{
+			{Index: 0, SubQuestion: "a"},
+			{Index: 1, SubQuestion: "b"},
+			{Index: 2, SubQuestion: "c"},
+		}}
+
+		executor := workflow.NewExecutor(graph, &workflow.ExecutorConfig{MaxParallelSteps: 3})
+		result, err := executor.Execute(ctx, state)
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if len(result.PastSteps) != 2 {
+			t.Errorf("expected 2 past steps, got %d", len(result.PastSteps))
+		}
+	})
+
+	t.Run("step error fails the workflow", func(t *testing.T) {
+		graph := workflow.NewGraph()
+		graph.AddNode(&mockNode{
+			name: "work",
+			executeFunc: func(state *workflow.State) (*workflow.NodeResult, error) {
+				return nil, errors.New("retrieval failed")
+			},
+		})
+		graph.SetStart("work")
+		graph.SetStepNodes("work")
+
+		state := workflow.NewState("test")
+		state.Plan = &workflow.Plan{Steps: []workflow.PlanStep{
+			{Index: 0, SubQuestion: "a"},
+			{Index: 1, SubQuestion: "b"},
+		}}
+
+		executor := workflow.NewExecutor(graph, &workflow.ExecutorConfig{MaxParallelSteps: 2})
+		if _, err := executor.Execute(ctx, state); err == nil {
+			t.Error("Execute should propagate step errors")
+		}
+	})
+}
+
+func TestExecutor_Observer(t *testing.T) {
+	ctx := context.Background()
+
+	newPlanState := func() *workflow.State {
+		state := workflow.NewState("test")
+		state.Plan = &workflow.Plan{Steps: []workflow.PlanStep{
+			{Index: 0, SubQuestion: "a"},
+			{Index: 1, SubQuestion: "b"},
+		}}
+		return state
+	}
+
+	t.Run("sequential execution reports node, step and policy events", func(t *testing.T) {
+		graph := workflow.NewGraph()
+		graph.AddNode(&mockNode{name: "work"})
+		graph.AddNode(&mockNode{
+			name: "record",
+			executeFunc: func(state *workflow.State) (*workflow.NodeResult, error) {
+				state.AddPastStep(workflow.PastStep{Step: *state.CurrentStep()})
+				state.IncrementStep()
+				return &workflow.NodeResult{UpdatedState: state}, nil
+			},
+		})
+		graph.AddNode(&mockNode{
+			name: "policy",
+			executeFunc: func(state *workflow.State) (*workflow.NodeResult, error) {
+				state.ShouldContinue = !state.IsComplete()
+				state.Decision = &workflow.PolicyDecision{ShouldContinue: state.ShouldContinue}
+				return &workflow.NodeResult{UpdatedState: state}, nil
+			},
+		})
+		graph.AddEdge("work", "record")
+		graph.AddEdge("record", "policy")
+		graph.AddEdge("policy", "work")
+		graph.SetStart("work")
+		graph.SetStepNodes("work", "record")
+
+		var events []workflow.Event
+		executor := workflow.NewExecutor(graph, &workflow.ExecutorConfig{
+			Observer: workflow.ObserverFunc(func(event workflow.Event) {
+				events = append(events, event)
+			}),
+		})
+
+		result, err := executor.Execute(ctx, newPlanState())
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+
+		var steps, decisions int
+		for _, event := range events {
+			switch event.Type {
+			case workflow.EventStepCompleted:
+				if event.StepPosition != steps {
+					t.Errorf("step event position = %d, want %d", event.StepPosition, steps)
+				}
+				if event.TotalSteps != 2 {
+					t.Errorf("step event TotalSteps = %d, want 2", event.TotalSteps)
+				}
+				if event.PastStep == nil {
+					t.Error("step event should include the past step")
+				}
+				steps++
+			case workflow.EventPolicyDecision:
+				if event.Decision == nil {
+					t.Error("policy event should include the decision")
+				}
+				decisions++
+			case workflow.EventNodeFinished:
+				if event.Node == "record" {
+					if event.Delta == nil || event.Delta.PastStepsAdded != 1 {
+						t.Errorf("record node delta = %+v, want 1 past step added", event.Delta)
+					}
+					if event.StepPosition < 0 {
+						t.Error("record node should report the step it completed")
+					}
+				}
+			}
+		}
+
+		if steps != 2 {
+			t.Errorf("step events = %d, want 2", steps)
+		}
+		if decisions != 2 {
+			t.Errorf("policy events = %d, want 2", decisions)
+		}
+		if events[0].Type != workflow.EventNodeStarted || events[0].Node != "work" {
+			t.Errorf("first event = %s %s, want node_started work", events[0].Type, events[0].Node)
+		}
+		for _, past := range result.PastSteps {
+			if past.ExecutionTimeMs < 0 {
+				t.Errorf("ExecutionTimeMs = %d, want >= 0", past.ExecutionTimeMs)
+			}
+		}
+	})
+
+	t.Run("node failure is reported", func(t *testing.T) {
+		graph := workflow.NewGraph()
+		graph.AddNode(&mockNode{
+			name: "fail",
+			executeFunc: func(state *workflow.State) (*workflow.NodeResult, error) {
+				return nil, errors.New("boom")
+			},
+		})
+		graph.SetStart("fail")
+
+		var finished *workflow.Event
+		executor := workflow.NewExecutor(graph, nil)
+		executor.SetObserver(workflow.ObserverFunc(func(event workflow.Event) {
+			if event.Type == workflow.EventNodeFinished {
+				finished = &event
+			}
+		}))
+
+		if _, err := executor.Execute(ctx, workflow.NewState("test")); err == nil {
+			t.Fatal("Execute should fail")
+		}
+		if finished == nil || finished.Err == nil {
+			t.Error("node_finished event should carry the node error")
+		}
+	})
+
+	t.Run("parallel steps never call the observer concurrently", func(t *testing.T) {
+		graph := stepPipeline(t, func(string) { time.Sleep(5 * time.Millisecond) })
+
+		var mu sync.Mutex
+		inside, steps := false, 0
+		executor := workflow.NewExecutor(graph, &workflow.ExecutorConfig{
+			MaxParallelSteps: 2,
+			Observer: workflow.ObserverFunc(func(event workflow.Event) {
+				mu.Lock()
+				if inside {
+					t.Error("observer called concurrently")
+				}
+				inside = true
+				mu.Unlock()
+
+				time.Sleep(time.Millisecond)
+				if event.Type == workflow.EventStepCompleted {
+					steps++
+					if event.Duration <= 0 {
+						t.Errorf("step duration = %v, want > 0", event.Duration)
+					}
+				}
+
+				mu.Lock()
+				inside = false
+				mu.Unlock()
+			}),
+		})
+
+		if _, err := executor.Execute(ctx, newPlanState()); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if steps != 2 {
+			t.Errorf("step events = %d, want 2", steps)
+		}
+	})
+
+	t.Run("context observer sees only its own run", func(t *testing.T) {
+		graph := workflow.NewGraph()
+		graph.AddNode(&mockNode{name: "work"})
+		graph.SetStart("work")
+
+		var executorEvents, runEvents int
+		executor := workflow.NewExecutor(graph, &workflow.ExecutorConfig{
+			Observer: workflow.ObserverFunc(func(workflow.Event) { executorEvents++ }),
+		})
+
+		runCtx := workflow.WithObserver(ctx, workflow.ObserverFunc(func(workflow.Event) { runEvents++ }))
+		if _, err := executor.Execute(runCtx, workflow.NewState("observed")); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if _, err := executor.Execute(ctx, workflow.NewState("unobserved")); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+
+		if runEvents != 2 || executorEvents != 4 {
+			t.Errorf("run observer got %d events, executor observer got %d; want 2 and 4", runEvents, executorEvents)
+		}
+	})
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := workflow.NewFileCheckpointStore(filepath.Join(t.TempDir(), "checkpoints"))
+	if err != nil {
+		t.Fatalf("NewFileCheckpointStore() error = %v", err)
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		state := workflow.NewState("question")
+		state.RunID = "run-1"
+		state.Plan = &workflow.Plan{Steps: []workflow.PlanStep{{Index: 0, SubQuestion: "a"}}}
+		state.PastSteps = []workflow.PastStep{{Step: state.Plan.Steps[0], Summary: "found a"}}
+		state.Decision = &workflow.PolicyDecision{ShouldContinue: true, Confidence: 0.5}
+		state.Error = errors.New("reflection failed")
+
+		if err := store.Save(ctx, &workflow.Checkpoint{RunID: "run-1", NextNode: "policy", LastNode: "reflector", State: state}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+
+		loaded, err := store.Load(ctx, "run-1")
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if loaded.NextNode != "policy" || loaded.LastNode != "reflector" {
+			t.Errorf("nodes = %s/%s, want policy/reflector", loaded.NextNode, loaded.LastNode)
+		}
+		if loaded.State.OriginalQuestion != "question" || loaded.State.RunID != "run-1" {
+			t.Errorf("unexpected state: %+v", loaded.State)
+		}
+		if len(loaded.State.PastSteps) != 1 || loaded.State.PastSteps[0].Summary != "found a" {
+			t.Errorf("PastSteps = %+v", loaded.State.PastSteps)
+		}
+		if loaded.State.Decision == nil || loaded.State.Decision.Confidence != 0.5 {
+			t.Errorf("Decision = %+v", loaded.State.Decision)
+		}
+		if loaded.State.Error == nil || loaded.State.Error.Error() != "reflection failed" {
+			t.Errorf("Error = %v, want reflection failed", loaded.State.Error)
+		}
+	})
+
+	t.Run("missing run", func(t *testing.T) {
+		if _, err := store.Load(ctx, "missing"); !errors.Is(err, workflow.ErrCheckpointNotFound) {
+			t.Errorf("Load() error = %v, want ErrCheckpointNotFound", err)
+		}
+	})
+
+	t.Run("invalid run ID", func(t *testing.T) {
+		for _, runID := range []string{"", "..", "../escape"} {
+			if _, err := store.Load(ctx, runID); err == nil {
+				t.Errorf("Load(%q) should fail", runID)
+			}
+		}
+	})
+}
+
+func TestExecutor_Resume(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := workflow.NewFileCheckpointStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileCheckpointStore() error = %v", err)
+	}
+
+	// The reflector fails on its first call, then succeeds
+	reflectorCalls := 0
+	newGraph := func() *workflow.Graph {
+		graph := workflow.NewGraph()
+		graph.AddNode(&mockNode{
+			name: "planner",
+			executeFunc: func(state *workflow.State) (*workflow.NodeResult, error) {
+				state.Plan = &workflow.Plan{Steps: []workflow.PlanStep{{Index: 0, SubQuestion: "a"}}}
+				return &workflow.NodeResult{UpdatedState: state}, nil
+			},
+		})
+		graph.AddNode(&mockNode{
+			name: "reflector",
+			executeFunc: func(state *workflow.State) (*workflow.NodeResult, error) {
+				reflectorCalls++
+				if reflectorCalls == 1 {
+					return nil, errors.New("LLM unavailable")
+				}
+				state.AddPastStep(workflow.PastStep{Step: *state.CurrentStep(), Summary: "done"})
+				state.IncrementStep()
+				return &workflow.NodeResult{UpdatedState: state}, nil
+			},
+		})
+		graph.AddNode(&mockNode{
+			name: "synthesizer",
+			executeFunc: func(state *workflow.State) (*workflow.NodeResult, error) {
+				state.FinalAnswer = "answer"
+				return &workflow.NodeResult{UpdatedState: state}, nil
+			},
+		})
+		graph.AddEdge("planner", "reflector")
+		graph.SetStart("planner")
+		graph.SetFinish("synthesizer")
+		return graph
+	}
+
+	executor := workflow.NewExecutor(newGraph(), &workflow.ExecutorConfig{Checkpoints: store})
+	state, err := executor.Execute(ctx, workflow.NewState("question"))
+	if err == nil {
+		t.Fatal("Execute should fail on the first reflector call")
+	}
+	if state == nil || state.Plan == nil {
+		t.Fatal("Execute should return the state reached before the failure")
+	}
+	if state.RunID == "" {
+		t.Fatal("Execute should assign a run ID when checkpointing")
+	}
+
+	checkpoint, err := store.Load(ctx, state.RunID)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if checkpoint.LastNode != "planner" || checkpoint.NextNode != "reflector" {
+		t.Errorf("checkpoint nodes = %s -> %s, want planner -> reflector", checkpoint.LastNode, checkpoint.NextNode)
+	}
+
+	// A fresh executor resumes from the checkpoint without replanning
+	resumed, err := workflow.NewExecutor(newGraph(), &workflow.ExecutorConfig{Checkpoints: store}).Resume(ctx, state.RunID)
+	if err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	if resumed.FinalAnswer != "answer" || len(resumed.PastSteps) != 1 {
+		t.Errorf("resumed state = %+v", resumed)
+	}
+
+	checkpoint, err = store.Load(ctx, state.RunID)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !checkpoint.Completed() {
+		t.Errorf("checkpoint should be completed, next node = %s", checkpoint.NextNode)
+	}
+
+	// Resuming a finished run returns its final state
+	again, err := executor.Resume(ctx, state.RunID)
+	if err != nil || again.FinalAnswer != "answer" {
+		t.Errorf("Resume() of finished run = %v, %v", again, err)
+	}
+
+	if _, err := workflow.NewExecutor(newGraph(), nil).Resume(ctx, state.RunID); err == nil {
+		t.Error("Resume should fail without a checkpoint store")
+	}
+}
+
+func TestExecutor_ExecuteStep(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		graph := workflow.NewGraph()
+		executed := false
+		node := &mockNode{
+			name: "test",
+			executeFunc: func(state *workflow.State) (*workflow.NodeResult, error) {
+				executed = true
+				state.FinalAnswer = "done"
+				return &workflow.NodeResult{UpdatedState: state}, nil
+			},
+		}
+		graph.AddNode(node)
+
+		executor := workflow.NewExecutor(graph, nil)
+		state := workflow.NewState("test question")
+		result, err := executor.ExecuteStep(ctx, state, "test")
+		if err != nil {
+			t.Errorf("ExecuteStep() error = %v", err)
+		}
+		if !executed {
+			t.Error("node was not executed")
+		}
+		if result.FinalAnswer != "done" {
+			t.Errorf("FinalAnswer = %v, want done", result.FinalAnswer)
+		}
+	})
+
+	t.Run("nonexistent node", func(t *testing.T) {
+		graph := workflow.NewGraph()
+		executor := workflow.NewExecutor(graph, nil)
+		state := workflow.NewState("test question")
+		_, err := executor.ExecuteStep(ctx, state, "nonexistent")
+		if err == nil {
+			t.Error("ExecuteStep should error on nonexistent node")
+		}
+	})
+
+	t.Run("node execution error", func(t *testing.T) {
+		graph := workflow.NewGraph()
+		node := &mockNode{
+			name: "error_node",
+			executeFunc: func(state *workflow.State) (*workflow.NodeResult, error) {
+				return nil, errors.New("execution failed")
+			},
+		}
+		graph.AddNode(node)
+
+		executor := workflow.NewExecutor(graph, nil)
+		state := workflow.NewState("test question")
+		_, err := executor.ExecuteStep(ctx, state, "error_node")
+		if err == nil {
+			t.Error("ExecuteStep should propagate node execution error")
+		}
+	})
+}
+
+// ============================================================================
+// State Tests
+// ============================================================================
+
+func TestNewState(t *testing.T) {
+	state := workflow.NewState("test question")
+	if state == nil {
+		t.Fatal("NewState returned nil")
+	}
+	if state.OriginalQuestion != "test question" {
+		t.Errorf("OriginalQuestion = %v, want 'test question'", state.OriginalQuestion)
+	}
+	if state.CurrentStepIndex != 0 {
+		t.Error("CurrentStepIndex should be 0")
+	}
+	if !state.ShouldContinue {
+		t.Error("ShouldContinue should be true")
+	}
+	if state.MaxIterations != 10 {
+		t.Errorf("MaxIterations = %v, want 10", state.MaxIterations)
+	}
+}
+
+func TestState_CurrentStep(t *testing.T) {
+	state := workflow.NewState("test")
+
+	// No plan
+	if state.CurrentStep() != nil {
+		t.Error("CurrentStep should return nil when no plan")
+	}
+
+	// Plan with steps
+	state.Plan = &workflow.Plan{
 		Steps: []workflow.PlanStep{
 			{Index: 0, SubQuestion: "step 0"},
 			{Index: 1, SubQuestion: "step 1"},
@@ -860,6 +1828,163 @@ func TestState_GetRetrievalContext(t *testing.T) {
 
 }
 
+func TestState_GetRetrievalContext_Persists(t *testing.T) {
+	state := workflow.NewState("test")
+	state.Plan = &workflow.Plan{
+		Steps: []workflow.PlanStep{
+			{SubQuestion: "first"},
+			{SubQuestion: "second"},
+		},
+	}
+
+	// Changes made by one caller are visible to the next
+	state.GetRetrievalContext().Strategy = workflow.StrategyKeyword
+	state.GetRetrievalContext().Query = "rewritten"
+
+	ctx := state.GetRetrievalContext()
+	if ctx.Strategy != workflow.StrategyKeyword {
+		t.Errorf("Strategy = %v, want keyword", ctx.Strategy)
+	}
+	if ctx.Query != "rewritten" {
+		t.Errorf("Query = %v, want rewritten", ctx.Query)
+	}
+	if state.Retrieval != ctx {
+		t.Error("retrieval context should be stored on state")
+	}
+
+	// Advancing the step starts a fresh context
+	state.IncrementStep()
+	ctx = state.GetRetrievalContext()
+	if ctx.StepIndex != 1 {
+		t.Errorf("StepIndex = %v, want 1", ctx.StepIndex)
+	}
+	if ctx.Query != "second" {
+		t.Errorf("Query = %v, want second", ctx.Query)
+	}
+	if ctx.Strategy != workflow.StrategyHybrid {
+		t.Errorf("Strategy = %v, want hybrid", ctx.Strategy)
+	}
+}
+
+func TestState_SetPlan(t *testing.T) {
+	state := workflow.NewState("test")
+	state.Plan = &workflow.Plan{Steps: []workflow.PlanStep{{Index: 0}, {Index: 1}}}
+	state.AddPastStep(workflow.PastStep{Step: state.Plan.Steps[0]})
+	state.IncrementStep()
+	state.GetRetrievalContext()
+
+	// Completed steps stay in place; the new step 3 runs before step 1
+	state.SetPlan(&workflow.Plan{Steps: []workflow.PlanStep{{Index: 0}, {Index: 3, SubQuestion: "new"}, {Index: 1}}, Revision: 1})
+
+	if state.CurrentStepIndex != 1 {
+		t.Errorf("CurrentStepIndex = %d, want 1", state.CurrentStepIndex)
+	}
+	if state.Retrieval != nil {
+		t.Error("retrieval context should be reset")
+	}
+	if ctx := state.GetRetrievalContext(); ctx.Query != "new" {
+		t.Errorf("Query = %q, want new", ctx.Query)
+	}
+}
+
+func TestState_RemainingSteps(t *testing.T) {
+	state := workflow.NewState("test")
+	if remaining := state.RemainingSteps(); remaining != nil {
+		t.Errorf("RemainingSteps() without a plan = %v, want nil", remaining)
+	}
+
+	state.Plan = &workflow.Plan{Steps: []workflow.PlanStep{{Index: 0}, {Index: 1}, {Index: 2}}}
+	state.AddPastStep(workflow.PastStep{Step: state.Plan.Steps[0]})
+	state.AddPastStep(workflow.PastStep{Step: state.Plan.Steps[2]})
+	state.IncrementStep()
+
+	remaining := state.RemainingSteps()
+	if len(remaining) != 1 || remaining[0].Index != 1 {
+		t.Errorf("RemainingSteps() = %+v, want only step 1", remaining)
+	}
+}
+
+func TestState_ReadySteps(t *testing.T) {
+	tests := []struct {
+		name     string
+		steps    []workflow.PlanStep
+		done     []int
+		expected []int
+	}{
+		{
+			name: "independent steps are all ready",
+			steps: []workflow.PlanStep{
+				{Index: 0}, {Index: 1}, {Index: 2},
+			},
+			expected: []int{0, 1, 2},
+		},
+		{
+			name: "dependent step waits",
+			steps: []workflow.PlanStep{
+				{Index: 0}, {Index: 1, Dependencies: []int{0}}, {Index: 2},
+			},
+			expected: []int{0, 2},
+		},
+		{
+			name: "dependency satisfied after completion",
+			steps: []workflow.PlanStep{
+				{Index: 0}, {Index: 1, Dependencies: []int{0}},
+			},
+			done:     []int{0},
+			expected: []int{1},
+		},
+		{
+			name: "unknown and self dependencies are ignored",
+			steps: []workflow.PlanStep{
+				{Index: 0, Dependencies: []int{0, 7}},
+			},
+			expected: []int{0},
+		},
+		{
+			name: "cycle falls back to first pending step",
+			steps: []workflow.PlanStep{
+				{Index: 0, Dependencies: []int{1}}, {Index: 1, Dependencies: []int{0}},
+			},
+			expected: []int{0},
+		},
+		{
+			name: "complete plan has nothing ready",
+			steps: []workflow.PlanStep{
+				{Index: 0},
+			},
+			done:     []int{0},
+			expected: []int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := workflow.NewState("test")
+			state.Plan = &workflow.Plan{Steps: tt.steps}
+			for _, idx := range tt.done {
+				state.AddPastStep(workflow.PastStep{Step: tt.steps[idx]})
+			}
+
+			ready := state.ReadySteps()
+			if len(ready) != len(tt.expected) {
+				t.Fatalf("ReadySteps() = %v, want %v", ready, tt.expected)
+			}
+			for i := range ready {
+				if ready[i] != tt.expected[i] {
+					t.Errorf("ReadySteps() = %v, want %v", ready, tt.expected)
+					break
+				}
+			}
+		})
+	}
+
+	t.Run("nil plan", func(t *testing.T) {
+		if ready := workflow.NewState("test").ReadySteps(); len(ready) != 0 {
+			t.Errorf("ReadySteps() = %v, want empty", ready)
+		}
+	})
+}
+
 // TestExecutor_RouteNext tests the routeNext logic indirectly through Execute
 func TestExecutor_RouteNext(t *testing.T) {
 	ctx := context.Background()
@@ -990,6 +2115,7 @@ func TestBuildDeepThinkingGraph(t *testing.T) {
 	nodes["distiller"] = &mockNode{name: "distiller"}
 	nodes["reflector"] = &mockNode{name: "reflector"}
 	nodes["policy"] = &mockNode{name: "policy"}
+	nodes["synthesizer"] = &mockNode{name: "synthesizer"}
 
 	t.Run("builds graph with all nodes", func(t *testing.T) {
 		graph, err := workflow.BuildDeepThinkingGraph(nodes)
@@ -1017,6 +2143,24 @@ func TestBuildDeepThinkingGraph(t *testing.T) {
 			t.Errorf("expected start node 'planner', got %s", graph.GetStartNode())
 		}
 
+		// Verify finish node is synthesizer
+		if graph.GetFinishNode() != "synthesizer" {
+			t.Errorf("expected finish node 'synthesizer', got %s", graph.GetFinishNode())
+		}
+
+		// Verify the per-step pipeline
+		stepNodes := graph.GetStepNodes()
+		expectedSteps := []string{"rewriter", "supervisor", "retriever", "reranker", "distiller", "reflector"}
+		if len(stepNodes) != len(expectedSteps) {
+			t.Fatalf("GetStepNodes() = %v, want %v", stepNodes, expectedSteps)
+		}
+		for i := range expectedSteps {
+			if stepNodes[i] != expectedSteps[i] {
+				t.Errorf("GetStepNodes() = %v, want %v", stepNodes, expectedSteps)
+				break
+			}
+		}
+
 		// Verify key edges exist
 		expectedEdges := map[string]string{
 			"planner":    "rewriter",
@@ -1042,6 +2186,65 @@ func TestBuildDeepThinkingGraph(t *testing.T) {
 				t.Errorf("expected edge from %s to %s not found", from, expectedTo)
 			}
 		}
+
+		// Policy can also send the run back to the planner to replan
+		if next := graph.GetNextNodes("policy"); !reflect.DeepEqual(next, []string{"rewriter", "synthesizer", "planner"}) {
+			t.Errorf("GetNextNodes(policy) = %v", next)
+		}
+	})
+
+	t.Run("policy routing", func(t *testing.T) {
+		executionOrder := []string{}
+		decisions := []workflow.PolicyDecision{
+			{ShouldContinue: true, Replan: true},
+			{ShouldContinue: true},
+			{ShouldContinue: false},
+		}
+
+		routed := make(map[string]workflow.Node, len(nodes))
+		for name := range nodes {
+			name := name
+			routed[name] = &mockNode{
+				name: name,
+				executeFunc: func(state *workflow.State) (*workflow.NodeResult, error) {
+					executionOrder = append(executionOrder, name)
+					switch name {
+					case "planner":
+						if state.Plan == nil {
+							state.Plan = &workflow.Plan{Steps: []workflow.PlanStep{{Index: 0}, {Index: 1}, {Index: 2}}}
+						}
+					case "reflector":
+						state.AddPastStep(workflow.PastStep{Step: *state.CurrentStep()})
+						state.IncrementStep()
+					case "policy":
+						decision := decisions[0]
+						decisions = decisions[1:]
+						state.Decision = &decision
+						state.ShouldContinue = decision.ShouldContinue
+					}
+					return &workflow.NodeResult{UpdatedState: state}, nil
+				},
+			}
+		}
+
+		graph, err := workflow.BuildDeepThinkingGraph(routed)
+		if err != nil {
+			t.Fatalf("BuildDeepThinkingGraph() failed: %v", err)
+		}
+		if _, err := workflow.NewExecutor(graph, nil).Execute(context.Background(), workflow.NewState("test")); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+
+		step := []string{"rewriter", "supervisor", "retriever", "reranker", "distiller", "reflector", "policy"}
+		want := []string{"planner"}
+		want = append(want, step...)
+		want = append(want, "planner") // replan
+		want = append(want, step...)
+		want = append(want, step...)
+		want = append(want, "synthesizer")
+		if !reflect.DeepEqual(executionOrder, want) {
+			t.Errorf("execution order = %v, want %v", executionOrder, want)
+		}
 	})
 
 	t.Run("missing node returns error", func(t *testing.T) {
@@ -1080,6 +2283,7 @@ func TestBuildDeepThinkingGraph(t *testing.T) {
 		badNodes["distiller"] = &mockNode{name: "distiller"}
 		badNodes["reflector"] = &mockNode{name: "reflector"}
 		badNodes["policy"] = &mockNode{name: "policy"}
+		badNodes["synthesizer"] = &mockNode{name: "synthesizer"}
 
 		_, err := workflow.BuildDeepThinkingGraph(badNodes)
 		if err == nil {
@@ -1088,7 +2292,7 @@ func TestBuildDeepThinkingGraph(t *testing.T) {
 	})
 
 	t.Run("verify all required nodes are present", func(t *testing.T) {
-		requiredNodes := []string{"planner", "rewriter", "supervisor", "retriever", "reranker", "distiller", "reflector", "policy"}
+		requiredNodes := []string{"planner", "rewriter", "supervisor", "retriever", "reranker", "distiller", "reflector", "policy", "synthesizer"}
 
 		for _, missingNode := range requiredNodes {
 			t.Run("missing_"+missingNode, func(t *testing.T) {